@@ -0,0 +1,455 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"vc_file_grouper/handler"
+	"vc_file_grouper/vc"
+)
+
+// command is a headless sub command that can be run instead of the web server
+type command func(langPack string, args []string) error
+
+// commands lists the sub commands by name
+var commands = map[string]command{
+	"export":    exportCommand,
+	"decode":    decodeCommand,
+	"encode":    encodeCommand,
+	"strb":      strbCommand,
+	"translate": translateCommand,
+	"zip":       zipCommand,
+	"wiki":      wikiCommand,
+	"diff":      diffCommand,
+	"validate":  validateCommand,
+}
+
+// exporters lists the available exports by name and format
+var exporters = map[string]map[string]func(io.Writer, *vc.Dataset) error{
+	"cards": {
+		"csv": handler.WriteCardCsv,
+	},
+	"glrcards": {
+		"csv":  handler.WriteCardGLRCsv,
+		"json": handler.WriteCardJSONStat,
+	},
+	"skills": {
+		"csv": handler.WriteSkillCsv,
+	},
+	"awakenings": {
+		"csv": handler.WriteAwakeningsCsv,
+	},
+}
+
+// runCommand runs the named sub command and returns the exit code for the program
+func runCommand(cmd command, langPack string, args []string) int {
+	err := cmd(langPack, args)
+	if err != nil {
+		os.Stderr.WriteString(err.Error() + "\n")
+		return 1
+	}
+	return 0
+}
+
+// exportCommand writes one of the data exports to stdout or a file
+// usage: export <cards|glrcards|skills|awakenings> [-format csv] [-o file] [data path]
+func exportCommand(langPack string, args []string) error {
+	if len(args) < 1 {
+		return errors.New("export: missing export type")
+	}
+	formats, ok := exporters[args[0]]
+	if !ok {
+		return fmt.Errorf("export: unknown export type '%s'", args[0])
+	}
+
+	fs := flag.NewFlagSet("export "+args[0], flag.ContinueOnError)
+	format := fs.String("format", "csv", "The output format. 'csv' or 'json'")
+	out := fs.String("o", "", "The output file. Defaults to the standard output")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	export, ok := formats[*format]
+	if !ok {
+		return fmt.Errorf("export: format '%s' is not supported for %s", *format, args[0])
+	}
+
+	ds, err := loadData(fs.Args(), langPack)
+	if err != nil {
+		return err
+	}
+	return writeOutput(*out, func(w io.Writer) error {
+		return export(w, ds)
+	})
+}
+
+// decodeCommand decodes all the encoded files in the data path into a separate folder
+// usage: decode [-o dir] [-workers n] [-force] [data path]
+func decodeCommand(langPack string, args []string) error {
+	fs := flag.NewFlagSet("decode", flag.ContinueOnError)
+	out := fs.String("o", handler.DecodeDir, "The folder to write the decoded files to")
+	workers := fs.Int("workers", 0, "The number of files to decode at the same time. Defaults to the number of CPUs")
+	force := fs.Bool("force", false, "Decode the files again even if they are up to date")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	decoder := vc.BulkDecoder{
+		Root:    dataPath(fs.Args()),
+		OutDir:  *out,
+		Workers: *workers,
+		Force:   *force,
+	}
+	summary, err := decoder.Run(func(res vc.DecodeResult) {
+		if res.Skipped {
+			return
+		}
+		if res.Err != nil {
+			fmt.Fprintf(os.Stderr, "Decoding: %s : ERROR: %s\n", res.File, res.Err.Error())
+			return
+		}
+		fmt.Fprintf(os.Stdout, "Decoding: %s : %s\n", res.File, res.NewFile)
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stdout, "%d decoded, %d already up to date, %d failed\n", summary.Decoded, summary.Skipped, len(summary.Failed))
+	if len(summary.Failed) > 0 {
+		return fmt.Errorf("decode: %d files could not be decoded", len(summary.Failed))
+	}
+	return nil
+}
+
+// encodeCommand encodes a changed JSON or PNG file so the game can read it again
+// usage: encode [-like original] -o file <decoded file>
+func encodeCommand(langPack string, args []string) error {
+	fs := flag.NewFlagSet("encode", flag.ContinueOnError)
+	out := fs.String("o", "", "The encoded file to write")
+	like := fs.String("like", "", "An encoded file to copy the header and key from. Defaults to the output file if it is already encoded")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return errors.New("encode: missing the file to encode")
+	}
+	if *out == "" {
+		return errors.New("encode: the output file is required")
+	}
+	original := *like
+	if original == "" {
+		if encoded, err := vc.IsFileEncoded(*out); err == nil && encoded {
+			original = *out
+		}
+	}
+	return vc.EncodeAndSave(fs.Arg(0), original, *out)
+}
+
+// strbCommand exports the strings of a .strb file as JSON, or writes a .strb file with changed strings
+// usage: strb export [-o file] <file.strb>
+// usage: strb import [-o file] <file.strb> <strings.json>
+func strbCommand(langPack string, args []string) error {
+	if len(args) < 1 || (args[0] != "export" && args[0] != "import") {
+		return errors.New("strb: only the 'export' and 'import' commands are supported")
+	}
+	fs := flag.NewFlagSet("strb "+args[0], flag.ContinueOnError)
+	out := fs.String("o", "", "The output file. Defaults to the standard output")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return errors.New("strb: missing the .strb file")
+	}
+	sf, err := vc.ReadStrb(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if args[0] == "export" {
+		return writeOutput(*out, func(w io.Writer) error {
+			b, err := json.MarshalIndent(sf.Strings, "", "\t")
+			if err != nil {
+				return err
+			}
+			_, err = w.Write(append(b, '\n'))
+			return err
+		})
+	}
+
+	if fs.NArg() < 2 {
+		return errors.New("strb import: missing the JSON file with the strings")
+	}
+	data, err := ioutil.ReadFile(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	strs := make([]string, 0)
+	if err = json.Unmarshal(data, &strs); err != nil {
+		return fmt.Errorf("strb import: %s should be a JSON list of strings: %s", fs.Arg(1), err.Error())
+	}
+	if len(strs) != len(sf.Strings) {
+		return fmt.Errorf("strb import: %s has %d strings, expected %d", fs.Arg(1), len(strs), len(sf.Strings))
+	}
+	sf.Strings = strs
+	return writeOutput(*out, func(w io.Writer) error {
+		_, err := sf.WriteTo(w)
+		return err
+	})
+}
+
+// translateCommand exports string files for translators as PO or XLIFF, or writes the translated string files
+// usage: translate export -target lang [-source lang] [-format po|xliff] [-file MsgCardName] [-o file] [data path]
+// usage: translate import [-o dir] <file.po|file.xlf> [data path]
+func translateCommand(langPack string, args []string) error {
+	if len(args) < 1 || (args[0] != "export" && args[0] != "import") {
+		return errors.New("translate: only the 'export' and 'import' commands are supported")
+	}
+	fs := flag.NewFlagSet("translate "+args[0], flag.ContinueOnError)
+	if args[0] == "export" {
+		source := fs.String("source", langPack, "The language to translate from")
+		target := fs.String("target", "", "The language to translate to, e.g. zhs")
+		format := fs.String("format", "po", "The output format. 'po' or 'xliff'")
+		file := fs.String("file", "", "A string file to export, e.g. MsgCardName. Defaults to all the Msg*.strb files")
+		out := fs.String("o", "", "The output file. Defaults to the standard output")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *target == "" {
+			return errors.New("translate export: the target language is required")
+		}
+		var write func(io.Writer, *vc.TranslationCatalog) error
+		switch *format {
+		case "po":
+			write = vc.WritePO
+		case "xliff":
+			write = vc.WriteXLIFF
+		default:
+			return fmt.Errorf("translate export: format '%s' is not supported", *format)
+		}
+		ds, err := loadData(fs.Args(), *source)
+		if err != nil {
+			return err
+		}
+		names := make([]string, 0, 1)
+		if *file != "" {
+			names = append(names, strings.TrimSuffix(*file, "_"+*source+".strb"))
+		}
+		cat, err := ds.TranslationCatalog(*source, *target, names...)
+		if err != nil {
+			return err
+		}
+		return writeOutput(*out, func(w io.Writer) error {
+			return write(w, cat)
+		})
+	}
+
+	out := fs.String("o", "", "The folder to write the string files to. Defaults to the string folder of the data location")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return errors.New("translate import: missing the PO or XLIFF file")
+	}
+	read := vc.ReadPO
+	switch strings.ToLower(filepath.Ext(fs.Arg(0))) {
+	case ".po":
+	case ".xlf", ".xliff":
+		read = vc.ReadXLIFF
+	default:
+		return fmt.Errorf("translate import: %s is not a .po or .xlf file", fs.Arg(0))
+	}
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	cat, err := read(f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	path := dataPath(fs.Args()[1:])
+	if *out == "" {
+		*out = filepath.Join(path, "string")
+	}
+	files, skipped, err := vc.NewDataset(path, cat.SourceLang).ImportTranslations(cat, *out)
+	for _, u := range skipped {
+		fmt.Fprintf(os.Stderr, "Skipped %s: the %s text changed since the export\n", u.Key(), cat.SourceLang)
+	}
+	for _, file := range files {
+		fmt.Fprintf(os.Stdout, "Wrote %s\n", file)
+	}
+	return err
+}
+
+// zipCommand writes the fan archive zip to stdout or a file
+// usage: zip [-o file] [data path]
+func zipCommand(langPack string, args []string) error {
+	fs := flag.NewFlagSet("zip", flag.ContinueOnError)
+	out := fs.String("o", "", "The output file. Defaults to the standard output")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ds, err := loadData(fs.Args(), langPack)
+	if err != nil {
+		return err
+	}
+	return writeOutput(*out, func(w io.Writer) error {
+		return handler.WriteZipData(w, ds)
+	})
+}
+
+// wikiCommand runs wiki related sub commands
+// usage: wiki diff [-o file] <card id> [data path]
+func wikiCommand(langPack string, args []string) error {
+	if len(args) < 1 || args[0] != "diff" {
+		return errors.New("wiki: only the 'diff' command is supported")
+	}
+	fs := flag.NewFlagSet("wiki diff", flag.ContinueOnError)
+	out := fs.String("o", "", "The output file. Defaults to the standard output")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return errors.New("wiki diff: missing card id")
+	}
+	cardID, err := strconv.Atoi(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("wiki diff: invalid card id '%s'", fs.Arg(0))
+	}
+	ds, err := loadData(fs.Args()[1:], langPack)
+	if err != nil {
+		return err
+	}
+	card := ds.CardScan(cardID)
+	if card == nil {
+		return fmt.Errorf("wiki diff: card %d not found", cardID)
+	}
+	return writeOutput(*out, func(w io.Writer) error {
+		diff, err := handler.WikiCardDiff(card)
+		if err != nil {
+			return err
+		}
+		b, err := json.MarshalIndent(diff, "", "\t")
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(b, '\n'))
+		return err
+	})
+}
+
+// diffCommand writes the changes between two versions of the master data
+// usage: diff [-format html|json] [-o file] <old data path> <new data path>
+func diffCommand(langPack string, args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	format := fs.String("format", "json", "The output format. 'html' or 'json'")
+	out := fs.String("o", "", "The output file. Defaults to the standard output")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		return errors.New("diff: the old and new data locations are required")
+	}
+	var write func(io.Writer, *vc.MasterDiff) error
+	switch *format {
+	case "html":
+		write = handler.WriteMasterDiffHTML
+	case "json":
+		write = handler.WriteMasterDiffJSON
+	default:
+		return fmt.Errorf("diff: format '%s' is not supported", *format)
+	}
+	diff, err := vc.DiffMasterData(filepath.Clean(fs.Arg(0)), filepath.Clean(fs.Arg(1)), langPack)
+	if err != nil {
+		return err
+	}
+	return writeOutput(*out, func(w io.Writer) error {
+		return write(w, diff)
+	})
+}
+
+// validateCommand checks the references between the master data tables. The report is written
+// either way, and the command fails if any references are broken so it can be used in scripts
+// usage: validate [-format text|html|json] [-o file] [data path]
+func validateCommand(langPack string, args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	format := fs.String("format", "text", "The output format. 'text', 'html' or 'json'")
+	out := fs.String("o", "", "The output file. Defaults to the standard output")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	var write func(io.Writer, *vc.IntegrityReport) error
+	switch *format {
+	case "text":
+		write = writeIntegrityText
+	case "html":
+		write = handler.WriteIntegrityHTML
+	case "json":
+		write = handler.WriteIntegrityJSON
+	default:
+		return fmt.Errorf("validate: format '%s' is not supported", *format)
+	}
+	ds, err := loadData(fs.Args(), langPack)
+	if err != nil {
+		return err
+	}
+	report := ds.ValidateIntegrity()
+	if err := writeOutput(*out, func(w io.Writer) error {
+		return write(w, report)
+	}); err != nil {
+		return err
+	}
+	if !report.IsEmpty() {
+		return fmt.Errorf("validate: found %d broken references", len(report.Issues))
+	}
+	return nil
+}
+
+// writeIntegrityText writes the integrity report with one problem per line
+func writeIntegrityText(w io.Writer, report *vc.IntegrityReport) error {
+	for _, category := range report.Categories() {
+		for _, issue := range report.Category(category) {
+			fmt.Fprintf(w, "%s: %s %d %s: %s\n", category, issue.Table, issue.RecordID, issue.Field, issue.Problem)
+		}
+	}
+	_, err := fmt.Fprintf(w, "Checked %d references and found %d problems\n", report.Checked, len(report.Issues))
+	return err
+}
+
+// dataPath gets the VC data location from the first remaining argument
+func dataPath(args []string) string {
+	if len(args) == 0 {
+		return "."
+	}
+	return filepath.Clean(args[0])
+}
+
+// loadData reads the master data from the data location in the first remaining argument
+func loadData(args []string, langPack string) (*vc.Dataset, error) {
+	path := dataPath(args)
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	return vc.LoadDataset(path, langPack)
+}
+
+// writeOutput calls the writer function with the named file, or stdout if the name is blank
+func writeOutput(fileName string, write func(io.Writer) error) error {
+	if fileName == "" {
+		return write(os.Stdout)
+	}
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	err = write(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"vc_file_grouper/structout"
+	"vc_file_grouper/vc"
+)
+
+// default and maximum page sizes for API list results
+const (
+	apiDefaultLimit = 100
+	apiMaxLimit     = 1000
+)
+
+// apiResource describes a list of records that can be served by the API
+type apiResource struct {
+	// number of records available
+	len func(ds *vc.Dataset) int
+	// id of the record at position i
+	id func(ds *vc.Dataset, i int) int
+	// the record at position i with its relationships resolved
+	item func(ds *vc.Dataset, i int) interface{}
+}
+
+// apiPage is a page of results from the API
+type apiPage struct {
+	Total  int           `json:"total"`
+	Offset int           `json:"offset"`
+	Limit  int           `json:"limit"`
+	Items  []interface{} `json:"items"`
+}
+
+// apiError is returned by the API when a request can not be completed
+type apiError struct {
+	Error string `json:"error"`
+}
+
+var apiResources = map[string]apiResource{
+	"cards": {
+		len:  func(ds *vc.Dataset) int { return len(ds.Data.Cards) },
+		id:   func(ds *vc.Dataset, i int) int { return ds.Data.Cards[i].ID },
+		item: func(ds *vc.Dataset, i int) interface{} { return structout.ToAPICard(ds.Data.Cards[i]) },
+	},
+	"characters": {
+		len:  func(ds *vc.Dataset) int { return len(ds.Data.CardCharacters) },
+		id:   func(ds *vc.Dataset, i int) int { return ds.Data.CardCharacters[i].ID },
+		item: func(ds *vc.Dataset, i int) interface{} { return structout.ToAPICharacter(&ds.Data.CardCharacters[i]) },
+	},
+	"skills": {
+		len:  func(ds *vc.Dataset) int { return len(ds.Data.Skills) },
+		id:   func(ds *vc.Dataset, i int) int { return ds.Data.Skills[i].ID },
+		item: func(ds *vc.Dataset, i int) interface{} { return structout.ToAPISkill(&ds.Data.Skills[i]) },
+	},
+	"events": {
+		len:  func(ds *vc.Dataset) int { return len(ds.Data.Events) },
+		id:   func(ds *vc.Dataset, i int) int { return ds.Data.Events[i].ID },
+		item: func(ds *vc.Dataset, i int) interface{} { return structout.ToAPIEvent(&ds.Data.Events[i]) },
+	},
+	"archwitches": {
+		len:  func(ds *vc.Dataset) int { return len(ds.Data.Archwitches) },
+		id:   func(ds *vc.Dataset, i int) int { return ds.Data.Archwitches[i].ID },
+		item: func(ds *vc.Dataset, i int) interface{} { return structout.ToAPIArchwitch(ds.Data.Archwitches[i]) },
+	},
+	"weapons": {
+		len:  func(ds *vc.Dataset) int { return len(ds.Data.Weapons) },
+		id:   func(ds *vc.Dataset, i int) int { return ds.Data.Weapons[i].ID },
+		item: func(ds *vc.Dataset, i int) interface{} { return structout.ToAPIWeapon(&ds.Data.Weapons[i]) },
+	},
+	"items": {
+		len:  func(ds *vc.Dataset) int { return len(ds.Data.Items) },
+		id:   func(ds *vc.Dataset, i int) int { return ds.Data.Items[i].ID },
+		item: func(ds *vc.Dataset, i int) interface{} { return structout.ToAPIItem(&ds.Data.Items[i]) },
+	},
+	"structures": {
+		len:  func(ds *vc.Dataset) int { return len(ds.Data.Structures) },
+		id:   func(ds *vc.Dataset, i int) int { return ds.Data.Structures[i].ID },
+		item: func(ds *vc.Dataset, i int) interface{} { return structout.ToAPIStructure(&ds.Data.Structures[i]) },
+	},
+	"deckbonuses": {
+		len:  func(ds *vc.Dataset) int { return len(ds.Data.DeckBonuses) },
+		id:   func(ds *vc.Dataset, i int) int { return ds.Data.DeckBonuses[i].ID },
+		item: func(ds *vc.Dataset, i int) interface{} { return structout.ToAPIDeckBonus(&ds.Data.DeckBonuses[i]) },
+	},
+	"thor": {
+		len:  func(ds *vc.Dataset) int { return len(ds.Data.ThorEvents) },
+		id:   func(ds *vc.Dataset, i int) int { return ds.Data.ThorEvents[i].ID },
+		item: func(ds *vc.Dataset, i int) interface{} { return structout.ToAPIThorEvent(&ds.Data.ThorEvents[i]) },
+	},
+}
+
+// APIHandler serves the JSON API.
+//  /api/v1/<resource>/ lists records. Use ?id=1,2,3 to filter and ?offset=&limit= to page
+//  /api/v1/<resource>/<id> returns a single record
+func APIHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
+	path := r.URL.Path
+	var pathLen int
+	if path[len(path)-1] == '/' {
+		pathLen = len(path) - 1
+	} else {
+		pathLen = len(path)
+	}
+
+	pathParts := strings.Split(path[1:pathLen], "/")
+	// "api/v1/resource/id"
+	if len(pathParts) < 3 || pathParts[1] != "v1" {
+		writeAPIError(w, http.StatusNotFound, "unknown API version")
+		return
+	}
+	resource, ok := apiResources[pathParts[2]]
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, "unknown resource "+pathParts[2])
+		return
+	}
+
+	if len(pathParts) > 3 {
+		id, err := strconv.Atoi(pathParts[3])
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, "invalid id "+pathParts[3])
+			return
+		}
+		for i, l := 0, resource.len(ds); i < l; i++ {
+			if resource.id(ds, i) == id {
+				writeAPIJSON(w, http.StatusOK, resource.item(ds, i))
+				return
+			}
+		}
+		writeAPIError(w, http.StatusNotFound, "no record with id "+pathParts[3])
+		return
+	}
+
+	qs := r.URL.Query()
+	offset, err := apiIntParam(qs.Get("offset"), 0)
+	if err != nil || offset < 0 {
+		writeAPIError(w, http.StatusBadRequest, "invalid offset "+qs.Get("offset"))
+		return
+	}
+	limit, err := apiIntParam(qs.Get("limit"), apiDefaultLimit)
+	if err != nil || limit < 1 || limit > apiMaxLimit {
+		writeAPIError(w, http.StatusBadRequest, "invalid limit "+qs.Get("limit"))
+		return
+	}
+	ids := make(map[int]bool)
+	for _, qID := range qs["id"] {
+		for _, sID := range strings.Split(qID, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(sID))
+			if err != nil {
+				writeAPIError(w, http.StatusBadRequest, "invalid id "+sID)
+				return
+			}
+			ids[id] = true
+		}
+	}
+
+	page := apiPage{
+		Offset: offset,
+		Limit:  limit,
+		Items:  make([]interface{}, 0),
+	}
+	for i, l := 0, resource.len(ds); i < l; i++ {
+		if len(ids) > 0 && !ids[resource.id(ds, i)] {
+			continue
+		}
+		if page.Total >= offset && len(page.Items) < limit {
+			page.Items = append(page.Items, resource.item(ds, i))
+		}
+		page.Total++
+	}
+	writeAPIJSON(w, http.StatusOK, page)
+}
+
+func apiIntParam(value string, defaultValue int) (int, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	return strconv.Atoi(value)
+}
+
+func writeAPIError(w http.ResponseWriter, status int, msg string) {
+	writeAPIJSON(w, status, apiError{Error: msg})
+}
+
+func writeAPIJSON(w http.ResponseWriter, status int, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("API encoding error: %s", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(b)
+}
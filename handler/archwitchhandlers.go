@@ -10,12 +10,12 @@ import (
 )
 
 // ArchwitchHandler displays archwitch data as a table.
-func ArchwitchHandler(w http.ResponseWriter, r *http.Request) {
+func ArchwitchHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	io.WriteString(w, "<html><head><title>All Archwitches</title></head><body>\n")
 	io.WriteString(w, "<table><thead><tr><th>Series ID</th><th>Reward Card Name</th><th>Event Start</th><th>Event End</th><th>Recieve Limit</th><th>Is Beginner</th></tr></thead><tbody>\n")
-	for i := len(vc.Data.ArchwitchSeries) - 1; i >= 0; i-- {
-		series := vc.Data.ArchwitchSeries[i]
-		rewardCard := vc.CardScan(series.RewardCardID)
+	for i := len(ds.Data.ArchwitchSeries) - 1; i >= 0; i-- {
+		series := ds.Data.ArchwitchSeries[i]
+		rewardCard := ds.CardScan(series.RewardCardID)
 		fmt.Fprintf(w,
 			"<tr><td>%d</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%d</td></tr>",
 			series.ID,
@@ -29,9 +29,9 @@ func ArchwitchHandler(w http.ResponseWriter, r *http.Request) {
 		io.WriteString(w, "\n<tr><td></td><td></td><td colspan=5><table border=1>")
 		io.WriteString(w, "<thead><tr><th>ID</th><th>Card Master / servants</th><th>Skill 1</th><th>Skill 2</th><th>Status Group</th><th>Public</th><th>Rarity</th><th>RareIntensity</th><th>Battle Time</th><th>Exp</th><th>Max Friendship</th><th>Weather</th><th>Model</th><th>Chain Ratio 2</th><th>Likability</th></tr></thead><tbody>")
 		for _, aw := range series.Archwitches() {
-			cardMaster := vc.CardScan(aw.CardMasterID)
-			skill1 := vc.SkillScan(aw.SkillID1)
-			skill2 := vc.SkillScan(aw.SkillID2)
+			cardMaster := ds.CardScan(aw.CardMasterID)
+			skill1 := ds.SkillScan(aw.SkillID1)
+			skill2 := ds.SkillScan(aw.SkillID2)
 			//servant1 := vc.CardScanCharacter(aw.ServantID1)
 			//servant2 := vc.CardScanCharacter(aw.ServantID2)
 			fmt.Fprintf(w,
@@ -4,6 +4,7 @@ import (
 	"encoding/csv"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"strconv"
 	"time"
@@ -12,12 +13,12 @@ import (
 )
 
 // AwakeningsTableHandler displays awakening data as a table
-func AwakeningsTableHandler(w http.ResponseWriter, r *http.Request) {
+func AwakeningsTableHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	io.WriteString(w, "<html><head><title>All Awakenings</title></head><body>\n")
 	io.WriteString(w, "<table><thead><tr><th>From Card</th><th>To Card</th><th>Chance</th><th>Crystals</th><th>Orb</th><th>Large</th><th>Medium</th><th>Small</th><th>closed</th></tr></thead><tbody>\n")
-	for _, value := range vc.Data.Awakenings {
-		baseCard := vc.CardScan(value.BaseCardID)
-		resultCard := vc.CardScan(value.ResultCardID)
+	for _, value := range ds.Data.Awakenings {
+		baseCard := ds.CardScan(value.BaseCardID)
+		resultCard := ds.CardScan(value.ResultCardID)
 		fmt.Fprintf(w,
 			"<tr><td><img src=\"/images/cardthumb/%s\"/><br /><a href=\"/cards/detail/%d\">%s</a></td><td><img src=\"/images/cardthumb/%s\"/><br /><a href=\"/cards/detail/%d\">%s</a></td><td>%d%%</td><td>%d</td><td>%d</td><td>%d</td><td>%d</td><td>%d</td><td>%v</td></tr>",
 			baseCard.Image(),
@@ -40,10 +41,17 @@ func AwakeningsTableHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // AwakeningsCsvHandler downloads awakening data as a CSV
-func AwakeningsCsvHandler(w http.ResponseWriter, r *http.Request) {
+func AwakeningsCsvHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	// File header
-	w.Header().Set("Content-Disposition", "attachment; filename=vcData-awaken-"+strconv.Itoa(vc.Data.Version)+"_"+vc.Data.Common.UnixTime.Format(time.RFC3339)+".csv")
+	w.Header().Set("Content-Disposition", "attachment; filename=vcData-awaken-"+strconv.Itoa(ds.Data.Version)+"_"+ds.Data.Common.UnixTime.Format(time.RFC3339)+".csv")
 	w.Header().Set("Content-Type", "text/csv")
+	if err := WriteAwakeningsCsv(w, ds); err != nil {
+		log.Printf(err.Error() + "\n")
+	}
+}
+
+// WriteAwakeningsCsv writes all the awakenings as a CSV doc
+func WriteAwakeningsCsv(w io.Writer, ds *vc.Dataset) error {
 	cw := csv.NewWriter(w)
 	cw.Write([]string{
 		"ID",
@@ -64,8 +72,8 @@ func AwakeningsCsvHandler(w http.ResponseWriter, r *http.Request) {
 		"Order",
 		"IsClosed",
 	})
-	for _, value := range vc.Data.Awakenings {
-		baseCard := vc.CardScan(value.BaseCardID)
+	for _, value := range ds.Data.Awakenings {
+		baseCard := ds.CardScan(value.BaseCardID)
 		cw.Write([]string{
 			strconv.Itoa(value.ID),
 			baseCard.Name,
@@ -87,4 +95,5 @@ func AwakeningsCsvHandler(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 	cw.Flush()
+	return cw.Error()
 }
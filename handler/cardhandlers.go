@@ -22,9 +22,9 @@ import (
 )
 
 // CardHandler shows cards in order
-func CardHandler(w http.ResponseWriter, r *http.Request) {
+func CardHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	io.WriteString(w, "<html><head><title>All Cards</title></head><body>\n")
-	for _, card := range vc.Data.Cards {
+	for _, card := range ds.Data.Cards {
 		fmt.Fprintf(w,
 			"<div style=\"float: left; margin: 3px\"><img src=\"/images/cardthumb/%s\"/><br /><a href=\"/cards/detail/%d\">%s</a></div>",
 			card.Image(),
@@ -35,7 +35,7 @@ func CardHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // CardLevelHandler shows card level information
-func CardLevelHandler(w http.ResponseWriter, r *http.Request) {
+func CardLevelHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	header := `{| class="article-table" style="float:left"
 !Lvl!!To Next Lvl!!Total Needed`
 	header2 := `{| class="article-table" style="float:left"
@@ -67,23 +67,24 @@ func CardLevelHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	io.WriteString(w, "<html><head><title>Card Levels</title></head><body>\n")
+	io.WriteString(w, "<a href=\"/cards/levelup/\">Level Up Calculator</a><br />\n")
 	io.WriteString(w, "\nN-GUR<br/><textarea rows=\"25\" cols=\"80\">")
-	genLevels(vc.Data.CardLevels)
+	genLevels(ds.Data.CardLevels)
 	io.WriteString(w, "</textarea>")
 	io.WriteString(w, "\n<br />XSR and XUR<br/><textarea rows=\"25\" cols=\"80\">")
-	genLevels(vc.Data.CardLevelsX)
+	genLevels(ds.Data.CardLevelsX)
 	io.WriteString(w, "</textarea>")
 	io.WriteString(w, "\n<br />LR-GLR<br/><textarea rows=\"25\" cols=\"80\">")
-	genLevels(vc.Data.CardLevelsLR)
+	genLevels(ds.Data.CardLevelsLR)
 	io.WriteString(w, "</textarea>")
 	io.WriteString(w, "\n<br />XLR<br/><textarea rows=\"25\" cols=\"80\">")
-	genLevels(vc.Data.CardLevelsXLR)
+	genLevels(ds.Data.CardLevelsXLR)
 	io.WriteString(w, "</textarea>")
 	io.WriteString(w, "\n<br />LR Resources<br/><textarea rows=\"25\" cols=\"80\">")
 
 	io.WriteString(w, header2)
-	l := len(vc.Data.LevelLRResources)
-	for i, lvl := range vc.Data.LevelLRResources {
+	l := len(ds.Data.LevelLRResources)
+	for i, lvl := range ds.Data.LevelLRResources {
 		fmt.Fprintf(w, `
 |-
 |%d||%d`,
@@ -100,8 +101,8 @@ func CardLevelHandler(w http.ResponseWriter, r *http.Request) {
 	io.WriteString(w, "\n<br />XSR & XUR Resources<br/><textarea rows=\"25\" cols=\"80\">")
 
 	io.WriteString(w, header3)
-	l = len(vc.Data.LevelXResources)
-	for i, lvl := range vc.Data.LevelXResources {
+	l = len(ds.Data.LevelXResources)
+	for i, lvl := range ds.Data.LevelXResources {
 		fmt.Fprintf(w, `
 |-
 |%d||%d||%d||%d||%d`,
@@ -121,8 +122,8 @@ func CardLevelHandler(w http.ResponseWriter, r *http.Request) {
 	io.WriteString(w, "\n<br />XLR Resources<br/><textarea rows=\"25\" cols=\"80\">")
 
 	io.WriteString(w, header3)
-	l = len(vc.Data.LevelXLRResources)
-	for i, lvl := range vc.Data.LevelXLRResources {
+	l = len(ds.Data.LevelXLRResources)
+	for i, lvl := range ds.Data.LevelXLRResources {
 		fmt.Fprintf(w, `
 |-
 |%d||%d||%d||%d||%d`,
@@ -142,7 +143,7 @@ func CardLevelHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // CardDetailHandler shows details of a single card formatted for use in the Wiki
-func CardDetailHandler(w http.ResponseWriter, r *http.Request) {
+func CardDetailHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	path := r.URL.Path
 	var pathLen int
 	if path[len(path)-1] == '/' {
@@ -163,7 +164,7 @@ func CardDetailHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	card := vc.CardScan(cardID)
+	card := ds.CardScan(cardID)
 	if card == nil {
 		http.Error(w, "Invalid card id "+pathParts[2]+"\nCard not found.", http.StatusNotFound)
 		return
@@ -238,6 +239,9 @@ func CardDetailHandler(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintf(w, "<div style=\"clear:both;float:left\">Edit on the <a href=\"https://valkyriecrusade.fandom.com/wiki/%s?action=edit\">fandom</a>\n<br /></div>", cardName)
 	fmt.Fprintf(w, "<div style=\"clear:left;float:left\"><a href=\"?action=uploadImages\">Upload Missing Images</a>\n<br /></div>")
 	fmt.Fprintf(w, "<div style=\"float:left;padding-left:15px;\"><a href=\"?action=createOnWiki\">Create New Wiki Page</a></div>")
+	fmt.Fprintf(w, "<div style=\"float:left;padding-left:15px;\"><a href=\"/cards/planner/?card=%d\">Plan the Evolution</a></div>", card.ID)
+	fmt.Fprintf(w, "<div style=\"float:left;padding-left:15px;\"><a href=\"/cards/cost/?card=%d\">Total Cost</a></div>", card.ID)
+	fmt.Fprintf(w, "<div style=\"float:left;padding-left:15px;\"><a href=\"/cards/skillcost/?card=%d\">Skill Cost</a></div>", card.ID)
 
 	if action := r.FormValue("action"); action != "" {
 		if action == "uploadImages" {
@@ -322,9 +326,9 @@ func CardDetailHandler(w http.ResponseWriter, r *http.Request) {
 		}
 
 		pathPart := ""
-		if _, err := os.Stat(filepath.Join(vc.FilePath, "card", "hd", evo.Image())); err == nil {
+		if _, err := os.Stat(filepath.Join(ds.FilePath, "card", "hd", evo.Image())); err == nil {
 			pathPart = "cardHD"
-		} else if _, err := os.Stat(filepath.Join(vc.FilePath, "card", "md", evo.Image())); err == nil {
+		} else if _, err := os.Stat(filepath.Join(ds.FilePath, "card", "md", evo.Image())); err == nil {
 			pathPart = "card"
 		} else {
 			pathPart = "cardSD"
@@ -343,10 +347,17 @@ func CardDetailHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // CardCsvHandler outputs the cards as a CSV doc
-func CardCsvHandler(w http.ResponseWriter, r *http.Request) {
+func CardCsvHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	// File header
-	w.Header().Set("Content-Disposition", "attachment; filename=\"vcData-cards-"+strconv.Itoa(vc.Data.Version)+"_"+vc.Data.Common.UnixTime.Format(time.RFC3339)+".csv\"")
+	w.Header().Set("Content-Disposition", "attachment; filename=\"vcData-cards-"+strconv.Itoa(ds.Data.Version)+"_"+ds.Data.Common.UnixTime.Format(time.RFC3339)+".csv\"")
 	w.Header().Set("Content-Type", "text/csv")
+	if err := WriteCardCsv(w, ds); err != nil {
+		log.Printf(err.Error() + "\n")
+	}
+}
+
+// WriteCardCsv writes all the cards as a CSV doc
+func WriteCardCsv(w io.Writer, ds *vc.Dataset) error {
 	cw := csv.NewWriter(w)
 	cw.UseCRLF = true
 	cw.Write([]string{"ID", "Card #", "Name", "Evo Rank", "TranscardID", "Rarity", "Element", "Deck Cost", "Base ATK",
@@ -359,7 +370,7 @@ func CardCsvHandler(w http.ResponseWriter, r *http.Request) {
 		"Description", "Friendship", "Login", "Meet",
 		"Battle Start", "Battle End", "Friendship Max", "Friendship Event",
 		"Is Closed"})
-	for _, card := range vc.Data.Cards {
+	for _, card := range ds.Data.Cards {
 		err := cw.Write([]string{strconv.Itoa(card.ID), fmt.Sprintf("cd_%05d", card.CardNo), card.Name, strconv.Itoa(card.EvolutionRank),
 			strconv.Itoa(card.TransCardID), card.Rarity(), card.Element(), strconv.Itoa(card.DeckCost), strconv.Itoa(card.DefaultOffense),
 			strconv.Itoa(card.DefaultDefense), strconv.Itoa(card.DefaultFollower), strconv.Itoa(card.MaxOffense), strconv.Itoa(card.MaxDefense), strconv.Itoa(card.MaxFollower),
@@ -377,16 +388,23 @@ func CardCsvHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 	cw.Flush()
+	return cw.Error()
 }
 
 //CardJSONStatHandler outputs card stats as JSON
-func CardJSONStatHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Disposition", "attachment; filename=\"vcData-glr-cards-"+strconv.Itoa(vc.Data.Version)+"_"+vc.Data.Common.UnixTime.Format(time.RFC3339)+".json\"")
+func CardJSONStatHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
+	w.Header().Set("Content-Disposition", "attachment; filename=\"vcData-glr-cards-"+strconv.Itoa(ds.Data.Version)+"_"+ds.Data.Common.UnixTime.Format(time.RFC3339)+".json\"")
 	w.Header().Set("Content-Type", "application/json")
+	if err := WriteCardJSONStat(w, ds); err != nil {
+		io.WriteString(w, err.Error())
+	}
+}
 
+// WriteCardJSONStat writes GLR and Rebirth card stats as JSON
+func WriteCardJSONStat(w io.Writer, ds *vc.Dataset) error {
 	statCards := make([]structout.CardStatInfo, 0)
 
-	for _, card := range vc.Data.Cards {
+	for _, card := range ds.Data.Cards {
 		if card.Rarity() == "GLR" || card.EvoIsReborn() {
 			log.Printf("found GLR or Reborn card %d:%s - %s", card.ID, card.Name, card.Rarity())
 			statCards = append(statCards, structout.ToCardStatInfo(card))
@@ -394,17 +412,24 @@ func CardJSONStatHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	jsonenc, err := json.MarshalIndent(statCards, "", "  ")
 	if err != nil {
-		io.WriteString(w, err.Error())
-		return
+		return err
 	}
-	w.Write(jsonenc)
+	_, err = w.Write(jsonenc)
+	return err
 }
 
 // CardCsvGLRHandler outputs GLR and Rebirth cards in a format usable for stat calcuations
-func CardCsvGLRHandler(w http.ResponseWriter, r *http.Request) {
+func CardCsvGLRHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	// File header
-	w.Header().Set("Content-Disposition", "attachment; filename=\"vcData-glr-cards-"+strconv.Itoa(vc.Data.Version)+"_"+vc.Data.Common.UnixTime.Format(time.RFC3339)+".csv\"")
+	w.Header().Set("Content-Disposition", "attachment; filename=\"vcData-glr-cards-"+strconv.Itoa(ds.Data.Version)+"_"+ds.Data.Common.UnixTime.Format(time.RFC3339)+".csv\"")
 	w.Header().Set("Content-Type", "text/csv")
+	if err := WriteCardGLRCsv(w, ds); err != nil {
+		log.Printf(err.Error() + "\n")
+	}
+}
+
+// WriteCardGLRCsv writes GLR and Rebirth cards as a CSV doc
+func WriteCardGLRCsv(w io.Writer, ds *vc.Dataset) error {
 	cw := csv.NewWriter(w)
 	cw.UseCRLF = true
 	cw.Write([]string{"VC ID", "Name", "name-rare", "Element", "Rarity", "Base ATK",
@@ -434,7 +459,7 @@ func CardCsvGLRHandler(w http.ResponseWriter, r *http.Request) {
 		"Rebirth Max Level",
 	})
 
-	cards := vc.Data.Cards.Copy()
+	cards := ds.Data.Cards.Copy()
 	log.Printf("Total card count: %d", len(cards))
 	// sort by name A-Z
 	sort.Slice(cards, func(i, j int) bool {
@@ -521,10 +546,11 @@ func CardCsvGLRHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 	cw.Flush()
+	return cw.Error()
 }
 
 //CardTableHandler outputs the cards in an HTML table
-func CardTableHandler(w http.ResponseWriter, r *http.Request) {
+func CardTableHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	qs := r.URL.Query()
 	filter := func(card *vc.Card) (match bool) {
 		match = true
@@ -608,7 +634,7 @@ func CardTableHandler(w http.ResponseWriter, r *http.Request) {
 </select>
 <label for="f_symbol">Symbol:</label><select id="f_symbol" name="symbol" value="%s">
 <option value=""></option>
-`+symbolNamesToOptions(qs.Get("symbol"))+`
+`+symbolNamesToOptions(qs.Get("symbol"), ds)+`
 </select>
 <label for="f_evos">Evo:</label><select id="f_evos" name="evos" value="%s">
 <option value=""></option>
@@ -677,8 +703,8 @@ func CardTableHandler(w http.ResponseWriter, r *http.Request) {
 	)
 	fmt.Fprintf(w, "\n<tbody>\n")
 
-	for i := len(vc.Data.Cards) - 1; i >= 0; i-- {
-		card := vc.Data.Cards[i]
+	for i := len(ds.Data.Cards) - 1; i >= 0; i-- {
+		card := ds.Data.Cards[i]
 		if !filter(card) {
 			continue
 		}
@@ -757,8 +783,8 @@ func elementToOptions(selected string) (ret string) {
 	return
 }
 
-func symbolNamesToOptions(selected string) (ret string) {
-	for i, v := range vc.Data.SymbolNames {
+func symbolNamesToOptions(selected string, ds *vc.Dataset) (ret string) {
+	for i, v := range ds.Data.SymbolNames {
 		if v == selected || strconv.Itoa(i) == selected {
 			ret += fmt.Sprintf(`<option value="%d" selected="selected">%s</option>`, i, v)
 		} else {
@@ -788,8 +814,9 @@ func getPreviousCard(card *vc.Card) (prev *vc.Card, prevName string) {
 		return nil, ""
 	}
 
+	ds := card.Dataset()
 	minID := card.GetEvolutionCards().Earliest().ID - 1
-	for prev = vc.CardScan(minID); prev == nil && minID > 0; prev = vc.CardScan(minID) {
+	for prev = ds.CardScan(minID); prev == nil && minID > 0; prev = ds.CardScan(minID) {
 		minID--
 	}
 	if prev != nil {
@@ -810,9 +837,10 @@ func getNextCard(card *vc.Card) (next *vc.Card, nextName string) {
 	if card == nil {
 		return nil, ""
 	}
+	ds := card.Dataset()
 	maxID := card.GetEvolutionCards().Latest().ID + 1
-	lastID := vc.Data.Cards.Latest().ID
-	for next = vc.CardScan(maxID); next == nil && maxID < lastID; next = vc.CardScan(maxID) {
+	lastID := ds.Data.Cards.Latest().ID
+	for next = ds.CardScan(maxID); next == nil && maxID < lastID; next = ds.CardScan(maxID) {
 		maxID++
 	}
 	if next != nil {
@@ -12,7 +12,7 @@ import (
 )
 
 // CharacterTableHandler show character data in a table format
-func CharacterTableHandler(w http.ResponseWriter, r *http.Request) {
+func CharacterTableHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	qs := r.URL.Query()
 	filter := func(character *vc.CardCharacter) (match bool) {
 		match = true
@@ -92,8 +92,8 @@ func CharacterTableHandler(w http.ResponseWriter, r *http.Request) {
 
 	// sort the characters by most recent card
 	// copy the character data so we don't modify the inline global
-	chars := make([]vc.CardCharacter, len(vc.Data.CardCharacters))
-	copy(chars, vc.Data.CardCharacters)
+	chars := make([]vc.CardCharacter, len(ds.Data.CardCharacters))
+	copy(chars, ds.Data.CardCharacters)
 
 	sort.Slice(chars, func(i, j int) bool {
 		first := chars[i]
@@ -179,7 +179,7 @@ func CharacterTableHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // CharacterDetailHandler show character details
-func CharacterDetailHandler(w http.ResponseWriter, r *http.Request) {
+func CharacterDetailHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	path := r.URL.Path
 	var pathLen int
 	if path[len(path)-1] == '/' {
@@ -195,12 +195,12 @@ func CharacterDetailHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	charID, err := strconv.Atoi(pathParts[2])
-	if err != nil || charID < 1 || charID > len(vc.Data.CardCharacters) {
+	if err != nil || charID < 1 || charID > len(ds.Data.CardCharacters) {
 		http.Error(w, "Invalid character id "+pathParts[2], http.StatusNotFound)
 		return
 	}
 
-	character := vc.CardCharacterScan(charID)
+	character := ds.CardCharacterScan(charID)
 
 	cards := character.Cards()
 
@@ -4,30 +4,40 @@ import (
 	"fmt"
 	"html"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 
 	"vc_file_grouper/vc"
 	"vc_file_grouper/wiki/api"
 )
 
-// ConfigDataLocHandler configures the path for the main VC data file
-func ConfigDataLocHandler(w http.ResponseWriter, r *http.Request) {
+// ConfigDataLocHandler configures the path for the main VC data file.
+// The data is read into a new dataset that replaces the current one if it loads without errors.
+func ConfigDataLocHandler(current *CurrentDataset) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		configDataLoc(w, r, current)
+	}
+}
+
+func configDataLoc(w http.ResponseWriter, r *http.Request, current *CurrentDataset) {
 	io.WriteString(w, "<html><head><title>Update Master Data</title></head><body>\n")
 
 	// check form value and update if valid
+	ds := current.Get()
 	newpath := r.FormValue("path")
 	if newpath != "" {
 		newpath = filepath.Clean(newpath)
 		if _, err := os.Stat(newpath); os.IsNotExist(err) {
 			io.WriteString(w, "<div>Invalid new path specified</div>")
 		} else {
-			if err = vc.ReadMasterData(newpath); err != nil {
+			if newDs, err := current.Load(newpath); err != nil {
 				fmt.Fprintf(w, "<div>%s</div>", err.Error())
 			} else {
 				io.WriteString(w, "<div>Success</div>")
-				vc.FilePath = newpath
+				ds = newDs
 			}
 		}
 	}
@@ -38,7 +48,7 @@ func ConfigDataLocHandler(w http.ResponseWriter, r *http.Request) {
 <button type="submit">Submit</button>
 <p><a href="/">back</a></p>
 </form>`,
-		html.EscapeString(vc.FilePath),
+		html.EscapeString(ds.FilePath),
 	)
 	io.WriteString(w, "</body></html>")
 }
@@ -77,3 +87,111 @@ func ConfigBotCredsHandler(w http.ResponseWriter, r *http.Request) {
 	)
 	io.WriteString(w, "</body></html>")
 }
+
+// ConfigOverridesHandler views and edits the card name overrides.
+// Saved changes are checked, written to the overrides file and the data is read again to use them.
+func ConfigOverridesHandler(current *CurrentDataset) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		configOverrides(w, r, current)
+	}
+}
+
+func configOverrides(w http.ResponseWriter, r *http.Request, current *CurrentDataset) {
+	io.WriteString(w, "<html><head><title>Card Name Overrides</title>\n")
+	io.WriteString(w, "<style>table, th, td {border: 1px solid black;} .error {color: red;}</style>")
+	io.WriteString(w, "</head><body>\n<p><a href=\"/\">back</a></p>\n")
+
+	ds := current.Get()
+	var text []byte
+	if r.Method == http.MethodPost {
+		text = []byte(r.FormValue("overrides"))
+		if file, err := ds.SaveNameOverrides(text); err != nil {
+			fmt.Fprintf(w, "<div class=\"error\">The overrides were not saved: %s</div>\n", html.EscapeString(err.Error()))
+		} else if newDs, err := current.Reload(); err != nil {
+			fmt.Fprintf(w, "<div class=\"error\">Saved %s, but the data could not be read again: %s</div>\n", html.EscapeString(file), html.EscapeString(err.Error()))
+		} else {
+			fmt.Fprintf(w, "<div>Saved %s</div>\n", html.EscapeString(file))
+			ds = newDs
+			text = nil
+		}
+	}
+	overrides := ds.Overrides
+	if overrides == nil {
+		overrides = vc.DefaultNameOverrides()
+	}
+	if text == nil {
+		if overrides.File == "" {
+			text = vc.DefaultNameOverridesJSON()
+		} else {
+			var err error
+			if text, err = ioutil.ReadFile(overrides.File); err != nil {
+				fmt.Fprintf(w, "<div class=\"error\">%s</div>\n", html.EscapeString(err.Error()))
+			}
+		}
+	}
+
+	if overrides.File == "" {
+		fmt.Fprintf(w, "<p>Using the built in overrides. Saving creates %s</p>\n", html.EscapeString(filepath.Join(ds.FilePath, vc.NameOverridesFile)))
+	} else {
+		fmt.Fprintf(w, "<p>Using %s</p>\n", html.EscapeString(overrides.File))
+	}
+	if problems := ds.ValidateNameOverrides(overrides); len(problems) > 0 {
+		io.WriteString(w, "<p>These overrides do not match the data:</p><ul class=\"error\">\n")
+		for _, p := range problems {
+			fmt.Fprintf(w, "<li>%s</li>\n", html.EscapeString(p))
+		}
+		io.WriteString(w, "</ul>\n")
+	}
+
+	fmt.Fprintf(w, `<form method="post">
+<textarea name="overrides" rows="30" cols="150">%s</textarea><br/>
+<button type="submit">Save</button>
+</form>
+`,
+		html.EscapeString(string(text)),
+	)
+
+	io.WriteString(w, "<h2>Character Names</h2>\n<table><thead><tr><th>Character</th><th>Name</th><th>Comment</th></tr></thead><tbody>\n")
+	for _, cn := range overrides.CharacterNames {
+		fmt.Fprintf(w, "<tr><td><a href=\"/characters/detail/%[1]d\">%[1]d</a></td><td>%[2]s</td><td>%[3]s</td></tr>\n",
+			cn.CharacterID,
+			html.EscapeString(cn.Name),
+			html.EscapeString(cn.Comment),
+		)
+	}
+	io.WriteString(w, "</tbody></table>\n")
+
+	io.WriteString(w, "<h2>Name Rules</h2>\n<table><thead><tr><th>Replace</th><th>With</th><th>Before Character</th><th>Comment</th></tr></thead><tbody>\n")
+	for _, rule := range overrides.NameRules {
+		before := ""
+		if rule.BeforeCharacter > 0 {
+			before = strconv.Itoa(rule.BeforeCharacter)
+		}
+		fmt.Fprintf(w, "<tr><td>'%s'</td><td>'%s'</td><td>%s</td><td>%s</td></tr>\n",
+			html.EscapeString(rule.From),
+			html.EscapeString(rule.To),
+			before,
+			html.EscapeString(rule.Comment),
+		)
+	}
+	io.WriteString(w, "</tbody></table>\n")
+
+	for _, list := range []struct {
+		title  string
+		groups []vc.CardGroup
+	}{
+		{"Retired Cards (Old)", overrides.RetiredCards},
+		{"New Cards (New)", overrides.NewCards},
+	} {
+		fmt.Fprintf(w, "<h2>%s</h2>\n<table><thead><tr><th>Cards</th><th>Comment</th></tr></thead><tbody>\n", list.title)
+		for _, g := range list.groups {
+			io.WriteString(w, "<tr><td>")
+			for _, id := range g.Cards {
+				fmt.Fprintf(w, "<a href=\"/cards/detail/%[1]d\">%[1]d</a> ", id)
+			}
+			fmt.Fprintf(w, "</td><td>%s</td></tr>\n", html.EscapeString(g.Comment))
+		}
+		io.WriteString(w, "</tbody></table>\n")
+	}
+	io.WriteString(w, "</body></html>")
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strconv"
+
+	"vc_file_grouper/vc"
+)
+
+// CostRollupHandler shows the total cost of getting a card from scratch.
+// Use ?card=id to select the card and format=json for the totals as JSON
+func CostRollupHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
+	var rollup *vc.CostRollup
+	var rollupErr error
+	if v := r.FormValue("card"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "Invalid card id "+v, http.StatusNotFound)
+			return
+		}
+		card := ds.CardScan(id)
+		if card == nil {
+			http.Error(w, "Invalid card id "+v+"\nCard not found.", http.StatusNotFound)
+			return
+		}
+		rollup, rollupErr = card.CostRollup()
+	}
+
+	if r.FormValue("format") == "json" {
+		if rollup == nil || rollupErr != nil {
+			msg := "A card is required"
+			if rollupErr != nil {
+				msg = rollupErr.Error()
+			}
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", "filename=cost_"+strconv.Itoa(rollup.CardID)+".json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "\t")
+		enc.Encode(rollup)
+		return
+	}
+
+	io.WriteString(w, "<html><head><title>Card Cost</title>\n")
+	io.WriteString(w, "<style>table, th, td {border: 1px solid black;};</style>")
+	io.WriteString(w, "</head><body>\n")
+	fmt.Fprintf(w, `<h1>Card Cost</h1>
+<form method="get">
+<label for="f_card">Card ID</label>
+<input id="f_card" name="card" value="%s"/>
+<button type="submit">Calculate</button>
+<button type="submit" name="format" value="json">Download as JSON</button>
+</form>
+<p><a href="/">back</a></p>
+`,
+		html.EscapeString(r.FormValue("card")),
+	)
+	if rollupErr != nil {
+		fmt.Fprintf(w, "<p>%s</p>\n", html.EscapeString(rollupErr.Error()))
+	} else if rollup != nil {
+		writeCostRollup(w, rollup)
+	}
+	io.WriteString(w, "</body></html>")
+}
+
+func writeCostRollup(w io.Writer, rollup *vc.CostRollup) {
+	fmt.Fprintf(w, "<h2><a href=\"/cards/detail/%d\">%s</a> (%s)</h2>\n",
+		rollup.CardID,
+		html.EscapeString(rollup.Name),
+		html.EscapeString(rollup.Plan.Card.Rarity()),
+	)
+	fmt.Fprintf(w, "<p>Final stats: %d ATK, %d DEF, %d SOL. <a href=\"/cards/planner/?card=%d\">Plan with your own materials</a></p>\n",
+		rollup.Plan.Result.Attack,
+		rollup.Plan.Result.Defense,
+		rollup.Plan.Result.Soldiers,
+		rollup.CardID,
+	)
+
+	io.WriteString(w, "<h3>Cards to Collect</h3>\n<table><thead><tr><th>Card</th><th>Count</th></tr></thead><tbody>\n")
+	for _, c := range rollup.Cards {
+		fmt.Fprintf(w, "<tr><td><a href=\"/cards/detail/%d\">%s</a></td><td>%d</td></tr>\n", c.CardID, html.EscapeString(c.Name), c.Count)
+	}
+	io.WriteString(w, "</tbody></table>\n")
+
+	if len(rollup.Items) > 0 {
+		io.WriteString(w, "<h3>Items</h3>\n<table><thead><tr><th>Item</th><th>Count</th><th>Expected with Failures</th></tr></thead><tbody>\n")
+		for _, i := range rollup.Items {
+			fmt.Fprintf(w, "<tr><td>%s</td><td>%d</td><td>%.1f</td></tr>\n", html.EscapeString(i.Name), i.Count, i.ExpectedCount)
+		}
+		io.WriteString(w, "</tbody></table>\n")
+	}
+
+	io.WriteString(w, "<h3>Steps</h3>\n<table><thead><tr><th>Card</th><th>Method</th><th>Count</th><th>Success</th><th>Expected Attempts</th></tr></thead><tbody>\n")
+	for _, s := range rollup.Steps {
+		success := ""
+		if s.Percent > 0 {
+			success = strconv.Itoa(s.Percent) + "%"
+		}
+		fmt.Fprintf(w, "<tr><td><a href=\"/cards/detail/%d\">%s</a></td><td>%s</td><td>%d</td><td>%s</td><td>%.1f</td></tr>\n",
+			s.CardID,
+			html.EscapeString(s.Name),
+			s.Method,
+			s.Count,
+			success,
+			s.ExpectedAttempts,
+		)
+	}
+	io.WriteString(w, "</tbody></table>\n")
+
+	fmt.Fprintf(w, "<h3>Levelling</h3>\n<p>%d EXP to level the cards used at max level, costing %d Gold, %d Iron, %d Ether and %d Elixir.</p>\n",
+		rollup.LevelExp,
+		rollup.LevelCost.Gold,
+		rollup.LevelCost.Iron,
+		rollup.LevelCost.Ether,
+		rollup.LevelCost.Elixir,
+	)
+}
@@ -4,21 +4,25 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"html"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"vc_file_grouper/vc"
 )
 
 // RawDataHandler outputs the raw JSON
-func RawDataHandler(w http.ResponseWriter, r *http.Request) {
+func RawDataHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	var prettyJSON bytes.Buffer
-	err := json.Indent(&prettyJSON, []byte(vc.MasterDataStr), "", "\t")
+	err := json.Indent(&prettyJSON, []byte(ds.MasterDataStr), "", "\t")
 	if err != nil {
 		// File header
 		io.WriteString(w, "<html><body>\n")
@@ -29,16 +33,16 @@ func RawDataHandler(w http.ResponseWriter, r *http.Request) {
 		io.WriteString(w, "</body></html>")
 		return
 	}
-	w.Header().Set("Content-Disposition", "filename="+"vcData-raw-"+strconv.Itoa(vc.Data.Version)+"_"+vc.Data.Common.UnixTime.Format(time.RFC3339)+".json")
+	w.Header().Set("Content-Disposition", "filename="+"vcData-raw-"+strconv.Itoa(ds.Data.Version)+"_"+ds.Data.Common.UnixTime.Format(time.RFC3339)+".json")
 	w.Header().Set("Content-Type", "application/json")
 
 	io.WriteString(w, prettyJSON.String())
 }
 
 // RawDataKeysHandler outputs all keys in the main JSON object
-func RawDataKeysHandler(w http.ResponseWriter, r *http.Request) {
+func RawDataKeysHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	c := make(map[string]interface{})
-	err := json.Unmarshal([]byte(vc.MasterDataStr), &c)
+	err := json.Unmarshal([]byte(ds.MasterDataStr), &c)
 	if err != nil {
 		// File header
 		io.WriteString(w, "<html><body>\n")
@@ -49,7 +53,7 @@ func RawDataKeysHandler(w http.ResponseWriter, r *http.Request) {
 		io.WriteString(w, "</body></html>")
 		return
 	}
-	w.Header().Set("Content-Disposition", "filename="+"vcData-raw-"+strconv.Itoa(vc.Data.Version)+"_"+vc.Data.Common.UnixTime.Format(time.RFC3339)+".json")
+	w.Header().Set("Content-Disposition", "filename="+"vcData-raw-"+strconv.Itoa(ds.Data.Version)+"_"+ds.Data.Common.UnixTime.Format(time.RFC3339)+".json")
 	w.Header().Set("Content-Type", "application/json")
 
 	io.WriteString(w, "[\n")
@@ -68,35 +72,171 @@ func RawDataKeysHandler(w http.ResponseWriter, r *http.Request) {
 	io.WriteString(w, "]")
 }
 
-// DecodeHandler decodes all files
-func DecodeHandler(w http.ResponseWriter, r *http.Request) {
-	io.WriteString(w, "<html><head><title>File Decode</title></head><body>\nDecodng files<br />\n")
-	err := filepath.Walk(vc.FilePath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+// RawDataSchemaHandler compares the raw JSON with the data model, listing the tables and fields
+// that are not read and the modelled fields that are no longer in the data. Use ?format=json for
+// the report as JSON.
+func RawDataSchemaHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
+	drift, err := ds.SchemaDrift()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if r.FormValue("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "\t")
+		enc.Encode(drift)
+		return
+	}
+
+	io.WriteString(w, "<html><head><title>Schema Drift</title>\n")
+	io.WriteString(w, "<style>table, th, td {border: 1px solid black;};</style>")
+	io.WriteString(w, "</head><body>\n")
+	fmt.Fprintf(w, "<h1>Schema Drift</h1>\n<p>Version %d: %s. <a href=\"?format=json\">JSON</a></p>\n", ds.Data.Version, html.EscapeString(drift.Summary()))
+
+	if len(drift.UnmodelledTables) > 0 {
+		io.WriteString(w, "<h2>Unmodelled Tables</h2>\n<table><thead><tr><th>Key</th><th>Records</th><th>Sample</th></tr></thead><tbody>\n")
+		for _, t := range drift.UnmodelledTables {
+			fmt.Fprintf(w, "<tr><td>%s</td><td>%d</td><td><code>%s</code></td></tr>\n",
+				html.EscapeString(t.Key), t.Records, html.EscapeString(t.Sample))
 		}
-		if info.IsDir() {
-			return nil
+		io.WriteString(w, "</tbody></table>\n")
+	}
+	if len(drift.MissingTables) > 0 {
+		io.WriteString(w, "<h2>Missing Tables</h2>\n<ul>\n")
+		for _, t := range drift.MissingTables {
+			fmt.Fprintf(w, "<li>%s</li>\n", html.EscapeString(t))
 		}
-		fileEncoded, err := vc.IsFileEncoded(path)
-		if err != nil {
-			return err
+		io.WriteString(w, "</ul>\n")
+	}
+	if len(drift.UnknownFields) > 0 {
+		io.WriteString(w, "<h2>Unknown Fields</h2>\n<table><thead><tr><th>Table</th><th>Field</th><th>Records</th><th>Sample</th></tr></thead><tbody>\n")
+		for _, f := range drift.UnknownFields {
+			fmt.Fprintf(w, "<tr><td>%s</td><td>%s</td><td>%d</td><td><code>%s</code></td></tr>\n",
+				html.EscapeString(f.Table), html.EscapeString(f.Field), f.Records, html.EscapeString(f.Sample))
 		}
-		if fileEncoded {
-			fmt.Fprintf(w, "Decoding: %s ", path)
-			nf, _, err := vc.DecodeAndSave(path)
-			if err != nil {
-				fmt.Fprintf(w, " : ERROR: %s<br />\n", err.Error())
-				return err
-			}
-			fmt.Fprintf(w, " : %s<br />\n", nf)
+		io.WriteString(w, "</tbody></table>\n")
+	}
+	if len(drift.MissingFields) > 0 {
+		io.WriteString(w, "<h2>Missing Fields</h2>\n<table><thead><tr><th>Table</th><th>Field</th></tr></thead><tbody>\n")
+		for _, f := range drift.MissingFields {
+			fmt.Fprintf(w, "<tr><td>%s</td><td>%s</td></tr>\n", html.EscapeString(f.Table), html.EscapeString(f.Field))
+		}
+		io.WriteString(w, "</tbody></table>\n")
+	}
+	io.WriteString(w, "</body></html>")
+}
+
+// WarnSchemaDrift writes a warning to the standard error if the master data of the dataset does not match the data model
+func WarnSchemaDrift(ds *vc.Dataset) {
+	drift, err := ds.SchemaDrift()
+	if err != nil || drift.IsEmpty() {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Warning: %s. See /raw/SCHEMA for details\n", drift.Summary())
+}
+
+// DecodeDir folder the decoded data files are written to
+var DecodeDir = "decoded"
+
+// decodeRun only one decode runs at a time since the runs share the files in DecodeDir
+var decodeRun struct {
+	lock    sync.Mutex
+	running bool
+}
+
+// DecodeHandler decodes all the encoded files into DecodeDir, showing the progress as it goes.
+// Files that were already decoded are skipped unless ?force=1 is used.
+func DecodeHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
+	decodeRun.lock.Lock()
+	if decodeRun.running {
+		decodeRun.lock.Unlock()
+		http.Error(w, "The files are already being decoded, try again when that is done", http.StatusConflict)
+		return
+	}
+	decodeRun.running = true
+	decodeRun.lock.Unlock()
+	defer func() {
+		decodeRun.lock.Lock()
+		decodeRun.running = false
+		decodeRun.lock.Unlock()
+	}()
+
+	decoder := vc.BulkDecoder{
+		Root:   ds.FilePath,
+		OutDir: DecodeDir,
+		Force:  r.FormValue("force") == "1",
+	}
+	flusher, _ := w.(http.Flusher)
+	fmt.Fprintf(w, "<html><head><title>File Decode</title></head><body>\nDecoding files into %s<br />\n", html.EscapeString(DecodeDir))
+	summary, err := decoder.Run(func(res vc.DecodeResult) {
+		if res.Skipped {
+			return
+		}
+		if res.Err != nil {
+			fmt.Fprintf(w, "Decoding: %s : ERROR: %s<br />\n", html.EscapeString(res.File), html.EscapeString(res.Err.Error()))
+		} else {
+			fmt.Fprintf(w, "Decoding: %s : %s<br />\n", html.EscapeString(res.File), html.EscapeString(res.NewFile))
+		}
+		if flusher != nil {
+			flusher.Flush()
 		}
-		return nil
 	})
-	if err == nil {
-		io.WriteString(w, "Decode complete<br />\n")
-	} else {
-		io.WriteString(w, err.Error()+"<br />\n")
+	if err != nil {
+		io.WriteString(w, html.EscapeString(err.Error())+"<br />\n")
+	}
+	fmt.Fprintf(w, "Decode complete. %d decoded, %d already up to date, %d failed<br />\n", summary.Decoded, summary.Skipped, len(summary.Failed))
+	if summary.Skipped > 0 {
+		io.WriteString(w, `<a href="?force=1">Decode all the files again</a><br />`+"\n")
 	}
 	io.WriteString(w, "</body></html>")
 }
+
+// EncodeHandler encodes an uploaded JSON or PNG file so the game can read it. The header and key are
+// copied from the data file it replaces. The encoded file is downloaded and the data location is not changed.
+func EncodeHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
+	target := r.FormValue("file")
+	if r.Method != "POST" {
+		io.WriteString(w, "<html><head><title>File Encode</title></head><body>\n")
+		fmt.Fprintf(w, `<form method="post" enctype="multipart/form-data">
+<div><label for="f_file">Data file it replaces: <input id="f_file" name="file" type="text" value="%s" size="60"/></label><small>(relative to the data location, i.e. response/master_all. Leave blank to use a new key)</small></div>
+<div><label for="f_data">Changed JSON or PNG file: <input id="f_data" name="data" type="file"/></label></div>
+<button type="submit">Encode</button>
+</form>
+</body></html>`,
+			html.EscapeString(target),
+		)
+		return
+	}
+
+	h := vc.NewCodeHeader()
+	name := "encoded"
+	if target != "" {
+		rel := filepath.Clean(filepath.FromSlash(target))
+		if filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			http.Error(w, "Invalid data file: "+target, http.StatusBadRequest)
+			return
+		}
+		var err error
+		if h, err = vc.ReadCodeHeader(filepath.Join(ds.FilePath, rel)); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		name = filepath.Base(rel)
+	}
+	f, _, err := r.FormFile("data")
+	if err != nil {
+		http.Error(w, "The file to encode is missing: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer f.Close()
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	w.Write(vc.Encode(data, h))
+}
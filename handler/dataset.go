@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+	"sync"
+
+	"vc_file_grouper/vc"
+)
+
+// DatasetHandlerFunc an HTTP handler that works with the VC data
+type DatasetHandlerFunc func(w http.ResponseWriter, r *http.Request, ds *vc.Dataset)
+
+// CurrentDataset holds the dataset served by the web server. The dataset can be replaced
+// when new master data is loaded. It is safe for concurrent use.
+type CurrentDataset struct {
+	lock sync.RWMutex
+	ds   *vc.Dataset
+	// loadLock makes sure only one new dataset is read at a time
+	loadLock sync.Mutex
+}
+
+// NewCurrentDataset creates a holder for the dataset served by the web server
+func NewCurrentDataset(ds *vc.Dataset) *CurrentDataset {
+	return &CurrentDataset{ds: ds}
+}
+
+// Get the dataset currently being served
+func (c *CurrentDataset) Get() *vc.Dataset {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.ds
+}
+
+// Set replaces the dataset being served
+func (c *CurrentDataset) Set(ds *vc.Dataset) {
+	c.lock.Lock()
+	c.ds = ds
+	c.lock.Unlock()
+}
+
+// Load reads the data location into a new dataset using the current language packs.
+// The new dataset is only swapped in once it has loaded without errors, so requests
+// that are in progress keep using the dataset they started with.
+func (c *CurrentDataset) Load(filePath string) (*vc.Dataset, error) {
+	c.loadLock.Lock()
+	defer c.loadLock.Unlock()
+	old := c.Get()
+	ds, err := vc.LoadDataset(filePath, old.LangPack, old.Languages...)
+	if err != nil {
+		return nil, err
+	}
+	c.Set(ds)
+	WarnSchemaDrift(ds)
+	return ds, nil
+}
+
+// Reload reads the current data location again
+func (c *CurrentDataset) Reload() (*vc.Dataset, error) {
+	return c.Load(c.Get().FilePath)
+}
+
+// Handle adapts a dataset handler to a standard HTTP handler that is given the current dataset on each request
+func (c *CurrentDataset) Handle(h DatasetHandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h(w, r, c.Get())
+	}
+}
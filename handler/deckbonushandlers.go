@@ -13,12 +13,12 @@ import (
 )
 
 // DeckBonusHandler show deck bonuses as a table
-func DeckBonusHandler(w http.ResponseWriter, r *http.Request) {
+func DeckBonusHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	io.WriteString(w, `<html><head><title>Deck Bonuses</title>
 <style>table, th, td {border: 1px solid black;};</style>
 </head><body>
 <div>
-<a href="WIKI/">Wiki Formatted</a>
+<a href="WIKI/">Wiki Formatted</a> <a href="/deck/">Deck Builder</a>
 <table><thead><tr>
   <th>_id</th>
   <th>Name</th>
@@ -33,9 +33,9 @@ func DeckBonusHandler(w http.ResponseWriter, r *http.Request) {
 </tr></thead>
 <tbody>`)
 
-	//sort.Sort(vc.DeckBonusByCountAndName(vc.Data.DeckBonuses))
+	//sort.Sort(vc.DeckBonusByCountAndName(ds.Data.DeckBonuses))
 
-	for _, d := range vc.Data.DeckBonuses {
+	for _, d := range ds.Data.DeckBonuses {
 		fmt.Fprintf(w, `<tr>
   <td>%d</td>
   <td>%s</td>
@@ -64,7 +64,7 @@ func DeckBonusHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // DeckBonusWikiHandler show deck bonuses as wiki formatted
-func DeckBonusWikiHandler(w http.ResponseWriter, r *http.Request) {
+func DeckBonusWikiHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	io.WriteString(w, `<html><head><title>Deck Bonuses</title>
 <style>table, th, td {border: 1px solid black;};</style>
 </head><body>
@@ -83,13 +83,13 @@ func DeckBonusWikiHandler(w http.ResponseWriter, r *http.Request) {
 
 `
 
-	sort.Sort(vc.DeckBonusByCountAndName(vc.Data.DeckBonuses))
+	sort.Sort(vc.DeckBonusByCountAndName(ds.Data.DeckBonuses))
 
 	reg := regexp.MustCompile(`\[|【(.+)\]|】\n?(.*)`)
 
 	oldReq := -1
 
-	for _, d := range vc.Data.DeckBonuses {
+	for _, d := range ds.Data.DeckBonuses {
 		if oldReq != d.ReqNum {
 			if oldReq > 1 {
 				io.WriteString(w, tableFooter)
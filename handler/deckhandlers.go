@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"vc_file_grouper/vc"
+)
+
+// DeckBuilderHandler checks a deck: its total cost, the deck bonuses it triggers and the ones one
+// more card would trigger. List the card ids in "cards", separated by commas or spaces, and add
+// format=json to get the result as JSON.
+func DeckBuilderHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
+	var deck *vc.Deck
+	var deckErr error
+	if v := strings.TrimSpace(r.FormValue("cards")); v != "" {
+		deck, deckErr = parseDeckCards(v)
+		if deckErr == nil {
+			deckErr = ds.EvaluateDeck(deck)
+		}
+	}
+
+	if r.FormValue("format") == "json" {
+		if deck == nil || deckErr != nil {
+			msg := "Cards are required"
+			if deckErr != nil {
+				msg = deckErr.Error()
+			}
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "\t")
+		enc.Encode(deck)
+		return
+	}
+
+	io.WriteString(w, "<html><head><title>Deck Builder</title>\n")
+	io.WriteString(w, "<style>table, th, td {border: 1px solid black;};</style>")
+	io.WriteString(w, "</head><body>\n")
+	fmt.Fprintf(w, `<h1>Deck Builder</h1>
+<form method="get">
+<label for="f_cards">Card IDs, up to %d, separated by commas or spaces</label>
+<input id="f_cards" name="cards" size="40" value="%s"/><br />
+<button type="submit">Check</button>
+<button type="submit" name="format" value="json">Check as JSON</button>
+</form>
+<p><a href="/deckbonus">Deck Bonuses</a> <a href="/">back</a></p>
+`,
+		vc.MaxDeckSize,
+		html.EscapeString(r.FormValue("cards")),
+	)
+	if deckErr != nil {
+		fmt.Fprintf(w, "<p>%s</p>\n", html.EscapeString(deckErr.Error()))
+	} else if deck != nil {
+		io.WriteString(w, "<table><thead><tr><th>Card</th><th>Element</th><th>Deck Cost</th></tr></thead><tbody>\n")
+		for _, c := range deck.Cards {
+			fmt.Fprintf(w, "<tr><td><a href=\"/cards/detail/%d\">%s</a> (%s)</td><td>%s</td><td>%d</td></tr>\n",
+				c.ID,
+				html.EscapeString(c.Name),
+				html.EscapeString(c.Rarity()),
+				c.Element(),
+				c.DeckCost,
+			)
+		}
+		io.WriteString(w, "</tbody></table>\n")
+		fmt.Fprintf(w, "<p>Total deck cost: %d. Deck bonuses: ATK +%d, DEF +%d</p>\n",
+			deck.DeckCost,
+			deck.AtkBonus,
+			deck.DefBonus,
+		)
+
+		io.WriteString(w, "<h2>Triggered Bonuses</h2>\n")
+		writeDeckBonusResults(w, deck.Bonuses, false)
+		io.WriteString(w, "<h2>One More Card</h2>\n")
+		writeDeckBonusResults(w, deck.NearMisses, true)
+	}
+	io.WriteString(w, "</body></html>")
+}
+
+func writeDeckBonusResults(w io.Writer, results []vc.DeckBonusResult, showMissing bool) {
+	if len(results) == 0 {
+		io.WriteString(w, "<p>None</p>\n")
+		return
+	}
+	io.WriteString(w, "<table><thead><tr><th>Bonus</th><th>Description</th><th>Atk/Def</th><th>Value</th><th>Cards</th>")
+	if showMissing {
+		io.WriteString(w, "<th>Add a card that is</th>")
+	}
+	io.WriteString(w, "</tr></thead><tbody>\n")
+	for _, b := range results {
+		atkDef := ""
+		if b.AffectsAtk() {
+			atkDef = "ATK"
+		}
+		if b.AffectsDef() {
+			if atkDef != "" {
+				atkDef += "/"
+			}
+			atkDef += "DEF"
+		}
+		fmt.Fprintf(w, "<tr><td>%s</td><td>%s</td><td>%s</td><td>%d</td><td>%d / %d</td>",
+			html.EscapeString(b.Name),
+			html.EscapeString(b.Description),
+			atkDef,
+			b.Value,
+			b.Matched,
+			b.ReqNum,
+		)
+		if showMissing {
+			fmt.Fprintf(w, "<td>%s</td>", html.EscapeString(strings.Join(b.Missing, " or ")))
+		}
+		io.WriteString(w, "</tr>\n")
+	}
+	io.WriteString(w, "</tbody></table>\n")
+}
+
+// parseDeckCards reads the card ids of a deck
+func parseDeckCards(v string) (*vc.Deck, error) {
+	deck := &vc.Deck{CardIDs: make([]int, 0)}
+	fields := strings.FieldsFunc(v, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+	for _, f := range fields {
+		id, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, fmt.Errorf("invalid card id %s", f)
+		}
+		deck.CardIDs = append(deck.CardIDs, id)
+	}
+	return deck, nil
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"vc_file_grouper/vc"
+)
+
+// MasterDiffHandler compares two versions of the master data.
+// Use ?old=path&new=path to select the data, and ?format=json for JSON output
+func MasterDiffHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
+	oldPath := r.FormValue("old")
+	newPath := r.FormValue("new")
+	if newPath == "" {
+		newPath = ds.FilePath
+	}
+
+	if oldPath != "" && r.FormValue("format") == "json" {
+		diff, err := vc.DiffMasterData(filepath.Clean(oldPath), filepath.Clean(newPath), ds.LangPack)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		WriteMasterDiffJSON(w, diff)
+		return
+	}
+
+	io.WriteString(w, "<html><head><title>Master Data Changes</title>\n")
+	io.WriteString(w, "<style>table, th, td {border: 1px solid black;};</style>")
+	io.WriteString(w, "</head><body>\n")
+	fmt.Fprintf(w, `<form method="get">
+<label for="f_old">Old Data Path or master_all.json</label>
+<input id="f_old" name="old" value="%s" style="width:300px"/><br />
+<label for="f_new">New Data Path or master_all.json</label>
+<input id="f_new" name="new" value="%s" style="width:300px"/><br />
+<label for="f_format">Format</label>
+<select id="f_format" name="format"><option value="html">HTML</option><option value="json">JSON</option></select>
+<button type="submit">Compare</button>
+<p><a href="/">back</a></p>
+</form>
+`,
+		html.EscapeString(oldPath),
+		html.EscapeString(newPath),
+	)
+	if oldPath != "" {
+		diff, err := vc.DiffMasterData(filepath.Clean(oldPath), filepath.Clean(newPath), ds.LangPack)
+		if err != nil {
+			fmt.Fprintf(w, "<div>%s</div>", html.EscapeString(err.Error()))
+		} else {
+			writeMasterDiffBody(w, diff)
+		}
+	}
+	io.WriteString(w, "</body></html>")
+}
+
+// WriteMasterDiffJSON writes the master data changes as JSON
+func WriteMasterDiffJSON(w io.Writer, diff *vc.MasterDiff) error {
+	b, err := json.MarshalIndent(diff, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// WriteMasterDiffHTML writes the master data changes as an HTML document
+func WriteMasterDiffHTML(w io.Writer, diff *vc.MasterDiff) error {
+	io.WriteString(w, "<html><head><title>Master Data Changes</title>\n")
+	io.WriteString(w, "<style>table, th, td {border: 1px solid black;};</style>")
+	io.WriteString(w, "</head><body>\n")
+	writeMasterDiffBody(w, diff)
+	_, err := io.WriteString(w, "</body></html>")
+	return err
+}
+
+func writeMasterDiffBody(w io.Writer, diff *vc.MasterDiff) {
+	fmt.Fprintf(w, "<p>Version %d (%s) to %d (%s)</p>\n",
+		diff.OldVersion,
+		diff.OldTimestamp.Format(time.RFC3339),
+		diff.NewVersion,
+		diff.NewTimestamp.Format(time.RFC3339),
+	)
+	writeTableDiff(w, "Cards", "/cards/detail/", &diff.Cards)
+	writeTableDiff(w, "Skills", "", &diff.Skills)
+	writeTableDiff(w, "Events", "/events/detail/", &diff.Events)
+	writeTableDiff(w, "Items", "", &diff.Items)
+	writeTableDiff(w, "Weapons", "/weapons/detail/", &diff.Weapons)
+
+	io.WriteString(w, "<h2>Strings</h2>\n")
+	if len(diff.Strings) == 0 {
+		io.WriteString(w, "<p>No changes</p>\n")
+	}
+	for _, sd := range diff.Strings {
+		fmt.Fprintf(w, "<h3>%s</h3>\n", html.EscapeString(sd.File))
+		io.WriteString(w, "<table><thead><tr><th>Line</th><th>Change</th><th>Old</th><th>New</th></tr></thead><tbody>\n")
+		for _, change := range []struct {
+			name  string
+			lines []vc.StringDiff
+		}{{"added", sd.Added}, {"removed", sd.Removed}, {"changed", sd.Changed}} {
+			for _, l := range change.lines {
+				fmt.Fprintf(w, "<tr><td>%d</td><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+					l.Line,
+					change.name,
+					html.EscapeString(l.Old),
+					html.EscapeString(l.New),
+				)
+			}
+		}
+		io.WriteString(w, "</tbody></table>\n")
+	}
+}
+
+func writeTableDiff(w io.Writer, title string, link string, td *vc.TableDiff) {
+	fmt.Fprintf(w, "<h2>%s</h2>\n", title)
+	if td.IsEmpty() {
+		io.WriteString(w, "<p>No changes</p>\n")
+		return
+	}
+	recordName := func(rd vc.RecordDiff) string {
+		name := html.EscapeString(rd.Name)
+		if link != "" {
+			return fmt.Sprintf(`<a href="%s%d">%d</a> %s`, link, rd.ID, rd.ID, name)
+		}
+		return fmt.Sprintf("%d %s", rd.ID, name)
+	}
+	io.WriteString(w, "<table><thead><tr><th>_id</th><th>Change</th><th>Field</th><th>Old</th><th>New</th></tr></thead><tbody>\n")
+	for _, rd := range td.Added {
+		fmt.Fprintf(w, "<tr><td>%s</td><td>added</td><td></td><td></td><td></td></tr>\n", recordName(rd))
+	}
+	for _, rd := range td.Removed {
+		fmt.Fprintf(w, "<tr><td>%s</td><td>removed</td><td></td><td></td><td></td></tr>\n", recordName(rd))
+	}
+	for _, rd := range td.Changed {
+		fields := make([]string, 0, len(rd.Fields))
+		for k := range rd.Fields {
+			fields = append(fields, k)
+		}
+		sort.Strings(fields)
+		for _, k := range fields {
+			fmt.Fprintf(w, "<tr><td>%s</td><td>changed</td><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+				recordName(rd),
+				html.EscapeString(k),
+				html.EscapeString(rd.Fields[k].Old),
+				html.EscapeString(rd.Fields[k].New),
+			)
+		}
+	}
+	io.WriteString(w, "</tbody></table>\n")
+}
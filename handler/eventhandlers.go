@@ -15,7 +15,7 @@ import (
 )
 
 // EventHandler handle event information
-func EventHandler(w http.ResponseWriter, r *http.Request) {
+func EventHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	qs := r.URL.Query()
 	qEventType := qs.Get("eventType")
 	eventTypeID, _ := strconv.Atoi(qEventType)
@@ -85,8 +85,8 @@ func EventHandler(w http.ResponseWriter, r *http.Request) {
 	io.WriteString(w, "<th>_id</th><th>Event Name</th><th>Event Type</th><th>Start Date</th><th>End Date</th><th>King Series</th><th>Guild Battle</th><th>Tower Event</th><th>DRV</th><th>Weapon</th>\n")
 	io.WriteString(w, "</tr></thead>\n")
 	io.WriteString(w, "<tbody>\n")
-	for i := len(vc.Data.Events) - 1; i >= 0; i-- {
-		e := vc.Data.Events[i]
+	for i := len(ds.Data.Events) - 1; i >= 0; i-- {
+		e := ds.Data.Events[i]
 		if !filter(&e) {
 			continue
 		}
@@ -118,7 +118,7 @@ func EventHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // EventDetailHandler show details for a single event
-func EventDetailHandler(w http.ResponseWriter, r *http.Request) {
+func EventDetailHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	path := r.URL.Path
 	var pathLen int
 	if path[len(path)-1] == '/' {
@@ -139,12 +139,12 @@ func EventDetailHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	event := vc.EventScan(eventID)
+	event := ds.EventScan(eventID)
 
 	var prevEvent, nextEvent *vc.Event = nil, nil
 
 	for i := event.ID - 1; i > 0; i-- {
-		tmp := vc.EventScan(i)
+		tmp := ds.EventScan(i)
 		if tmp != nil && tmp.EventTypeID == event.EventTypeID && !strings.Contains(tmp.Name, "Rune Boss") && !strings.Contains(tmp.Name, " 2x ") {
 			prevEvent = tmp
 			break
@@ -156,8 +156,8 @@ func EventDetailHandler(w http.ResponseWriter, r *http.Request) {
 		prevEventName = cleanEventName(prevEvent)
 	}
 
-	for i := event.ID + 1; i <= vc.MaxEventID(vc.Data.Events); i++ {
-		tmp := vc.EventScan(i)
+	for i := event.ID + 1; i <= vc.MaxEventID(ds.Data.Events); i++ {
+		tmp := ds.EventScan(i)
 		if tmp != nil && tmp.EventTypeID == event.EventTypeID && !strings.Contains(tmp.Name, "Rune Boss") && !strings.Contains(tmp.Name, " 2x ") {
 			nextEvent = tmp
 			break
@@ -204,7 +204,7 @@ func EventDetailHandler(w http.ResponseWriter, r *http.Request) {
 		var aws string
 
 		for _, aw := range event.Archwitches() {
-			cardMaster := vc.CardScan(aw.CardMasterID)
+			cardMaster := ds.CardScan(aw.CardMasterID)
 			if aw.IsLAW() {
 				legendary = cardMaster.Name
 			} else if aw.IsFAW() {
@@ -234,13 +234,13 @@ func EventDetailHandler(w http.ResponseWriter, r *http.Request) {
 				midCaption := fmt.Sprintf("Mid Rankings<br /><small>Cutoff@ %s (JST)</small>",
 					midRewardTime.Format(wikiFmt),
 				)
-				midrewards = genWikiAWRewards(mid, midCaption, "Rank")
+				midrewards = genWikiAWRewards(mid, midCaption, "Rank", ds)
 			}
 			finalRewardList := rr.FinalRewards()
-			finalrewards = genWikiAWRewards(finalRewardList, "Final Rankings", "Rank")
+			finalrewards = genWikiAWRewards(finalRewardList, "Final Rankings", "Rank", ds)
 			for _, fr := range finalRewardList {
 				if fr.CardID > 0 {
-					rrCard := vc.CardScan(fr.CardID)
+					rrCard := ds.CardScan(fr.CardID)
 					rankReward = rrCard.Name
 					break
 				}
@@ -269,7 +269,7 @@ func EventDetailHandler(w http.ResponseWriter, r *http.Request) {
 		// rewards
 		//rr := event.RankRewards()
 		//finalRewardList := rr.FinalRewards()
-		//finalrewards := genWikiAWRewards(finalRewardList, "Ranking")
+		//finalrewards := genWikiAWRewards(finalRewardList, "Ranking", ds)
 		gb := event.GuildBattle()
 		bb := gb.BingoBattle()
 
@@ -278,20 +278,20 @@ func EventDetailHandler(w http.ResponseWriter, r *http.Request) {
 		//log.Printf("found %d archwitches on guild battle %d king series id %d\n", len(aws), bb.ID, bb.KingSeriesID)
 		// if len(aws) > 0 {
 		// 	king := aws[0]
-		// 	kingCard := vc.CardScan(king.CardMasterID, )
+		// 	kingCard := ds.CardScan(king.CardMasterID, )
 		// 	aw = kingCard.Name
 		// 	if len(aws) > 1 {
 		// 		// append extra AW cards
 		// 		for i := 1; i < len(aws); i++ {
 		// 			king = aws[i]
-		// 			kingCard = vc.CardScan(king.CardMasterID, )
+		// 			kingCard = ds.CardScan(king.CardMasterID, )
 		// 			aw += " |Archwitch Panel Encounter\n| " + kingCard.Name
 		// 		}
 		// 	}
 		// }
 
-		rankRewards := genWikiAWRewards(gb.RankRewards(), "Ranking", "Rank") +
-			genWikiAWRewards(gb.IndividualRewards(), "Point Reward", "Points")
+		rankRewards := genWikiAWRewards(gb.RankRewards(), "Ranking", "Rank", ds) +
+			genWikiAWRewards(gb.IndividualRewards(), "Point Reward", "Points", ds)
 
 		fmt.Fprintf(w, getEventTemplate(event.EventTypeID), event.EventTypeID,
 			event.StartDatetime.Format(wikiFmt),
@@ -307,8 +307,8 @@ func EventDetailHandler(w http.ResponseWriter, r *http.Request) {
 			nth(event.GuildBattleID-32), // Guild Battle Number spelled out (first, second, third, etc)
 			"",                          // Overlap AW Event
 			html.EscapeString(strings.ReplaceAll(event.Description, "\n", "\n\n")),
-			genWikiExchange(bb.ExchangeRewards()), // Ring Exchange
-			rankRewards,                           // Rewards (combined)
+			genWikiExchange(bb.ExchangeRewards(), ds), // Ring Exchange
+			rankRewards, // Rewards (combined)
 			prevEventName,
 			nextEventName,
 		)
@@ -336,9 +336,9 @@ func EventDetailHandler(w http.ResponseWriter, r *http.Request) {
 				hasStory,
 				hasEnemySymbol,
 				html.EscapeString(strings.ReplaceAll(event.Description, "\n", "\n\n")),
-				genWikiAWRewards(tower.ArrivalRewards(), "Floor Arrival Rewards", "Floor"), // RR 1
-				genWikiAWRewards(tower.RankRewards(), "Rank Rewards", "Rank"),              // RR 2
-				genWikiRankTrend(event, nil, time.Unix(0, 0), ranks, true),                 // rank trend
+				genWikiAWRewards(tower.ArrivalRewards(), "Floor Arrival Rewards", "Floor", ds), // RR 1
+				genWikiAWRewards(tower.RankRewards(), "Rank Rewards", "Rank", ds),              // RR 2
+				genWikiRankTrend(event, nil, time.Unix(0, 0), ranks, true),                     // rank trend
 				prevEventName,
 				nextEventName,
 			)
@@ -367,9 +367,9 @@ func EventDetailHandler(w http.ResponseWriter, r *http.Request) {
 				hasStory,
 				hasEnemySymbol,
 				html.EscapeString(event.Description),
-				genWikiAWRewards(realm.ArrivalRewards(), "Point Rewards", "Floor"), // RR 1
-				genWikiAWRewards(realm.RankRewards(), "Rank Rewards", "Rank"),      // RR 2
-				genWikiRankTrend(event, nil, time.Unix(0, 0), ranks, true),         // rank trend
+				genWikiAWRewards(realm.ArrivalRewards(), "Point Rewards", "Floor", ds), // RR 1
+				genWikiAWRewards(realm.RankRewards(), "Rank Rewards", "Rank", ds),      // RR 2
+				genWikiRankTrend(event, nil, time.Unix(0, 0), ranks, true),             // rank trend
 				prevEventName,
 				nextEventName,
 			)
@@ -396,9 +396,9 @@ func EventDetailHandler(w http.ResponseWriter, r *http.Request) {
 				hasStory,
 				hasEnemySymbol,
 				html.EscapeString(event.Description),
-				genWikiAWRewards(we.ArrivalRewards(), "Point Rewards", "Point"), // RR 1
-				genWikiAWRewards(we.RankRewards(), "Rank Rewards", "Rank"),      // RR 2
-				genWikiRankTrend(event, nil, time.Unix(0, 0), ranks, true),      // rank trend
+				genWikiAWRewards(we.ArrivalRewards(), "Point Rewards", "Point", ds), // RR 1
+				genWikiAWRewards(we.RankRewards(), "Rank Rewards", "Rank", ds),      // RR 2
+				genWikiRankTrend(event, nil, time.Unix(0, 0), ranks, true),          // rank trend
 				prevEventName,
 				nextEventName,
 			)
@@ -455,7 +455,7 @@ func cleanEventName(event *vc.Event) string {
 	return name
 }
 
-func genWikiAWRewards(rewards []vc.RankRewardSheet, caption string, rankTitle string) string {
+func genWikiAWRewards(rewards []vc.RankRewardSheet, caption string, rankTitle string, ds *vc.Dataset) string {
 	ret := `
 {| border="1" cellpadding="1" cellspacing="1" class="mw-collapsible mw-collapsed article-table" style="float:left"
 |-
@@ -491,7 +491,7 @@ func genWikiAWRewards(rewards []vc.RankRewardSheet, caption string, rankTitle st
 					rewardList = ""
 				}
 			}
-			rewardList += getWikiAWRewards(reward, rewardList != "")
+			rewardList += getWikiAWRewards(reward, rewardList != "", ds)
 			if reward.Point > 0 {
 				ret += fmt.Sprintf(prange, reward.Point, rewardList)
 			} else {
@@ -513,14 +513,14 @@ func genWikiAWRewards(rewards []vc.RankRewardSheet, caption string, rankTitle st
 				rewardList = ""
 			}
 			newline := rewardList != ""
-			rewardList += getWikiAWRewards(reward, newline)
+			rewardList += getWikiAWRewards(reward, newline, ds)
 		}
 	}
 
 	return ret + "|}\n"
 }
 
-func getWikiAWRewards(reward vc.RankRewardSheet, newline bool) string {
+func getWikiAWRewards(reward vc.RankRewardSheet, newline bool, ds *vc.Dataset) string {
 	rlist := "%s x%d"
 	if newline {
 		rlist = "<br />" + rlist
@@ -528,7 +528,7 @@ func getWikiAWRewards(reward vc.RankRewardSheet, newline bool) string {
 
 	var r string
 	if reward.CardID > 0 {
-		card := vc.CardScan(reward.CardID)
+		card := ds.CardScan(reward.CardID)
 		if card == nil {
 			r = "{{Card Icon|Unknown Card ID}}"
 		} else {
@@ -537,7 +537,7 @@ func getWikiAWRewards(reward vc.RankRewardSheet, newline bool) string {
 		return fmt.Sprintf(rlist, r, reward.Num)
 	}
 	if reward.ItemID > 0 {
-		item := vc.ItemScan(reward.ItemID)
+		item := ds.ItemScan(reward.ItemID)
 		if item == nil {
 			r = fmt.Sprintf("__UNKNOWN_ITEM_ID:%d__", reward.ItemID)
 		} else {
@@ -878,7 +878,7 @@ func genWikiRankTrend(event *vc.Event, eventMap *vc.Map, midRewardTime time.Time
 	return
 }
 
-func genWikiExchange(exchanges []vc.GuildBingoExchangeReward) (ret string) {
+func genWikiExchange(exchanges []vc.GuildBingoExchangeReward, ds *vc.Dataset) (ret string) {
 	ret = `{| class="article-table mw-collapsible mw-collapsed sortable" border="1" cellpadding="1" cellspacing="1" style="width:400px;"
 |-
 ! scope="col" |Prize
@@ -899,7 +899,7 @@ func genWikiExchange(exchanges []vc.GuildBingoExchangeReward) (ret string) {
 		itemSortCode := fmt.Sprintf("%02d", (10 - exchange.RewardType))
 		switch exchange.RewardType {
 		case 1: // card
-			card := vc.CardScan(exchange.RewardID)
+			card := ds.CardScan(exchange.RewardID)
 			if card == nil {
 				ret += fmt.Sprintf("\n|-\n|data-sort-value=\"%s\"| {{Card Icon|%s}} ||data-sort-value=%d| x%[3]d",
 					"-999",
@@ -915,7 +915,7 @@ func genWikiExchange(exchanges []vc.GuildBingoExchangeReward) (ret string) {
 				)
 			}
 		case 2: //item
-			item := vc.ItemScan(exchange.RewardID)
+			item := ds.ItemScan(exchange.RewardID)
 			if item == nil {
 				ret += fmt.Sprintf("\n|-\n|data-sort-value=\"%s\"|__UNKNOWN_ITEM_ID:%d__ ||data-sort-value=%d| x%[3]d",
 					itemSortCode,
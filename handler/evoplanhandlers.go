@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strconv"
+
+	"vc_file_grouper/vc"
+)
+
+// EvoPlanHandler calculates an evolution plan made of the materials that are really owned.
+// Use ?card=id to start from the standard plan for a card, and post the plan JSON in the
+// "plan" field to calculate it. Add format=json to get the calculated plan as JSON.
+func EvoPlanHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
+	var plan *vc.EvoPlanNode
+	var planErr error
+	if p := r.FormValue("plan"); p != "" {
+		plan = &vc.EvoPlanNode{}
+		planErr = json.Unmarshal([]byte(p), plan)
+	} else if id, err := strconv.Atoi(r.FormValue("card")); err == nil {
+		card := ds.CardScan(id)
+		if card == nil {
+			http.Error(w, "Card not found", http.StatusNotFound)
+			return
+		}
+		plan = card.DefaultEvoPlan()
+	}
+	if plan != nil && planErr == nil {
+		planErr = ds.PlanEvolution(plan)
+	}
+
+	if r.FormValue("format") == "json" {
+		if plan == nil || planErr != nil {
+			msg := "A plan or card is required"
+			if planErr != nil {
+				msg = planErr.Error()
+			}
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "\t")
+		enc.Encode(plan)
+		return
+	}
+
+	planJSON := ""
+	if plan != nil {
+		if planErr != nil {
+			planJSON = r.FormValue("plan")
+		} else {
+			b, _ := json.MarshalIndent(plan, "", "  ")
+			planJSON = string(b)
+		}
+	}
+
+	io.WriteString(w, "<html><head><title>Evolution Planner</title>\n")
+	io.WriteString(w, "<style>table, th, td {border: 1px solid black;};</style>")
+	io.WriteString(w, "</head><body>\n")
+	fmt.Fprintf(w, `<h1>Evolution Planner</h1>
+<form method="get">
+<label for="f_card">Start from the standard plan for card ID</label>
+<input id="f_card" name="card" value="%s"/>
+<button type="submit">Load</button>
+</form>
+<form method="post">
+<p>Each card has a <code>cardId</code>. Owned cards can have their current <code>stats</code> (<code>{"Attack": 1, "Defense": 1, "Soldiers": 1}</code>),
+otherwise the level 1 stats are used, or the max level stats with <code>"maxLevel": true</code>.
+Cards with <code>materials</code> are made from them by evolution (2 cards of the same character), amalgamation, or awakening and rebirth (1 card).
+<code>"maxLevel": true</code> on a made card levels it to max before it is used.</p>
+<textarea name="plan" rows="25" cols="100">%s</textarea><br />
+<button type="submit">Calculate</button>
+<button type="submit" name="format" value="json">Calculate as JSON</button>
+</form>
+<p><a href="/">back</a></p>
+`,
+		html.EscapeString(r.FormValue("card")),
+		html.EscapeString(planJSON),
+	)
+	if planErr != nil {
+		fmt.Fprintf(w, "<p>%s</p>\n", html.EscapeString(planErr.Error()))
+	} else if plan != nil {
+		io.WriteString(w, "<table><thead><tr><th>Step</th><th>Card</th><th>Method</th><th>Level</th><th>Materials</th><th>ATK</th><th>DEF</th><th>SOL</th></tr></thead><tbody>\n")
+		steps := make(map[*vc.EvoPlanNode]int)
+		for i, n := range plan.Nodes() {
+			steps[n] = i + 1
+			materials := ""
+			for j, m := range n.Materials {
+				if j > 0 {
+					materials += ", "
+				}
+				materials += strconv.Itoa(steps[m])
+			}
+			level := "1"
+			if n.MaxLevel {
+				level = "Max"
+			}
+			fmt.Fprintf(w, "<tr><td>%d</td><td><a href=\"/cards/detail/%d\">%s</a> (%s)</td><td>%s</td><td>%s</td><td>%s</td><td>%d</td><td>%d</td><td>%d</td></tr>\n",
+				i+1,
+				n.CardID,
+				html.EscapeString(n.Name),
+				html.EscapeString(n.Card.Rarity()),
+				n.Method,
+				level,
+				materials,
+				n.Result.Attack,
+				n.Result.Defense,
+				n.Result.Soldiers,
+			)
+		}
+		io.WriteString(w, "</tbody></table>\n")
+	}
+	io.WriteString(w, "</body></html>")
+}
@@ -19,7 +19,7 @@ import (
 )
 
 // StructureListHandler show structures as a list
-func StructureListHandler(w http.ResponseWriter, r *http.Request) {
+func StructureListHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	io.WriteString(w, "<html><head><title>Structures</title>\n")
 	io.WriteString(w, "<style>table, th, td {border: 1px solid black;};</style>")
 	io.WriteString(w, "</head><body>\n")
@@ -38,7 +38,7 @@ func StructureListHandler(w http.ResponseWriter, r *http.Request) {
 `)
 	io.WriteString(w, "</tr></thead>\n")
 	io.WriteString(w, "<tbody>\n")
-	for _, s := range vc.Data.Structures {
+	for _, s := range ds.Data.Structures {
 		fmt.Fprintf(w, `<tr>
 	<td><a href="/garden/structures/detail/%[1]d">%[1]d</a></td>
 	<td>%[2]s</td>
@@ -67,7 +67,7 @@ func StructureListHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // StructureDetailHandler show details for a single structure
-func StructureDetailHandler(w http.ResponseWriter, r *http.Request) {
+func StructureDetailHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	path := r.URL.Path
 	var pathLen int
 	if path[len(path)-1] == '/' {
@@ -88,7 +88,7 @@ func StructureDetailHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	structure := vc.StructureScan(structureID)
+	structure := ds.StructureScan(structureID)
 	if structure == nil {
 		http.Error(w, "Structure not found with id "+pathParts[3], http.StatusNotFound)
 		return
@@ -159,7 +159,7 @@ func StructureDetailHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // StructureImagesHandler show structure images
-func StructureImagesHandler(w http.ResponseWriter, r *http.Request) {
+func StructureImagesHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	path := r.URL.Path
 	var pathLen int
 	if path[len(path)-1] == '/' {
@@ -175,7 +175,7 @@ func StructureImagesHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	gardenBin := filepath.Join(vc.FilePath, "garden", "map_01.bin")
+	gardenBin := filepath.Join(ds.FilePath, "garden", "map_01.bin")
 	log.Printf("reading garden image file\n")
 	images, err := vc.ReadBinFileImages(gardenBin)
 	limages := len(images)
@@ -334,7 +334,7 @@ Max quantity: %d
 	}
 	areaReq := ""
 	if structure.UnlockAreaID > 0 {
-		areaReq = fmt.Sprintf("<br />Clear Area %s", vc.Data.Areas[structure.UnlockAreaID].Name)
+		areaReq = fmt.Sprintf("<br />Clear Area %s", structure.Dataset().Data.Areas[structure.UnlockAreaID].Name)
 	}
 	io.WriteString(w, lvlHeader)
 	levels := structure.Levels()
@@ -419,7 +419,7 @@ Max quantity: %d
 	}
 	areaReq := ""
 	if structure.UnlockAreaID > 0 {
-		areaReq = fmt.Sprintf("<br />Clear Area %s", vc.Data.Areas[structure.UnlockAreaID].Name)
+		areaReq = fmt.Sprintf("<br />Clear Area %s", structure.Dataset().Data.Areas[structure.UnlockAreaID].Name)
 	}
 	io.WriteString(w, lvlHeader)
 	levels := structure.Levels()
@@ -540,7 +540,7 @@ Max quantity: %d
 	}
 	areaReq := ""
 	if structure.UnlockAreaID > 0 {
-		areaReq = fmt.Sprintf("<br />Clear Area %s", vc.Data.Areas[structure.UnlockAreaID].Name)
+		areaReq = fmt.Sprintf("<br />Clear Area %s", structure.Dataset().Data.Areas[structure.UnlockAreaID].Name)
 	}
 	io.WriteString(w, lvlHeader)
 	for _, l := range levels {
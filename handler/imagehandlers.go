@@ -16,40 +16,40 @@ import (
 )
 
 // ImageCardSDHandler show SD card images
-func ImageCardSDHandler(w http.ResponseWriter, r *http.Request) {
-	//vc.FilePath+"/card/sd"
-	serveCardImage(filepath.Join(vc.FilePath, "card", "sd"), "/images/cardSD/", w, r)
+func ImageCardSDHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
+	//ds.FilePath+"/card/sd"
+	serveCardImage(filepath.Join(ds.FilePath, "card", "sd"), "/images/cardSD/", w, r, ds)
 }
 
 // ImageCardHandler show MD card images
-func ImageCardHandler(w http.ResponseWriter, r *http.Request) {
-	//vc.FilePath+"/card/md"
-	serveCardImage(filepath.Join(vc.FilePath, "card", "md"), "/images/card/", w, r)
+func ImageCardHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
+	//ds.FilePath+"/card/md"
+	serveCardImage(filepath.Join(ds.FilePath, "card", "md"), "/images/card/", w, r, ds)
 }
 
 // ImageCardThumbHandler show thumbnail card images
-func ImageCardThumbHandler(w http.ResponseWriter, r *http.Request) {
-	//vc.FilePath+"/card/thumb"
-	serveCardImage(filepath.Join(vc.FilePath, "card", "thumb"), "/images/cardthumb/", w, r)
+func ImageCardThumbHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
+	//ds.FilePath+"/card/thumb"
+	serveCardImage(filepath.Join(ds.FilePath, "card", "thumb"), "/images/cardthumb/", w, r, ds)
 }
 
 // ImageCardHDHandler show HD card images
-func ImageCardHDHandler(w http.ResponseWriter, r *http.Request) {
-	//vc.FilePath+"/card/hd"
-	serveCardImage(filepath.Join(vc.FilePath, "card", "hd"), "/images/cardHD/", w, r)
+func ImageCardHDHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
+	//ds.FilePath+"/card/hd"
+	serveCardImage(filepath.Join(ds.FilePath, "card", "hd"), "/images/cardHD/", w, r, ds)
 }
 
 // ImageHandlerFor handles images under a specified path
-func ImageHandlerFor(urlPath string, imageDir string) func(http.ResponseWriter, *http.Request) {
-	return func(w http.ResponseWriter, r *http.Request) {
-		//vc.FilePath+"/event"
-		servImageDir(w, r, urlPath, imageDir)
+func ImageHandlerFor(urlPath string, imageDir string) DatasetHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
+		//ds.FilePath+"/event"
+		servImageDir(w, r, ds, urlPath, imageDir)
 	}
 }
 
 type fileFilterFunc func(os.FileInfo) bool
 
-func servImageDir(w http.ResponseWriter, r *http.Request, urlPath string, root string, filters ...fileFilterFunc) {
+func servImageDir(w http.ResponseWriter, r *http.Request, ds *vc.Dataset, urlPath string, root string, filters ...fileFilterFunc) {
 	imgname := filepath.FromSlash(r.URL.Path)
 	urlRoot := path.Join("/images", urlPath)
 	urlRoot = filepath.FromSlash(urlRoot)
@@ -74,7 +74,7 @@ func servImageDir(w http.ResponseWriter, r *http.Request, urlPath string, root s
 			"\nRelative path modification not allowed", http.StatusNotFound)
 		return
 	}
-	fullpath := path.Join(vc.FilePath, root, imgname)
+	fullpath := path.Join(ds.FilePath, root, imgname)
 
 	finfo, err := os.Stat(fullpath)
 	if err != nil {
@@ -144,9 +144,9 @@ func servImageDir(w http.ResponseWriter, r *http.Request, urlPath string, root s
 	http.Error(w, "Invalid Image location "+imgname, http.StatusNotFound)
 }
 
-func checkImageName(info os.FileInfo) bool {
+func checkImageName(info os.FileInfo, ds *vc.Dataset) bool {
 	imageName := info.Name()
-	for _, card := range vc.Data.Cards {
+	for _, card := range ds.Data.Cards {
 		if card.Image() == imageName {
 			return false
 		}
@@ -154,14 +154,16 @@ func checkImageName(info os.FileInfo) bool {
 	return true
 }
 
-func serveCardImage(imagePath string, urlprefix string, w http.ResponseWriter, r *http.Request) {
+func serveCardImage(imagePath string, urlprefix string, w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	imgname := r.URL.Path[len(urlprefix):]
 	qs := r.URL.Query()
 	if imgname == "" || imgname == "/" || strings.HasPrefix(imgname, "../") {
 		if len(qs) > 0 {
 			if unused := qs.Get("unused"); unused != "" {
-				relPath, _ := filepath.Rel(vc.FilePath, imagePath)
-				servImageDir(w, r, strings.TrimPrefix(urlprefix, "/images"), relPath, checkImageName)
+				relPath, _ := filepath.Rel(ds.FilePath, imagePath)
+				servImageDir(w, r, ds, strings.TrimPrefix(urlprefix, "/images"), relPath, func(info os.FileInfo) bool {
+					return checkImageName(info, ds)
+				})
 				return
 			}
 		}
@@ -201,7 +203,7 @@ func serveCardImage(imagePath string, urlprefix string, w http.ResponseWriter, r
 		cardID = imgname[3:]
 	}
 
-	card := vc.CardScanImage(cardID)
+	card := ds.CardScanImage(cardID)
 	ext := ".png"
 	isIcon := false
 	if strings.Contains(fullpath, filepath.FromSlash("/thumb/")) {
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+
+	"vc_file_grouper/vc"
+)
+
+// IntegrityHandler checks the references between the master data tables.
+// Use ?format=json for JSON output
+func IntegrityHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
+	report := ds.ValidateIntegrity()
+	if r.FormValue("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		WriteIntegrityJSON(w, report)
+		return
+	}
+	WriteIntegrityHTML(w, report)
+}
+
+// WriteIntegrityJSON writes the integrity report as JSON
+func WriteIntegrityJSON(w io.Writer, report *vc.IntegrityReport) error {
+	b, err := json.MarshalIndent(report, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// WriteIntegrityHTML writes the integrity report as an HTML document
+func WriteIntegrityHTML(w io.Writer, report *vc.IntegrityReport) error {
+	io.WriteString(w, "<html><head><title>Data Integrity</title>\n")
+	io.WriteString(w, "<style>table, th, td {border: 1px solid black;};</style>")
+	io.WriteString(w, "</head><body>\n")
+	fmt.Fprintf(w, "<h1>Data Integrity</h1>\n<p>Checked %d references and found %d problems. <a href=\"?format=json\">JSON</a></p>\n",
+		report.Checked,
+		len(report.Issues),
+	)
+	for _, category := range report.Categories() {
+		issues := report.Category(category)
+		fmt.Fprintf(w, "<h2>%s (%d)</h2>\n", html.EscapeString(category), len(issues))
+		io.WriteString(w, "<table><thead><tr><th>Table</th><th>Record</th><th>Field</th><th>Reference</th><th>Problem</th></tr></thead><tbody>\n")
+		for _, issue := range issues {
+			fmt.Fprintf(w, "<tr><td>%s</td><td>%d</td><td>%s</td><td>%d</td><td>%s</td></tr>\n",
+				html.EscapeString(issue.Table),
+				issue.RecordID,
+				html.EscapeString(issue.Field),
+				issue.RefID,
+				html.EscapeString(issue.Problem),
+			)
+		}
+		io.WriteString(w, "</tbody></table>\n")
+	}
+	_, err := io.WriteString(w, "</body></html>")
+	return err
+}
@@ -11,7 +11,7 @@ import (
 )
 
 // ItemHandler shows item details as a table
-func ItemHandler(w http.ResponseWriter, r *http.Request) {
+func ItemHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	io.WriteString(w, "<html><head><title>All Items</title>\n")
 	io.WriteString(w, "<style>table, th, td {border: 1px solid black;};</style>")
 	io.WriteString(w, "</head><body>\n")
@@ -20,8 +20,8 @@ func ItemHandler(w http.ResponseWriter, r *http.Request) {
 	io.WriteString(w, "<th>_id</th><th>Item Name</th><th>Image</th><th>Description</th><th>Group</th><th>End Date</th><th>Max Own</th><th>Limited Item</th><th>Is Deleted</th>\n")
 	io.WriteString(w, "</tr></thead>\n")
 	io.WriteString(w, "<tbody>\n")
-	for i := len(vc.Data.Items) - 1; i >= 0; i-- {
-		e := vc.Data.Items[i]
+	for i := len(ds.Data.Items) - 1; i >= 0; i-- {
+		e := ds.Data.Items[i]
 		fmt.Fprintf(w, "<tr>"+
 			"<td>%d</td>"+
 			"<td>%s<br />%s</td>"+
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"vc_file_grouper/vc"
+)
+
+// LevelUpHandler calculates the EXP materials give and the cost of levelling up a card.
+// Use ?card=id&current=level&goal=level and list the materials in "fodder", one per line as
+// "card id [level] [count]". Add format=json to get the result as JSON.
+func LevelUpHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
+	var plan *vc.LevelUpPlan
+	var planErr error
+	if r.FormValue("card") != "" {
+		plan, planErr = parseLevelUpForm(r)
+		if planErr == nil {
+			planErr = ds.PlanLevelUp(plan)
+		}
+	}
+
+	if r.FormValue("format") == "json" {
+		if plan == nil || planErr != nil {
+			msg := "A card is required"
+			if planErr != nil {
+				msg = planErr.Error()
+			}
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "\t")
+		enc.Encode(plan)
+		return
+	}
+
+	io.WriteString(w, "<html><head><title>Level Up Calculator</title>\n")
+	io.WriteString(w, "<style>table, th, td {border: 1px solid black;};</style>")
+	io.WriteString(w, "</head><body>\n")
+	fmt.Fprintf(w, `<h1>Level Up Calculator</h1>
+<form method="get">
+<label for="f_card">Card ID</label>
+<input id="f_card" name="card" value="%s"/><br />
+<label for="f_current">Current Level</label>
+<input id="f_current" name="current" value="%s"/><br />
+<label for="f_goal">Goal Level (max level if empty)</label>
+<input id="f_goal" name="goal" value="%s"/><br />
+<label for="f_fodder">Materials, one per line as: card id [level] [count]. Without a count, as many as needed are used</label><br />
+<textarea id="f_fodder" name="fodder" rows="10" cols="40">%s</textarea><br />
+<button type="submit">Calculate</button>
+<button type="submit" name="format" value="json">Calculate as JSON</button>
+</form>
+<p><a href="/cards/levels">Level tables</a> <a href="/">back</a></p>
+`,
+		html.EscapeString(r.FormValue("card")),
+		html.EscapeString(r.FormValue("current")),
+		html.EscapeString(r.FormValue("goal")),
+		html.EscapeString(r.FormValue("fodder")),
+	)
+	if planErr != nil {
+		fmt.Fprintf(w, "<p>%s</p>\n", html.EscapeString(planErr.Error()))
+	} else if plan != nil {
+		fmt.Fprintf(w, "<p><a href=\"/cards/detail/%d\">%s</a> (%s) from level %d to %d needs %d EXP. The materials give %d EXP and reach level %d.</p>\n",
+			plan.CardID,
+			html.EscapeString(plan.Name),
+			html.EscapeString(plan.Card.Rarity()),
+			plan.CurrentLevel,
+			plan.GoalLevel,
+			plan.ExpNeeded,
+			plan.ExpGained,
+			plan.ReachedLevel,
+		)
+		io.WriteString(w, "<table><thead><tr><th>Material</th><th>Level</th><th>EXP Each</th><th>Needed Alone</th><th>Available</th><th>Used</th></tr></thead><tbody>\n")
+		for _, f := range plan.Fodder {
+			available := "any"
+			if f.Count > 0 {
+				available = strconv.Itoa(f.Count)
+			}
+			fmt.Fprintf(w, "<tr><td><a href=\"/cards/detail/%d\">%s</a> (%s)</td><td>%d</td><td>%d</td><td>%d</td><td>%s</td><td>%d</td></tr>\n",
+				f.CardID,
+				html.EscapeString(f.Name),
+				html.EscapeString(f.Card.Rarity()),
+				f.Level,
+				f.Exp,
+				f.NeededAlone,
+				available,
+				f.Used,
+			)
+		}
+		io.WriteString(w, "</tbody></table>\n")
+		fmt.Fprintf(w, "<p>Cost: %d Gold, %d Iron, %d Ether, %d Elixir</p>\n",
+			plan.Cost.Gold,
+			plan.Cost.Iron,
+			plan.Cost.Ether,
+			plan.Cost.Elixir,
+		)
+	}
+	io.WriteString(w, "</body></html>")
+}
+
+// parseLevelUpForm reads the level up calculator form
+func parseLevelUpForm(r *http.Request) (*vc.LevelUpPlan, error) {
+	plan := &vc.LevelUpPlan{Fodder: make([]*vc.LevelUpFodder, 0)}
+	var err error
+	if plan.CardID, err = strconv.Atoi(r.FormValue("card")); err != nil {
+		return nil, fmt.Errorf("invalid card id %s", r.FormValue("card"))
+	}
+	if v := r.FormValue("current"); v != "" {
+		if plan.CurrentLevel, err = strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("invalid current level %s", v)
+		}
+	}
+	if v := r.FormValue("goal"); v != "" {
+		if plan.GoalLevel, err = strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("invalid goal level %s", v)
+		}
+	}
+	for _, line := range strings.Split(r.FormValue("fodder"), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		values := make([]int, len(fields))
+		for i, f := range fields {
+			if i > 2 {
+				return nil, fmt.Errorf("invalid material line %q", line)
+			}
+			if values[i], err = strconv.Atoi(f); err != nil {
+				return nil, fmt.Errorf("invalid material line %q", line)
+			}
+		}
+		fodder := &vc.LevelUpFodder{CardID: values[0]}
+		if len(values) > 1 {
+			fodder.Level = values[1]
+		}
+		if len(values) > 2 {
+			fodder.Count = values[2]
+		}
+		plan.Fodder = append(plan.Fodder, fodder)
+	}
+	return plan, nil
+}
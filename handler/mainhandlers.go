@@ -20,12 +20,13 @@ import (
 )
 
 // MasterDataHandler Main index page
-func MasterDataHandler(w http.ResponseWriter, r *http.Request) {
+func MasterDataHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 
 	// File header
 	fmt.Fprintf(w, `<html><body>
 <p>Version: %d,&nbsp;&nbsp;&nbsp;&nbsp;Timestamp: %d,&nbsp;&nbsp;&nbsp;&nbsp;JST: %s</p>
 <a href="/config/dataLoc">Configure Data Location</a><br />
+<a href="/config/overrides">Card Name Overrides</a><br />
 <a href="/config/setBotCreds">Set bot username and password</a>.
 If not set, you won't be able to automate updates to the wiki.
 Please create a "bot key" for your account by using the <a href="https://valkyriecrusade.fandom.com/wiki/Special:BotPasswords" target="_blank">Special:BotPasswords</a> page<br />
@@ -40,9 +41,14 @@ Please create a "bot key" for your account by using the <a href="https://valkyri
 <a href="/events/weaponScenario/">Weapon Scenario</a><br />
 <a href="/items">Item List</a><br />
 <a href="/deckbonus">Deck Bonuses</a><br />
+<a href="/deck/">Deck Builder</a><br />
 <a href="/maps">Map List</a><br />
 <a href="/archwitches">Archwitch List</a><br />
 <a href="/cards/levels">Card Levels</a><br />
+<a href="/cards/planner/">Evolution Planner</a><br />
+<a href="/cards/levelup/">Level Up Calculator</a><br />
+<a href="/cards/cost/">Card Cost from Scratch</a><br />
+<a href="/cards/skillcost/">Skill Cost Calculator</a><br />
 <a href="/garden/structures">Garden Structures</a><br />
 <a href="/characters">Character List as a Table</a><br />
 <a href="/thor">Thor Event List</a><br />
@@ -51,6 +57,10 @@ Formatted Data:<br />
 <a href="/cards/csv">Card List as CSV</a><br />
 <a href="/skills/csv">Skill List as CSV</a><br />
 <a href="/cards/glrcsv">GLR Card List as CSV</a> <a href="/cards/glrjson"> as JSON</a><br />
+JSON API: <a href="/api/v1/cards/">Cards</a> <a href="/api/v1/characters/">Characters</a> <a href="/api/v1/skills/">Skills</a>
+<a href="/api/v1/events/">Events</a> <a href="/api/v1/archwitches/">Archwitches</a> <a href="/api/v1/weapons/">Weapons</a>
+<a href="/api/v1/items/">Items</a> <a href="/api/v1/structures/">Structures</a> <a href="/api/v1/deckbonuses/">Deck Bonuses</a>
+<a href="/api/v1/thor/">Thor Events</a><br />
 <br />
 <a href="/strb/">Binary String files</a><br />
 <br />
@@ -74,8 +84,13 @@ Images:<br />
 <a href="/awakenings/csv">List of Awakenings as CSV</a><br />
 <a href="/raw">Raw data</a><br />
 <a href="/raw/KEYS">Raw data Keys</a><br />
+<a href="/raw/SCHEMA">Raw data Schema Drift</a><br />
+<a href="/validate/">Check Data Integrity</a><br />
+<a href="/diff">Compare Master Data Versions</a><br />
+<a href="/translations">Compare Translations</a><br />
 <br />
 <a href="/decode">Decode All Files</a><br />
+<a href="/encode">Encode a Changed File</a><br />
 <br />
 <a href="/zipData">Decode All Files and store them in a Zip archive</a><br />
 <br />
@@ -83,105 +98,92 @@ Images:<br />
 <br />
 <a href="/SHUTDOWN">SHUTDOWN</a><br />
 </body></html>`,
-		vc.Data.Version,
-		vc.Data.Common.UnixTime.Unix(),
-		vc.Data.Common.UnixTime.Format(time.RFC3339),
+		ds.Data.Version,
+		ds.Data.Common.UnixTime.Unix(),
+		ds.Data.Common.UnixTime.Format(time.RFC3339),
 	)
 	// io.WriteString(w, "<a href=\"/cards\">Card List</a><br />\n")
 }
 
-func ZipDataHandler(w http.ResponseWriter, r *http.Request) {
+// ZipDataHandler downloads the fan archive as a zip file
+func ZipDataHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	// Get a Buffer to Write To
 	w.Header().Set("Content-Disposition", "attachment; filename=\"Valkyrie Crusade Fan Archive - Final - "+time.Now().Format("2006-01-02")+".zip\"")
 	w.Header().Set("Content-Type", "application/zip")
 
+	err := WriteZipData(w, ds)
+	if err != nil {
+		log.Printf(err.Error() + "\n")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// WriteZipData writes the fan archive zip to the writer
+func WriteZipData(w io.Writer, ds *vc.Dataset) error {
 	// Create a new zip archive.
 	z := zip.NewWriter(w)
 
 	var err error
 
-	err = zipCards(z)
+	err = zipCards(z, ds)
 	if err != nil {
-		log.Printf("Card zip error: " + err.Error() + "\n")
-		http.Error(w, "Card zip error: "+err.Error(), http.StatusInternalServerError)
-		return
+		return fmt.Errorf("Card zip error: %w", err)
 	}
 
-	err = zipWeapons(z)
+	err = zipWeapons(z, ds)
 	if err != nil {
-		log.Printf("Weapon zip error: " + err.Error() + "\n")
-		http.Error(w, "Weapon zip error: "+err.Error(), http.StatusInternalServerError)
-		return
+		return fmt.Errorf("Weapon zip error: %w", err)
 	}
 
-	err = zipItems(z)
+	err = zipItems(z, ds)
 	if err != nil {
-		log.Printf("Item zip error: " + err.Error() + "\n")
-		http.Error(w, "Item zip error: "+err.Error(), http.StatusInternalServerError)
-		return
+		return fmt.Errorf("Item zip error: %w", err)
 	}
 
-	err = zipStructures(z)
+	err = zipStructures(z, ds)
 	if err != nil {
-		log.Printf("Structure zip error: " + err.Error() + "\n")
-		http.Error(w, "Structure zip error: "+err.Error(), http.StatusInternalServerError)
-		return
+		return fmt.Errorf("Structure zip error: %w", err)
 	}
 
-	err = zipTreasure(z)
+	err = zipTreasure(z, ds)
 	if err != nil {
-		log.Printf("Treasure zip error: " + err.Error() + "\n")
-		http.Error(w, "Treasure zip error: "+err.Error(), http.StatusInternalServerError)
-		return
+		return fmt.Errorf("Treasure zip error: %w", err)
 	}
 
-	err = zipAudio(z)
+	err = zipAudio(z, ds)
 	if err != nil {
-		log.Printf("Audio zip error: " + err.Error() + "\n")
-		http.Error(w, "Audio zip error: "+err.Error(), http.StatusInternalServerError)
-		return
+		return fmt.Errorf("Audio zip error: %w", err)
 	}
 
-	err = zipAlliance(z)
+	err = zipAlliance(z, ds)
 	if err != nil {
-		log.Printf("Alliance zip error: " + err.Error() + "\n")
-		http.Error(w, "Alliance zip error: "+err.Error(), http.StatusInternalServerError)
-		return
+		return fmt.Errorf("Alliance zip error: %w", err)
 	}
 
-	err = zipEventStory(z)
+	err = zipEventStory(z, ds)
 	if err != nil {
-		log.Printf("Story zip error: " + err.Error() + "\n")
-		http.Error(w, "Story zip error: "+err.Error(), http.StatusInternalServerError)
-		return
+		return fmt.Errorf("Story zip error: %w", err)
 	}
 
-	err = zipNavi(z)
+	err = zipNavi(z, ds)
 	if err != nil {
-		log.Printf("Navi zip error: " + err.Error() + "\n")
-		http.Error(w, "Navi zip error: "+err.Error(), http.StatusInternalServerError)
-		return
+		return fmt.Errorf("Navi zip error: %w", err)
 	}
 
-	err = zipGardenSprites(z)
+	err = zipGardenSprites(z, ds)
 	if err != nil {
-		log.Printf("Navi zip error: " + err.Error() + "\n")
-		http.Error(w, "Navi zip error: "+err.Error(), http.StatusInternalServerError)
-		return
+		return fmt.Errorf("Navi zip error: %w", err)
 	}
 
-	err = zipBattleImages(z) // dungeon, weapon event, battle backgrounds, battle maps
+	err = zipBattleImages(z, ds) // dungeon, weapon event, battle backgrounds, battle maps
 	if err != nil {
-		log.Printf("Navi zip error: " + err.Error() + "\n")
-		http.Error(w, "Navi zip error: "+err.Error(), http.StatusInternalServerError)
-		return
+		return fmt.Errorf("Navi zip error: %w", err)
 	}
 
-	err = zipApkImages(z)
+	err = zipApkImages(z, ds)
 	if err != nil {
-		log.Printf("ApkImages zip error: " + err.Error() + "\n")
-		http.Error(w, "ApkImages zip error: "+err.Error(), http.StatusInternalServerError)
-		return
+		return fmt.Errorf("ApkImages zip error: %w", err)
 	}
 
 	err = addFileToZip(z, "README.txt", nil, []byte(`Valkyrie Crusade Fan Archive
@@ -237,23 +239,16 @@ Some notes about this archive's structure:
 `))
 
 	if err != nil {
-		log.Printf("Readme zip error: " + err.Error() + "\n")
-		http.Error(w, "Readme zip error: "+err.Error(), http.StatusInternalServerError)
-		return
+		return fmt.Errorf("Readme zip error: %w", err)
 	}
 
-	err = z.Close()
-	if err != nil {
-		log.Printf(err.Error() + "\n")
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
+	return z.Close()
 }
 
-func zipCards(z *zip.Writer) error {
+func zipCards(z *zip.Writer, ds *vc.Dataset) error {
 	seen := make([]string, 0)
 	cardImageNames := make([]string, 0)
-	for _, cardEvos := range vc.Data.Cards.CardsByName() {
+	for _, cardEvos := range ds.Data.Cards.CardsByName() {
 		firstEvo := cardEvos.EarliestOpen()
 		if firstEvo == nil {
 			firstEvo = cardEvos.Earliest()
@@ -323,7 +318,7 @@ func zipCards(z *zip.Writer) error {
 		}
 	}
 
-	filepath.Walk(vc.FilePath+"/card", func(p string, info os.FileInfo, err error) (e error) {
+	filepath.Walk(ds.FilePath+"/card", func(p string, info os.FileInfo, err error) (e error) {
 		if err != nil {
 			return err
 		}
@@ -332,7 +327,7 @@ func zipCards(z *zip.Writer) error {
 		}
 
 		if !util.Contains(cardImageNames, info.Name()) {
-			relPath, err := filepath.Rel(vc.FilePath+"/card", p)
+			relPath, err := filepath.Rel(ds.FilePath+"/card", p)
 			if err != nil {
 				return err
 			}
@@ -346,7 +341,7 @@ func zipCards(z *zip.Writer) error {
 				return
 			}
 
-			uci := vc.CardScanImage(strings.TrimPrefix(info.Name(), "cd_"))
+			uci := ds.CardScanImage(strings.TrimPrefix(info.Name(), "cd_"))
 			if uci != nil {
 				relPath = strings.TrimSuffix(relPath, info.Name())
 				relPath += uci.Rarity() + " - " + uci.Name + " - " + info.Name()
@@ -366,8 +361,8 @@ func zipCards(z *zip.Writer) error {
 	return nil
 }
 
-func zipWeapons(z *zip.Writer) error {
-	for _, weapon := range vc.Data.Weapons {
+func zipWeapons(z *zip.Writer, ds *vc.Dataset) error {
+	for _, weapon := range ds.Data.Weapons {
 		wName := strings.Replace(weapon.MaxRarityName(), " (Weapon)", "", -1)
 		pathNameBase := "Weapons/" + wName + "/"
 		quotes := "Weapon Type: " + weapon.StatusDescription() + "\n"
@@ -401,9 +396,9 @@ func zipWeapons(z *zip.Writer) error {
 	return nil
 }
 
-func zipItems(z *zip.Writer) error {
+func zipItems(z *zip.Writer, ds *vc.Dataset) error {
 	seen := make([]string, 0)
-	for _, item := range vc.Data.Items {
+	for _, item := range ds.Data.Items {
 		pathNameBase := "Items/"
 		if group, ok := vc.ItemGroups[item.GroupID]; ok {
 			pathNameBase += group + "/"
@@ -429,8 +424,8 @@ func zipItems(z *zip.Writer) error {
 	return nil
 }
 
-func zipTreasure(z *zip.Writer) error {
-	return filepath.Walk(vc.FilePath+"/treasure/", func(p string, info os.FileInfo, err error) (e error) {
+func zipTreasure(z *zip.Writer, ds *vc.Dataset) error {
+	return filepath.Walk(ds.FilePath+"/treasure/", func(p string, info os.FileInfo, err error) (e error) {
 		if err != nil {
 			return err
 		}
@@ -438,7 +433,7 @@ func zipTreasure(z *zip.Writer) error {
 			return nil
 		}
 
-		relPath, err := filepath.Rel(vc.FilePath+"/treasure/", p)
+		relPath, err := filepath.Rel(ds.FilePath+"/treasure/", p)
 		if err != nil {
 			return err
 		}
@@ -464,9 +459,9 @@ func zipTreasure(z *zip.Writer) error {
 	})
 }
 
-func zipStructures(z *zip.Writer) error {
+func zipStructures(z *zip.Writer, ds *vc.Dataset) error {
 	seen := make([]string, 0)
-	for _, structure := range vc.Data.Structures {
+	for _, structure := range ds.Data.Structures {
 		group := vc.ShopGroup[structure.ShopGroupDecoID]
 		if strings.HasPrefix(structure.Name, "Honorable Plaque") || strings.HasPrefix(structure.Name, "Shield of Honor") {
 			group = "Special/Honorable Plaque"
@@ -500,8 +495,8 @@ func zipStructures(z *zip.Writer) error {
 	return nil
 }
 
-func zipAudio(z *zip.Writer) error {
-	return filepath.Walk(vc.FilePath, func(p string, info os.FileInfo, err error) (e error) {
+func zipAudio(z *zip.Writer, ds *vc.Dataset) error {
+	return filepath.Walk(ds.FilePath, func(p string, info os.FileInfo, err error) (e error) {
 		if err != nil {
 			return err
 		}
@@ -513,7 +508,7 @@ func zipAudio(z *zip.Writer) error {
 			strings.HasSuffix(lp, ".wav") ||
 			strings.HasSuffix(lp, ".flac") ||
 			strings.HasSuffix(lp, ".mp3") {
-			relPath, err := filepath.Rel(vc.FilePath, p)
+			relPath, err := filepath.Rel(ds.FilePath, p)
 			if err != nil {
 				return err
 			}
@@ -533,8 +528,8 @@ func zipAudio(z *zip.Writer) error {
 	})
 }
 
-func zipAlliance(z *zip.Writer) error {
-	return filepath.Walk(vc.FilePath+"/guild/texture/", func(p string, info os.FileInfo, err error) (e error) {
+func zipAlliance(z *zip.Writer, ds *vc.Dataset) error {
+	return filepath.Walk(ds.FilePath+"/guild/texture/", func(p string, info os.FileInfo, err error) (e error) {
 		if err != nil {
 			return err
 		}
@@ -542,7 +537,7 @@ func zipAlliance(z *zip.Writer) error {
 			return nil
 		}
 
-		relPath, err := filepath.Rel(vc.FilePath+"/guild/texture/", p)
+		relPath, err := filepath.Rel(ds.FilePath+"/guild/texture/", p)
 		if err != nil {
 			return err
 		}
@@ -605,8 +600,8 @@ func zipAlliance(z *zip.Writer) error {
 	})
 }
 
-func zipNavi(z *zip.Writer) error {
-	return filepath.Walk(vc.FilePath+"/navi/", func(p string, info os.FileInfo, err error) (e error) {
+func zipNavi(z *zip.Writer, ds *vc.Dataset) error {
+	return filepath.Walk(ds.FilePath+"/navi/", func(p string, info os.FileInfo, err error) (e error) {
 		if err != nil {
 			return err
 		}
@@ -614,7 +609,7 @@ func zipNavi(z *zip.Writer) error {
 			return nil
 		}
 
-		relPath, err := filepath.Rel(vc.FilePath+"/navi/", p)
+		relPath, err := filepath.Rel(ds.FilePath+"/navi/", p)
 		if err != nil {
 			return err
 		}
@@ -645,8 +640,8 @@ func zipNavi(z *zip.Writer) error {
 	})
 }
 
-func zipGardenSprites(z *zip.Writer) error {
-	return filepath.Walk(vc.FilePath+"/garden/", func(p string, info os.FileInfo, err error) (e error) {
+func zipGardenSprites(z *zip.Writer, ds *vc.Dataset) error {
+	return filepath.Walk(ds.FilePath+"/garden/", func(p string, info os.FileInfo, err error) (e error) {
 		if err != nil {
 			return err
 		}
@@ -658,7 +653,7 @@ func zipGardenSprites(z *zip.Writer) error {
 			return nil
 		}
 
-		relPath, err := filepath.Rel(vc.FilePath+"/garden/", p)
+		relPath, err := filepath.Rel(ds.FilePath+"/garden/", p)
 		if err != nil {
 			return err
 		}
@@ -691,8 +686,8 @@ func zipGardenSprites(z *zip.Writer) error {
 	})
 }
 
-func zipBattleImages(z *zip.Writer) (err error) {
-	err = filepath.Walk(vc.FilePath+"/battle/", func(p string, info os.FileInfo, err error) (e error) {
+func zipBattleImages(z *zip.Writer, ds *vc.Dataset) (err error) {
+	err = filepath.Walk(ds.FilePath+"/battle/", func(p string, info os.FileInfo, err error) (e error) {
 		if err != nil {
 			return err
 		}
@@ -704,7 +699,7 @@ func zipBattleImages(z *zip.Writer) (err error) {
 			return nil
 		}
 
-		relPath, err := filepath.Rel(vc.FilePath+"/battle/", p)
+		relPath, err := filepath.Rel(ds.FilePath+"/battle/", p)
 		if err != nil {
 			return err
 		}
@@ -749,7 +744,7 @@ func zipBattleImages(z *zip.Writer) (err error) {
 		return
 	}
 
-	err = filepath.Walk(vc.FilePath+"/dungeon/", func(p string, info os.FileInfo, err error) (e error) {
+	err = filepath.Walk(ds.FilePath+"/dungeon/", func(p string, info os.FileInfo, err error) (e error) {
 		if err != nil {
 			return err
 		}
@@ -761,7 +756,7 @@ func zipBattleImages(z *zip.Writer) (err error) {
 			return nil
 		}
 
-		relPath, err := filepath.Rel(vc.FilePath+"/dungeon/", p)
+		relPath, err := filepath.Rel(ds.FilePath+"/dungeon/", p)
 		if err != nil {
 			return err
 		}
@@ -790,7 +785,7 @@ func zipBattleImages(z *zip.Writer) (err error) {
 		return
 	}
 
-	return filepath.Walk(vc.FilePath+"/weaponevent/", func(p string, info os.FileInfo, err error) (e error) {
+	return filepath.Walk(ds.FilePath+"/weaponevent/", func(p string, info os.FileInfo, err error) (e error) {
 		if err != nil {
 			return err
 		}
@@ -802,7 +797,7 @@ func zipBattleImages(z *zip.Writer) (err error) {
 			return nil
 		}
 
-		relPath, err := filepath.Rel(vc.FilePath+"/weaponevent/", p)
+		relPath, err := filepath.Rel(ds.FilePath+"/weaponevent/", p)
 		if err != nil {
 			return err
 		}
@@ -830,11 +825,11 @@ func zipBattleImages(z *zip.Writer) (err error) {
 
 }
 
-func zipEventStory(z *zip.Writer) (err error) {
+func zipEventStory(z *zip.Writer, ds *vc.Dataset) (err error) {
 	var txt string
 	var lines []string
 
-	lines, err = vc.ReadStringFile(filepath.Join(vc.FilePath, "bundle", "string", "MsgDemoString_en.strb"))
+	lines, err = ds.ReadStringFile(filepath.Join(ds.FilePath, "bundle", "string", "MsgDemoString_en.strb"))
 	if err != nil {
 		return
 	}
@@ -848,7 +843,7 @@ func zipEventStory(z *zip.Writer) (err error) {
 		return
 	}
 
-	for _, m := range vc.Data.Maps {
+	for _, m := range ds.Data.Maps {
 		if !m.HasStory() || m.CleanedEventName() == "" {
 			continue
 		}
@@ -932,7 +927,7 @@ table,th,td{border:1px solid black;}
 		}
 	}
 
-	for _, t := range vc.Data.Towers {
+	for _, t := range ds.Data.Towers {
 		txt, err = t.ScenarioHtml()
 		if err != nil {
 			return
@@ -946,7 +941,7 @@ table,th,td{border:1px solid black;}
 		}
 	}
 
-	for _, d := range vc.Data.Dungeons {
+	for _, d := range ds.Data.Dungeons {
 		if d.ScenarioID > 0 {
 			txt, err = d.ScenarioHtml()
 			if err != nil {
@@ -959,7 +954,7 @@ table,th,td{border:1px solid black;}
 		}
 	}
 
-	for _, w := range vc.Data.WeaponEvents {
+	for _, w := range ds.Data.WeaponEvents {
 		if w.ScenarioID > 0 {
 			txt, err = w.ScenarioHtml()
 			if err != nil {
@@ -1006,8 +1001,8 @@ func buildStoryHtml(title string, lines []string) (story string) {
 	return
 }
 
-func zipApkImages(z *zip.Writer) error {
-	return filepath.Walk(vc.FilePath+"/apk_images/", func(p string, info os.FileInfo, err error) (e error) {
+func zipApkImages(z *zip.Writer, ds *vc.Dataset) error {
+	return filepath.Walk(ds.FilePath+"/apk_images/", func(p string, info os.FileInfo, err error) (e error) {
 		if err != nil {
 			return err
 		}
@@ -1015,7 +1010,7 @@ func zipApkImages(z *zip.Writer) error {
 			return nil
 		}
 
-		relPath, err := filepath.Rel(vc.FilePath, p)
+		relPath, err := filepath.Rel(ds.FilePath, p)
 		if err != nil {
 			return err
 		}
@@ -1070,8 +1065,8 @@ type mapDlResult struct {
 }
 
 //DownloadAwMapsHandler
-func DownloadAwMapsHandler(w http.ResponseWriter, r *http.Request) {
-	lMaps := len(vc.Data.Maps)
+func DownloadAwMapsHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
+	lMaps := len(ds.Data.Maps)
 	numJobs := 4
 	maps := make(chan *vc.Map, lMaps)
 	results := make(chan mapDlResult, lMaps)
@@ -1081,8 +1076,8 @@ func DownloadAwMapsHandler(w http.ResponseWriter, r *http.Request) {
 		go findAndDownloadAwMap(i, maps, results)
 	}
 	queued := 0
-	for i := range vc.Data.Maps {
-		m := &(vc.Data.Maps[i])
+	for i := range ds.Data.Maps {
+		m := &(ds.Data.Maps[i])
 		if !m.PublicStartDatetime.IsZero() && !mapIsOnDisk(m) {
 			log.Printf("Searching for maps for event: %d: %s : %s", m.ID, m.CleanedEventName(), m.CleanedName())
 			maps <- m
@@ -1113,7 +1108,7 @@ func DownloadAwMapsHandler(w http.ResponseWriter, r *http.Request) {
 func mapIsOnDisk(m *vc.Map) bool {
 	eventName := m.CleanedEventName()
 	fileName := fmt.Sprintf("AreaMap_002_%03d.%s.%s", m.ID, eventName, m.CleanedName())
-	fileLoc := filepath.Join(vc.FilePath, "battle", "map", fileName)
+	fileLoc := filepath.Join(m.Dataset().FilePath, "battle", "map", fileName)
 	_, err := os.Stat(fileLoc)
 	return err == nil
 }
@@ -1129,7 +1124,7 @@ map_loop:
 	for m := range maps {
 		eventName := m.CleanedEventName()
 		fileName := fmt.Sprintf("AreaMap_002_%03d.%s.%s", m.ID, eventName, m.CleanedName())
-		fileLoc := filepath.Join(vc.FilePath, "battle", "map", fileName)
+		fileLoc := filepath.Join(m.Dataset().FilePath, "battle", "map", fileName)
 		if s, err := os.Stat(fileLoc); err == nil {
 			log.Printf("File already exists on disk: %s", fileName)
 			results <- mapDlResult{Success: true, Map: m, Timestamp: int(s.ModTime().Unix())}
@@ -13,7 +13,7 @@ import (
 )
 
 // MapHandler handle Map requests
-func MapHandler(w http.ResponseWriter, r *http.Request) {
+func MapHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	path := r.URL.Path
 	var pathLen int
 	if path[len(path)-1] == '/' {
@@ -25,16 +25,16 @@ func MapHandler(w http.ResponseWriter, r *http.Request) {
 	pathParts := strings.Split(path[1:pathLen], "/")
 	// "maps/id/WIKI"
 	if len(pathParts) < 2 {
-		MapTableHandler(w, r)
+		MapTableHandler(w, r, ds)
 		return
 	}
 
 	mapID, err := strconv.Atoi(pathParts[1])
-	if err != nil || mapID < 1 || mapID > len(vc.Data.Maps) {
+	if err != nil || mapID < 1 || mapID > len(ds.Data.Maps) {
 		http.Error(w, "Invalid map id "+pathParts[1], http.StatusNotFound)
 		return
 	}
-	m := vc.MapScan(mapID, vc.Data.Maps)
+	m := vc.MapScan(mapID, ds.Data.Maps)
 
 	if m == nil {
 		http.Error(w, "Invalid map id "+pathParts[1], http.StatusNotFound)
@@ -173,7 +173,7 @@ BattleBG %d.png
 }
 
 // MapTableHandler show maps as a table
-func MapTableHandler(w http.ResponseWriter, r *http.Request) {
+func MapTableHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	io.WriteString(w, "<html><head><title>Maps</title>\n")
 	io.WriteString(w, "<style>table, th, td {border: 1px solid black;};</style>")
 	io.WriteString(w, "</head><body>\n")
@@ -183,8 +183,8 @@ func MapTableHandler(w http.ResponseWriter, r *http.Request) {
 	io.WriteString(w, "</tr></thead>\n")
 	io.WriteString(w, "<tbody>\n")
 
-	for i := len(vc.Data.Maps) - 1; i >= 0; i-- {
-		m := vc.Data.Maps[i]
+	for i := len(ds.Data.Maps) - 1; i >= 0; i-- {
+		m := ds.Data.Maps[i]
 		fmt.Fprintf(w, "<tr><td><a href=\"/maps/%[1]d\">%[1]d</a></td><td><a href=\"/maps/%[1]d\">%[2]s</a></td><td>%[3]s</td><td>%s</td><td>%s</td><td>%d</td><td>%d</td><td>%d</td><td>%d</td><td>%d</td><td>%d</td>",
 			m.ID,
 			m.Name,
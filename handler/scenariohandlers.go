@@ -11,10 +11,10 @@ import (
 )
 
 // ScenarioHandler outputs the raw JSON
-func ScenarioHandler(folder, title string) func(w http.ResponseWriter, r *http.Request) {
-	return func(w http.ResponseWriter, r *http.Request) {
+func ScenarioHandler(folder, title string) DatasetHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 		// string file location vcRoot/scenario/MsgScenarioString_<lang>.strb
-		lines, err := vc.ReadStringFileFilter(filepath.Join(vc.FilePath, "scenario", folder, "MsgScenarioString_"+vc.LangPack+".strb"), false)
+		lines, err := vc.ReadStringFileFilter(filepath.Join(ds.FilePath, "scenario", folder, "MsgScenarioString_"+ds.LangPack+".strb"), false)
 		io.WriteString(w, "<html><head><title>"+title+" Scenario</title></head><body>\n")
 		if err != nil {
 			// write out our error...
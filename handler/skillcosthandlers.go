@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strconv"
+
+	"vc_file_grouper/vc"
+)
+
+// skillCostResult the medal costs and custom skill costs of a card
+type skillCostResult struct {
+	CardID          int                       `json:"cardId"`
+	Name            string                    `json:"name"`
+	FromLevel       int                       `json:"fromLevel"`
+	Skills          []vc.SkillMedalCost       `json:"skills"`
+	TotalMedals     int                       `json:"totalMedals"`
+	CustomSkillCost []vc.CustomSkillCostRange `json:"customSkillCost"`
+}
+
+// SkillCostHandler shows the medals needed to raise a card's skills to the max skill level and
+// the cost of custom skills on the card at each card level.
+// Use ?card=id&from=level and format=json for the result as JSON
+func SkillCostHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
+	var result *skillCostResult
+	var card *vc.Card
+	if v := r.FormValue("card"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "Invalid card id "+v, http.StatusNotFound)
+			return
+		}
+		card = ds.CardScan(id)
+		if card == nil {
+			http.Error(w, "Invalid card id "+v+"\nCard not found.", http.StatusNotFound)
+			return
+		}
+		from := 1
+		if f := r.FormValue("from"); f != "" {
+			if from, err = strconv.Atoi(f); err != nil || from < 1 || from > vc.MaxSkillLevel {
+				http.Error(w, "Invalid skill level "+f, http.StatusBadRequest)
+				return
+			}
+		}
+		result = &skillCostResult{
+			CardID:          card.ID,
+			Name:            card.Name,
+			FromLevel:       from,
+			Skills:          card.SkillMedalCosts(from),
+			CustomSkillCost: card.CustomSkillCostTable(),
+		}
+		for _, s := range result.Skills {
+			result.TotalMedals += s.Medals
+		}
+	}
+
+	if r.FormValue("format") == "json" {
+		if result == nil {
+			http.Error(w, "A card is required", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "\t")
+		enc.Encode(result)
+		return
+	}
+
+	io.WriteString(w, "<html><head><title>Skill Cost Calculator</title>\n")
+	io.WriteString(w, "<style>table, th, td {border: 1px solid black;};</style>")
+	io.WriteString(w, "</head><body>\n")
+	fmt.Fprintf(w, `<h1>Skill Cost Calculator</h1>
+<form method="get">
+<label for="f_card">Card ID</label>
+<input id="f_card" name="card" value="%s"/><br />
+<label for="f_from">Current Skill Level</label>
+<input id="f_from" name="from" value="%s"/><br />
+<button type="submit">Calculate</button>
+<button type="submit" name="format" value="json">Calculate as JSON</button>
+</form>
+<p><a href="/">back</a></p>
+`,
+		html.EscapeString(r.FormValue("card")),
+		html.EscapeString(r.FormValue("from")),
+	)
+	if result == nil {
+		io.WriteString(w, "</body></html>")
+		return
+	}
+
+	fmt.Fprintf(w, "<h2><a href=\"/cards/detail/%d\">%s</a> (%s)</h2>\n",
+		card.ID,
+		html.EscapeString(card.Name),
+		html.EscapeString(card.Rarity()),
+	)
+	fmt.Fprintf(w, "<p>Raising the skills from level %d to %d needs %d medals.</p>\n",
+		result.FromLevel,
+		vc.MaxSkillLevel,
+		result.TotalMedals,
+	)
+	io.WriteString(w, "<table><thead><tr><th>Skill</th><th>Level Type</th><th>Medals by Level</th><th>Total Medals</th></tr></thead><tbody>\n")
+	for _, s := range result.Skills {
+		levels := ""
+		for i, l := range s.Levels {
+			if i > 0 {
+				levels += ", "
+			}
+			levels += fmt.Sprintf("%d: %d", l.Level, l.Medal)
+		}
+		fmt.Fprintf(w, "<tr><td>%s (%d)</td><td>%d</td><td>%s</td><td>%d</td></tr>\n",
+			html.EscapeString(s.Name),
+			s.SkillID,
+			s.LevelType,
+			levels,
+			s.Medals,
+		)
+	}
+	io.WriteString(w, "</tbody></table>\n")
+
+	io.WriteString(w, "<h3>Custom Skill Cost</h3>\n")
+	if p := card.CustomSkillCostPattern(); p != nil {
+		fmt.Fprintf(w, "<p>Starts at %d and goes up by %d every %d card levels, up to %d.</p>\n",
+			card.CustomSkillCost,
+			p.Increment,
+			p.CardLevelInterval,
+			p.Max,
+		)
+	}
+	io.WriteString(w, "<table><thead><tr><th>Card Level</th><th>Cost</th></tr></thead><tbody>\n")
+	for _, c := range result.CustomSkillCost {
+		levels := strconv.Itoa(c.FromLevel)
+		if c.ToLevel != c.FromLevel {
+			levels += " - " + strconv.Itoa(c.ToLevel)
+		}
+		fmt.Fprintf(w, "<tr><td>%s</td><td>%d</td></tr>\n", levels, c.Cost)
+	}
+	io.WriteString(w, "</tbody></table>\n")
+	io.WriteString(w, "</body></html>")
+}
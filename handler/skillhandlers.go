@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/csv"
+	"io"
 	"log"
 	"net/http"
 	"strconv"
@@ -17,10 +18,17 @@ func SkillTableHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // SkillCsvHandler outputs skills as a csv file
-func SkillCsvHandler(w http.ResponseWriter, r *http.Request) {
+func SkillCsvHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	// File header
-	w.Header().Set("Content-Disposition", "attachment; filename=vcData-skills-"+strconv.Itoa(vc.Data.Version)+"_"+vc.Data.Common.UnixTime.Format(time.RFC3339)+".csv")
+	w.Header().Set("Content-Disposition", "attachment; filename=vcData-skills-"+strconv.Itoa(ds.Data.Version)+"_"+ds.Data.Common.UnixTime.Format(time.RFC3339)+".csv")
 	w.Header().Set("Content-Type", "text/csv")
+	if err := WriteSkillCsv(w, ds); err != nil {
+		log.Printf(err.Error() + "\n")
+	}
+}
+
+// WriteSkillCsv writes all the skills as a CSV doc
+func WriteSkillCsv(w io.Writer, ds *vc.Dataset) error {
 	cw := csv.NewWriter(w)
 	cw.UseCRLF = true
 	cw.Write([]string{"ID",
@@ -63,7 +71,7 @@ func SkillCsvHandler(w http.ResponseWriter, r *http.Request) {
 		"ThorHammerAnimationType",
 		"ReceiptItemID",
 	})
-	for _, s := range vc.Data.Skills {
+	for _, s := range ds.Data.Skills {
 		var startDate, endDate, skillChain string
 		chainIds := make([]string, 0)
 		if s.PublicStartDatetime.IsZero() {
@@ -122,4 +130,5 @@ func SkillCsvHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 	cw.Flush()
+	return cw.Error()
 }
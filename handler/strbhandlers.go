@@ -2,6 +2,7 @@ package handler
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"html"
 	"io"
@@ -16,7 +17,7 @@ import (
 )
 
 // StrbHandler handle STRB files
-func StrbHandler(w http.ResponseWriter, r *http.Request) {
+func StrbHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	fpath := r.URL.Path
 	var pathLen int
 	if fpath[len(fpath)-1] == '/' {
@@ -28,7 +29,7 @@ func StrbHandler(w http.ResponseWriter, r *http.Request) {
 	pathParts := strings.Split(fpath[1:pathLen], "/")
 	// "strb/id/TYPE"
 	if len(pathParts) < 3 {
-		StrbTableHandler(w, r)
+		StrbTableHandler(w, r, ds)
 		return
 	}
 
@@ -40,7 +41,7 @@ func StrbHandler(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, "Illegal file path: %s", strbFile)
 		return
 	}
-	fullPath := filepath.Join(vc.FilePath, strbFile)
+	fullPath := filepath.Join(ds.FilePath, strbFile)
 	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
 		fmt.Fprintf(w, "File does not exist: %s", strbFile)
 		return
@@ -76,6 +77,15 @@ func StrbHandler(w http.ResponseWriter, r *http.Request) {
 		for _, line := range contents {
 			fmt.Fprintf(w, "%s\n\n", line)
 		}
+	} else if ftype == "json" {
+		sf, err := vc.ReadStrb(fullPath)
+		if err != nil {
+			fmt.Fprintf(w, "Error reading file %s: %s", strbFile, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		b, _ := json.MarshalIndent(sf.Strings, "", "\t")
+		w.Write(b)
 	} else {
 		fmt.Fprintf(w, "Unsupported file type for conversion: %s", ftype)
 		return
@@ -89,17 +99,17 @@ func checkStrbName(info os.FileInfo) bool {
 }
 
 // StrbTableHandler shows strb events as a table
-func StrbTableHandler(w http.ResponseWriter, r *http.Request) {
+func StrbTableHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	io.WriteString(w, "<html><head><title>Strb Events</title>\n")
 	io.WriteString(w, "<style>table, th, td {border: 1px solid black;};</style>")
 	io.WriteString(w, "</head><body>\n")
 	io.WriteString(w, "<div>\n")
 	io.WriteString(w, "<table><thead><tr>\n")
-	io.WriteString(w, "<th>File</th><th>&nbsp;</th><th>&nbsp;</th>")
+	io.WriteString(w, "<th>File</th><th>&nbsp;</th><th>&nbsp;</th><th>&nbsp;</th>")
 	io.WriteString(w, "</tr></thead>\n")
 	io.WriteString(w, "<tbody>\n")
 
-	fullpath := vc.FilePath
+	fullpath := ds.FilePath
 
 	err := filepath.Walk(fullpath, func(fpath string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -127,7 +137,8 @@ func StrbTableHandler(w http.ResponseWriter, r *http.Request) {
 			fmt.Fprintf(w, "<tr>"+
 				"<td>%[1]s</td>"+
 				"<td><a href=\"/strb/%[2]s/html\">html</a></td>"+
-				"<td><a href=\"/strb/%[2]s/txt\">txt</a></td>",
+				"<td><a href=\"/strb/%[2]s/txt\">txt</a></td>"+
+				"<td><a href=\"/strb/%[2]s/json\">json</a></td>",
 				html.EscapeString(relPath),
 				url.QueryEscape(relPath),
 			)
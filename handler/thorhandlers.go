@@ -2,17 +2,19 @@ package handler
 
 import (
 	"fmt"
+	"html"
 	"io"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	"vc_file_grouper/util"
 	"vc_file_grouper/vc"
 )
 
 // ThorHandler handle Thor events
-func ThorHandler(w http.ResponseWriter, r *http.Request) {
+func ThorHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	path := r.URL.Path
 	var pathLen int
 	if path[len(path)-1] == '/' {
@@ -24,16 +26,16 @@ func ThorHandler(w http.ResponseWriter, r *http.Request) {
 	pathParts := strings.Split(path[1:pathLen], "/")
 	// "thor/id/WIKI"
 	if len(pathParts) < 2 {
-		ThorTableHandler(w, r)
+		ThorTableHandler(w, r, ds)
 		return
 	}
 
 	thorID, err := strconv.Atoi(pathParts[1])
-	if err != nil || thorID < 1 || thorID > len(vc.Data.ThorEvents) {
+	if err != nil || thorID < 1 || thorID > len(ds.Data.ThorEvents) {
 		http.Error(w, "Invalid Thor Event id "+pathParts[1], http.StatusNotFound)
 		return
 	}
-	t := vc.ThorEventScan(thorID)
+	t := ds.ThorEventScan(thorID)
 
 	if t == nil {
 		http.Error(w, "Invalid Thor Event id "+pathParts[1], http.StatusNotFound)
@@ -48,22 +50,291 @@ func ThorHandler(w http.ResponseWriter, r *http.Request) {
 	ThorDetailHandler(w, r, t)
 }
 
-// ThorDetailWikiHandler does nothing
+// ThorDetailWikiHandler shows the wiki template for a Thor event
 func ThorDetailWikiHandler(w http.ResponseWriter, r *http.Request, t *vc.ThorEvent) {
+	ds := t.Dataset()
+	name := thorEventName(t)
+	prevName, nextName := "", ""
+	if prev := ds.ThorEventScan(t.ID - 1); prev != nil {
+		prevName = thorEventName(prev)
+	}
+	if next := ds.ThorEventScan(t.ID + 1); next != nil {
+		nextName = thorEventName(next)
+	}
+
+	eventType := 0
+	description := ""
+	if event := t.Event(); event != nil {
+		eventType = event.EventTypeID
+		description = html.EscapeString(strings.ReplaceAll(event.Description, "\n", "\n\n"))
+	}
+
+	rankReward := ""
+	for _, reward := range t.RankRewards() {
+		if reward.CardID > 0 {
+			if card := ds.CardScan(reward.CardID); card != nil {
+				rankReward = card.Name
+				break
+			}
+		}
+	}
+
+	kings := ""
+	kingTable := `{| class="article-table"
+! Archwitch !! Skill 1 !! Skill 2 !! Cost Group
+`
+	costGroups := make([]int, 0)
+	for _, king := range t.Archwitches() {
+		card := ds.CardScan(king.CardMasterID1)
+		cardName := "Unknown Card ID"
+		if card != nil {
+			cardName = card.Name
+		}
+		kings += "|" + cardName + "|Archwitch\n"
+		kingTable += fmt.Sprintf("|-\n| {{Card Icon|%s}} || %s || %s || %d\n",
+			cardName,
+			wikiSkillName(ds.SkillScan(king.SkillID1)),
+			wikiSkillName(ds.SkillScan(king.SkillID2)),
+			king.CostGroupID,
+		)
+		if !util.ContainsInt(costGroups, king.CostGroupID) {
+			costGroups = append(costGroups, king.CostGroupID)
+		}
+	}
+	kingTable += "|}\n"
+
+	costTables := ""
+	for _, groupID := range costGroups {
+		costTables += fmt.Sprintf(`{| class="article-table" style="float:left"
+! colspan="3"|Cost Group %d
+|-
+! Cost !! ATK !! DEF
+`, groupID)
+		for _, cost := range ds.ThorKingCosts(groupID) {
+			costTables += fmt.Sprintf("|-\n|style=\"text-align:right\"|%d\n|style=\"text-align:right\"|%d%%\n|style=\"text-align:right\"|%d%%\n", cost.Cost, cost.OffenseRatio, cost.DefenseRatio)
+		}
+		costTables += "|}\n"
+	}
+
+	rewards := genWikiAWRewards(thorRewardSheets(t.RankRewards(), false), "Ranking", "Rank", ds) +
+		genWikiAWRewards(thorRewardSheets(t.PointRewards(), true), "Point Reward", "Points", ds)
+
+	fmt.Fprintf(w, "<html><head><title>%s</title></head><body><h1>%[1]s</h1>\n", html.EscapeString(name))
+	fmt.Fprintf(w, "<div><a href=\"/thor/%d\">Thor event details</a></div>\n", t.ID)
+	io.WriteString(w, "<textarea style=\"width:800px;height:760px\">")
+	fmt.Fprintf(w, `{{Event|eventType = %d
+|start jst = %s
+|end jst = %s
+|image = Banner {{PAGENAME}}.png
+|%s|Ranking Reward
+%s}}
+
+%s
+
+==Archwitches==
+%s
+==Thor Hammer Cost==
+%s{{clr}}
+
+==Rewards==
+%s
+{{clr}}
+
+{{NavEvent|%s|%s}}
+`,
+		eventType,
+		t.PublicStartDatetime.Format(wikiFmt),
+		t.PublicEndDatetime.Format(wikiFmt),
+		rankReward,
+		kings,
+		description,
+		kingTable,
+		costTables,
+		rewards,
+		prevName,
+		nextName,
+	)
+	io.WriteString(w, "</textarea></body></html>")
 }
 
-// ThorDetailHandler does nothing
+// ThorDetailHandler shows the kings, costs and rewards of a Thor event
 func ThorDetailHandler(w http.ResponseWriter, r *http.Request, t *vc.ThorEvent) {
+	ds := t.Dataset()
+	name := thorEventName(t)
+	fmt.Fprintf(w, "<html><head><title>%s</title>\n", html.EscapeString(name))
+	io.WriteString(w, "<style>table, th, td {border: 1px solid black;};</style>")
+	fmt.Fprintf(w, "</head><body><h1>%s</h1>\n", html.EscapeString(name))
+	if prev := ds.ThorEventScan(t.ID - 1); prev != nil {
+		fmt.Fprintf(w, "<div style=\"float:left\"><a href=\"/thor/%d\">%s</a>\n</div>", prev.ID, html.EscapeString(thorEventName(prev)))
+	}
+	if next := ds.ThorEventScan(t.ID + 1); next != nil {
+		fmt.Fprintf(w, "<div style=\"float:right\"><a href=\"/thor/%d\">%s</a>\n</div>", next.ID, html.EscapeString(thorEventName(next)))
+	}
+	io.WriteString(w, "<div style=\"clear:both\">")
+	fmt.Fprintf(w, "<a href=\"/thor/%d/WIKI\">Wiki template</a><br />\n", t.ID)
+	if event := t.Event(); event != nil {
+		fmt.Fprintf(w, "Event: <a href=\"/events/detail/%d\">%s</a><br />\n", event.ID, html.EscapeString(cleanEventName(event)))
+	}
+	fmt.Fprintf(w, "Start: %s<br />End: %s<br />Ranking: %s - %s<br />Reward Distribution: %s</div>\n",
+		t.PublicStartDatetime.Format(time.RFC3339),
+		t.PublicEndDatetime.Format(time.RFC3339),
+		t.RankingStartDatetime.Format(time.RFC3339),
+		t.RankingEndDatetime.Format(time.RFC3339),
+		t.RankingRewardDestributionStartDatetime.Format(time.RFC3339),
+	)
+
+	io.WriteString(w, "<h2>Archwitches</h2>\n")
+	kingRows := make([][]interface{}, 0)
+	costGroups := make([]int, 0)
+	for _, king := range t.Archwitches() {
+		kingRows = append(kingRows, []interface{}{
+			king.ID,
+			imageLink(ds.CardScan(king.CardMasterID1)),
+			imageLink(ds.CardScan(king.CardMasterID2)),
+			printSkill(ds.SkillScan(king.SkillID1)),
+			printSkill(ds.SkillScan(king.SkillID2)),
+			king.CostGroupID,
+			king.StatusGroupID,
+			king.PublicFlg,
+		})
+		if !util.ContainsInt(costGroups, king.CostGroupID) {
+			costGroups = append(costGroups, king.CostGroupID)
+		}
+	}
+	printHTMLTable(w, "", "",
+		[]string{"ID", "Card 1", "Card 2", "Skill 1", "Skill 2", "Cost Group", "Status Group", "Public"},
+		kingRows,
+	)
+
+	io.WriteString(w, "<h2>Thor Hammer Cost</h2>\n")
+	for _, groupID := range costGroups {
+		costRows := make([][]interface{}, 0)
+		for _, cost := range ds.ThorKingCosts(groupID) {
+			costRows = append(costRows, []interface{}{cost.Cost, cost.OffenseRatio, cost.DefenseRatio})
+		}
+		printHTMLTable(w, "float:left;margin-right:5px", fmt.Sprintf("Cost Group %d", groupID),
+			[]string{"Cost", "ATK Ratio", "DEF Ratio"},
+			costRows,
+		)
+	}
+
+	io.WriteString(w, "<h2 style=\"clear:both\">Rewards</h2>\n")
+	printHTMLTable(w, "float:left;margin-right:5px", "Ranking",
+		[]string{"Rank", "Reward"},
+		thorRewardRows(t.RankRewards(), false, ds),
+	)
+	printHTMLTable(w, "float:left", "Point Reward",
+		[]string{"Points", "Reward"},
+		thorRewardRows(t.PointRewards(), true, ds),
+	)
+	io.WriteString(w, "</body></html>")
+}
+
+// thorEventName the name of the event the Thor event is held during, or the Thor event title
+func thorEventName(t *vc.ThorEvent) string {
+	if event := t.Event(); event != nil {
+		return cleanEventName(event)
+	}
+	if t.Title != "" {
+		return t.Title
+	}
+	return fmt.Sprintf("Thor Event %d", t.ID)
+}
+
+// wikiSkillName the skill name for a wiki table
+func wikiSkillName(skill *vc.Skill) string {
+	if skill == nil {
+		return ""
+	}
+	return skill.Name
+}
+
+// thorRewardSheets converts Thor rewards so they can be shown like the other event rewards.
+// Point rewards keep the points needed in RankFrom.
+func thorRewardSheets(rewards []vc.ThorReward, points bool) []vc.RankRewardSheet {
+	ret := make([]vc.RankRewardSheet, 0, len(rewards))
+	for _, r := range rewards {
+		sheet := vc.RankRewardSheet{
+			ID:          r.ID,
+			GroupID:     r.GroupID,
+			RankFrom:    r.RankFrom,
+			RankTo:      r.RankTo,
+			Cash:        r.Cash,
+			FriendPoint: r.FriendPoint,
+			Coin:        r.Coin,
+			Iron:        r.Iron,
+			Ether:       r.Ether,
+			Exp:         r.Exp,
+			ItemID:      r.ItemID,
+			CardID:      r.CardID,
+			Num:         r.Num,
+		}
+		if points {
+			sheet.RankFrom, sheet.RankTo, sheet.Point = 0, 0, r.RankFrom
+		}
+		ret = append(ret, sheet)
+	}
+	return ret
+}
+
+// thorRewardRows table rows for the rewards with the card and item names
+func thorRewardRows(rewards []vc.ThorReward, points bool, ds *vc.Dataset) [][]interface{} {
+	ret := make([][]interface{}, 0, len(rewards))
+	for _, r := range rewards {
+		var rank string
+		if points || r.RankFrom == r.RankTo {
+			rank = strconv.Itoa(r.RankFrom)
+		} else {
+			rank = fmt.Sprintf("%d~%d", r.RankFrom, r.RankTo)
+		}
+		ret = append(ret, []interface{}{rank, thorRewardName(r, ds)})
+	}
+	return ret
+}
+
+// thorRewardName describes a reward, i.e. `Elixir x3`
+func thorRewardName(r vc.ThorReward, ds *vc.Dataset) string {
+	if r.CardID > 0 {
+		card := ds.CardScan(r.CardID)
+		if card == nil {
+			return fmt.Sprintf("Unknown card %d x%d", r.CardID, r.Num)
+		}
+		return fmt.Sprintf("<a href=\"/cards/detail/%d\">%s</a> x%d", card.ID, html.EscapeString(card.Name), r.Num)
+	}
+	if r.ItemID > 0 {
+		item := ds.ItemScan(r.ItemID)
+		if item == nil {
+			return fmt.Sprintf("Unknown item %d x%d", r.ItemID, r.Num)
+		}
+		return fmt.Sprintf("%s x%d", html.EscapeString(item.NameEng), r.Num)
+	}
+	switch {
+	case r.Cash > 0:
+		return fmt.Sprintf("Jewels x%d", r.Cash)
+	case r.FriendPoint > 0:
+		return fmt.Sprintf("Friendship Points x%d", r.FriendPoint)
+	case r.Coin > 0:
+		return fmt.Sprintf("Gold x%d", r.Coin)
+	case r.Iron > 0:
+		return fmt.Sprintf("Iron x%d", r.Iron)
+	case r.Ether > 0:
+		return fmt.Sprintf("Ether x%d", r.Ether)
+	case r.Exp > 0:
+		return fmt.Sprintf("EXP x%d", r.Exp)
+	}
+	return fmt.Sprintf("Unknown reward x%d", r.Num)
 }
 
 // ThorTableHandler shows thor events as a table
-func ThorTableHandler(w http.ResponseWriter, r *http.Request) {
+func ThorTableHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	io.WriteString(w, "<html><head><title>Thor Events</title>\n")
 	io.WriteString(w, "<style>table, th, td {border: 1px solid black;};</style>")
 	io.WriteString(w, "</head><body>\n")
 	io.WriteString(w, "<div>\n")
 	io.WriteString(w, "<table><thead><tr>\n")
 	io.WriteString(w, "<th>ID</th>"+
+		"<th>Name</th>"+
+		"<th>Archwitches</th>"+
 		"<th>Start</th>"+
 		"<th>End</th>"+
 		"<th>Rank Start</th>"+
@@ -72,14 +343,25 @@ func ThorTableHandler(w http.ResponseWriter, r *http.Request) {
 	io.WriteString(w, "</tr></thead>\n")
 	io.WriteString(w, "<tbody>\n")
 
-	for _, t := range vc.Data.ThorEvents {
+	for i := range ds.Data.ThorEvents {
+		t := &ds.Data.ThorEvents[i]
+		kings := make([]string, 0)
+		for _, king := range t.Archwitches() {
+			if card := ds.CardScan(king.CardMasterID1); card != nil {
+				kings = append(kings, html.EscapeString(card.Name))
+			}
+		}
 		fmt.Fprintf(w, "<tr><td><a href=\"/thor/%[1]d\">%[1]d</a></td>"+
-			"<td>%[2]s</td>"+
+			"<td><a href=\"/thor/%[1]d\">%[2]s</a></td>"+
+			"<td>%[3]s</td>"+
+			"<td>%[4]s</td>"+
 			"<td>%s</td>"+
 			"<td>%s</td>"+
 			"<td>%s</td>"+
 			"<td>%s</td>",
 			t.ID,
+			html.EscapeString(thorEventName(t)),
+			strings.Join(kings, "<br />"),
 			t.PublicStartDatetime.Format(time.RFC3339),
 			t.PublicEndDatetime.Format(time.RFC3339),
 			t.RankingStartDatetime.Format(time.RFC3339),
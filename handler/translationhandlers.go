@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"vc_file_grouper/vc"
+)
+
+// TranslationsHandler shows the text of each loaded language pack side by side.
+// Use ?type= to select the records, ?all=1 to include rows that are not flagged and ?format=json for JSON output
+func TranslationsHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
+	kind := r.FormValue("type")
+	if kind == "" {
+		kind = "cards"
+	}
+	all := r.FormValue("all") == "1"
+	rows := ds.CompareTranslations(kind, !all)
+	langs := ds.AllLanguages()
+
+	if r.FormValue("format") == "json" {
+		b, err := json.MarshalIndent(rows, "", "\t")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(b)
+		return
+	}
+
+	io.WriteString(w, "<html><head><title>Translations</title>\n")
+	io.WriteString(w, "<style>table, th, td {border: 1px solid black;} .missing {background-color: #fcc;} .untranslated {background-color: #ffc;}</style>")
+	io.WriteString(w, "</head><body>\n")
+	fmt.Fprintf(w, "<p><a href=\"/\">back</a></p><p>Languages: %s", html.EscapeString(strings.Join(langs, ", ")))
+	if len(langs) < 2 {
+		io.WriteString(w, " (start the program with <code>-lang en,zhs</code> to load more than one language)")
+	}
+	io.WriteString(w, "</p>\n<p><a href=\"/translations/export/\">Export the string files for translators</a></p>\n<p>")
+	for _, k := range vc.TranslationKinds() {
+		if k == kind {
+			fmt.Fprintf(w, "<b>%s</b> ", html.EscapeString(k))
+		} else {
+			fmt.Fprintf(w, "<a href=\"%s\">%s</a> ",
+				html.EscapeString("?type="+url.QueryEscape(k)+"&all="+url.QueryEscape(r.FormValue("all"))),
+				html.EscapeString(k),
+			)
+		}
+	}
+	io.WriteString(w, "</p>\n")
+	typeQuery := html.EscapeString("?type=" + url.QueryEscape(kind))
+	if all {
+		fmt.Fprintf(w, "<p><a href=\"%s\">Only show missing or untranslated text</a> <a href=\"%[1]s&amp;all=1&amp;format=json\">JSON</a></p>\n", typeQuery)
+	} else {
+		fmt.Fprintf(w, "<p><a href=\"%s&amp;all=1\">Show all text</a> <a href=\"%[1]s&amp;format=json\">JSON</a></p>\n", typeQuery)
+	}
+
+	io.WriteString(w, "<table><thead><tr><th>_id</th><th>Name</th><th>Field</th>")
+	for _, lang := range langs {
+		fmt.Fprintf(w, "<th>%s</th>", html.EscapeString(lang))
+	}
+	io.WriteString(w, "<th>Status</th></tr></thead><tbody>\n")
+	for _, row := range rows {
+		fmt.Fprintf(w, "<tr><td>%d</td><td>%s</td><td>%s</td>", row.ID, html.EscapeString(row.Label), row.Field)
+		for _, lang := range langs {
+			class := ""
+			if containsLang(row.Missing, lang) {
+				class = " class=\"missing\""
+			} else if containsLang(row.Untranslated, lang) {
+				class = " class=\"untranslated\""
+			}
+			fmt.Fprintf(w, "<td%s>%s</td>", class, html.EscapeString(row.Values[lang]))
+		}
+		status := make([]string, 0, 2)
+		if len(row.Missing) > 0 {
+			status = append(status, "missing: "+strings.Join(row.Missing, ", "))
+		}
+		if len(row.Untranslated) > 0 {
+			status = append(status, "untranslated: "+strings.Join(row.Untranslated, ", "))
+		}
+		fmt.Fprintf(w, "<td>%s</td></tr>\n", html.EscapeString(strings.Join(status, "; ")))
+	}
+	io.WriteString(w, "</tbody></table>\n")
+	io.WriteString(w, "</body></html>")
+}
+
+// TranslationsExportHandler downloads the string files as a PO or XLIFF file for translators.
+// Use ?target= for the language to translate to, ?file= to export a single file and ?format=po|xliff
+func TranslationsExportHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
+	names, err := ds.StringFileNames(ds.LangPack)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	target := r.FormValue("target")
+	if target == "" {
+		io.WriteString(w, "<html><head><title>Export Translations</title></head><body>\n")
+		io.WriteString(w, "<p><a href=\"/translations/\">back</a></p>\n")
+		fmt.Fprintf(w, "<form method=\"get\">Translate from <b>%s</b> to <input type=\"text\" name=\"target\" size=\"5\" placeholder=\"zhs\"/> ", html.EscapeString(ds.LangPack))
+		io.WriteString(w, "<select name=\"file\"><option value=\"\">All string files</option>")
+		for _, name := range names {
+			fmt.Fprintf(w, "<option>%s</option>", html.EscapeString(name))
+		}
+		io.WriteString(w, "</select> <select name=\"format\"><option>po</option><option>xliff</option></select> ")
+		io.WriteString(w, "<input type=\"submit\" value=\"Download\"/></form>\n")
+		io.WriteString(w, "<p>Translated files can be turned back into string files with the <code>translate import</code> command.</p>\n")
+		io.WriteString(w, "</body></html>")
+		return
+	}
+
+	if strings.ContainsAny(target, `/\.`) {
+		http.Error(w, "Invalid language: "+target, http.StatusBadRequest)
+		return
+	}
+	file := r.FormValue("file")
+	selected := make([]string, 0, 1)
+	if file != "" {
+		if !containsLang(names, file) {
+			http.Error(w, "Unknown string file: "+file, http.StatusNotFound)
+			return
+		}
+		selected = append(selected, file)
+	}
+	var write func(io.Writer, *vc.TranslationCatalog) error
+	ext := r.FormValue("format")
+	switch ext {
+	case "", "po":
+		write, ext = vc.WritePO, "po"
+	case "xliff":
+		write, ext = vc.WriteXLIFF, "xlf"
+	default:
+		http.Error(w, "Unsupported format: "+ext, http.StatusBadRequest)
+		return
+	}
+	cat, err := ds.TranslationCatalog(ds.LangPack, target, selected...)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if file == "" {
+		file = "Msg"
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+file+"_"+ds.LangPack+"-"+target+"."+ext+"\"")
+	if err := write(w, cat); err != nil {
+		log.Printf("Error exporting translations: %s", err.Error())
+	}
+}
+
+func containsLang(langs []string, lang string) bool {
+	for _, l := range langs {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
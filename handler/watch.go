@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"vc_file_grouper/vc"
+)
+
+// Watch polls the master data and string files of the current dataset and reloads the
+// dataset when they change. A reload only starts once the files have stopped changing
+// for a full interval so a game data refresh that is still being copied is not read half way.
+// Watch blocks until the stop channel is closed.
+func (c *CurrentDataset) Watch(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	root := c.Get().FilePath
+	loaded := dataFilesState(root)
+	pending := loaded
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		if ds := c.Get(); ds.FilePath != root {
+			// the data location was changed by hand, start watching the new one
+			root = ds.FilePath
+			loaded = dataFilesState(root)
+			pending = loaded
+			continue
+		}
+
+		state := dataFilesState(root)
+		if state == loaded {
+			pending = state
+			continue
+		}
+		if state != pending {
+			// still changing, wait for the next tick
+			pending = state
+			continue
+		}
+
+		log.Printf("Data files in %s changed, reloading", root)
+		if _, err := c.Reload(); err != nil {
+			os.Stderr.WriteString("Unable to reload the data: " + err.Error() + "\n")
+		} else {
+			os.Stdout.WriteString("Reloaded the data from " + root + "\n")
+		}
+		// don't retry a failed reload until the files change again
+		loaded = state
+	}
+}
+
+// dataFilesState a summary of the size and modification time of the files a dataset is read from,
+// including the name overrides file in the data location
+func dataFilesState(root string) string {
+	files, _ := filepath.Glob(filepath.Join(root, "string", "*.strb"))
+	sort.Strings(files)
+	files = append([]string{filepath.Join(root, "response", "master_all"), filepath.Join(root, vc.NameOverridesFile)}, files...)
+
+	state := ""
+	for _, f := range files {
+		info, err := os.Stat(f)
+		if err != nil {
+			continue
+		}
+		state += fmt.Sprintf("%s:%d:%d;", info.Name(), info.ModTime().UnixNano(), info.Size())
+	}
+	return state
+}
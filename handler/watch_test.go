@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"vc_file_grouper/vc"
+)
+
+// testStringFiles the string files Dataset.Read needs when the data has no weapons or Thor events
+var testStringFiles = []string{
+	"MsgBuildingDesc", "MsgBuildingName", "MsgCardName", "MsgCardSymbol", "MsgCharaBonds", "MsgCharaBtlEnd",
+	"MsgCharaBtlStart", "MsgCharaDesc", "MsgCharaFriendshipMax", "MsgCharaFriendship", "MsgCharaMeet",
+	"MsgCharaSuperAwaken", "MsgCharaWelcome", "MsgDeckBonusDesc", "MsgDeckBonusName", "MsgEventDesc",
+	"MsgEventName", "MsgKingFriendshipDesc", "MsgKingTitle", "MsgNPCAreaEnd", "MsgNPCAreaLongName",
+	"MsgNPCAreaName", "MsgNPCAreaStart", "MsgNPCAreaStory", "MsgNPCBossEnd", "MsgNPCBossStart",
+	"MsgNPCMapName", "MsgNPCMapStart", "MsgShopItemDescInShop", "MsgShopItemDescSub", "MsgShopItemDesc",
+	"MsgShopItemName", "MsgShopItemUseResult", "MsgSkillDesc", "MsgSkillFire", "MsgSkillName",
+}
+
+// writeTestMaster writes an encoded master data file with the version, dated so each write is seen as a change
+func writeTestMaster(t *testing.T, root string, version int) {
+	t.Helper()
+	file := filepath.Join(root, "response", "master_all")
+	data := vc.Encode([]byte(fmt.Sprintf(`{"version":%d}`, version)), vc.CodeHeader{Key: 3})
+	if err := ioutil.WriteFile(file, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Now().Add(time.Duration(version) * time.Second)
+	if err := os.Chtimes(file, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testDataLocation creates a data location with empty string files that a dataset can be loaded from
+func testDataLocation(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	for _, dir := range []string{"response", "string"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range testStringFiles {
+		sf := &vc.StringFile{Strings: []string{}, Index: []int{}}
+		if err := sf.Save(filepath.Join(root, "string", name+"_en.strb")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTestMaster(t, root, 1)
+	return root
+}
+
+func TestWatchReload(t *testing.T) {
+	root := testDataLocation(t)
+	ds, err := vc.LoadDataset(root, "en")
+	if err != nil {
+		t.Fatalf("LoadDataset returned an error: %s", err.Error())
+	}
+	current := NewCurrentDataset(ds)
+
+	interval := 100 * time.Millisecond
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		current.Watch(interval, stop)
+		close(done)
+	}()
+	defer func() {
+		close(stop)
+		<-done
+	}()
+
+	// keep the files changing for a few intervals, the data must not be reloaded yet
+	version := 1
+	for end := time.Now().Add(4 * interval); time.Now().Before(end); {
+		version++
+		writeTestMaster(t, root, version)
+		time.Sleep(interval / 10)
+		if current.Get() != ds {
+			t.Fatalf("The data was reloaded while the files were still changing")
+		}
+	}
+
+	// once the files stop changing the new data is swapped in
+	deadline := time.Now().Add(20 * interval)
+	for current.Get() == ds {
+		if time.Now().After(deadline) {
+			t.Fatalf("The data was not reloaded after the files stopped changing")
+		}
+		time.Sleep(interval / 10)
+	}
+	reloaded := current.Get()
+	if reloaded.Data.Version != version {
+		t.Errorf("Expected version %d after the reload, got %d", version, reloaded.Data.Version)
+	}
+	if ds.Data.Version != 1 {
+		t.Errorf("The old dataset was changed by the reload, it has version %d", ds.Data.Version)
+	}
+
+	// nothing changed, so it is not loaded again
+	time.Sleep(3 * interval)
+	if current.Get() != reloaded {
+		t.Errorf("The data was reloaded without a change to the files")
+	}
+}
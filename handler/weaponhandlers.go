@@ -15,7 +15,7 @@ import (
 )
 
 // WeaponHandler handle weapon information
-func WeaponHandler(w http.ResponseWriter, r *http.Request) {
+func WeaponHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 
 	io.WriteString(w, "<html><head><title>All Weapons</title>\n")
 	io.WriteString(w, "<style>table, th, td {border: 1px solid black;};</style>")
@@ -25,8 +25,8 @@ func WeaponHandler(w http.ResponseWriter, r *http.Request) {
 	io.WriteString(w, "<th>ID</th><th>Weapon Names</th><th>Descriptions</th><th>Max Rarity</th><th>Max Rank</th><th>Rank Group</th><th>Rarity Group</th><th>Status ID</th>\n")
 	io.WriteString(w, "</tr></thead>\n")
 	io.WriteString(w, "<tbody>\n")
-	for i := len(vc.Data.Weapons) - 1; i >= 0; i-- {
-		wp := vc.Data.Weapons[i]
+	for i := len(ds.Data.Weapons) - 1; i >= 0; i-- {
+		wp := ds.Data.Weapons[i]
 		fmt.Fprintf(w, `<tr>
 	<td><a href="/weapons/detail/%[1]d">%[1]d</a></td>
 	<td><a href="/weapons/detail/%[1]d">%[2]s</a></td>
@@ -51,7 +51,7 @@ func WeaponHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // WeaponDetailHandler show details for a single weapon
-func WeaponDetailHandler(w http.ResponseWriter, r *http.Request) {
+func WeaponDetailHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	path := r.URL.Path
 	var pathLen int
 	if path[len(path)-1] == '/' {
@@ -72,7 +72,7 @@ func WeaponDetailHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	weapon := vc.WeaponScan(weaponID)
+	weapon := ds.WeaponScan(weaponID)
 
 	if weapon == nil {
 		http.Error(w, "Invalid weapon id "+pathParts[2], http.StatusNotFound)
@@ -82,13 +82,13 @@ func WeaponDetailHandler(w http.ResponseWriter, r *http.Request) {
 	var prevWeapon, nextWeapon *vc.Weapon = nil, nil
 
 	prevWeaponName := ""
-	prevWeapon = vc.WeaponScan(weaponID - 1)
+	prevWeapon = ds.WeaponScan(weaponID - 1)
 	if prevWeapon != nil {
 		prevWeaponName = prevWeapon.MaxRarityName()
 	}
 
 	nextWeaponName := ""
-	nextWeapon = vc.WeaponScan(weaponID + 1)
+	nextWeapon = ds.WeaponScan(weaponID + 1)
 	if nextWeapon != nil {
 		nextWeaponName = nextWeapon.MaxRarityName()
 	}
@@ -287,9 +287,9 @@ func printWeaponImages(w io.Writer, weapon *vc.Weapon) {
 	for i := 1; i <= rlen; i++ {
 		pathPart := ""
 		imgName := fmt.Sprintf("wp_%05[1]d_%02[2]d", weapon.ID, i)
-		if _, err := os.Stat(filepath.Join(vc.FilePath, "weapon", "hd") + imgName); err == nil {
+		if _, err := os.Stat(filepath.Join(weapon.Dataset().FilePath, "weapon", "hd") + imgName); err == nil {
 			pathPart = "hd"
-		} else if _, err := os.Stat(filepath.Join(vc.FilePath, "weapon", "md") + imgName); err == nil {
+		} else if _, err := os.Stat(filepath.Join(weapon.Dataset().FilePath, "weapon", "md") + imgName); err == nil {
 			pathPart = "md"
 		} else {
 			pathPart = "sd"
@@ -0,0 +1,422 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"vc_file_grouper/vc"
+	"vc_file_grouper/wiki/api"
+)
+
+// botChangelogRoot folder the wiki bot writes its changes to
+const botChangelogRoot = "changelog"
+
+// botProgressFile file in the changelog folder of a batch job that records its progress
+const botProgressFile = "progress.json"
+
+// BotBatchFailure a card the batch job could not update
+type BotBatchFailure struct {
+	CardID int    `json:"cardId"`
+	Name   string `json:"name"`
+	Error  string `json:"error"`
+}
+
+// BotBatchProgress progress of a wiki bot batch job. It is saved after each card
+// so an interrupted job can pick up where it left off.
+type BotBatchProgress struct {
+	// Dir changelog folder of the job
+	Dir      string    `json:"dir"`
+	Summary  string    `json:"summary"`
+	DryRun   bool      `json:"dryRun"`
+	Started  time.Time `json:"started"`
+	Updated  time.Time `json:"updated"`
+	Finished bool      `json:"finished"`
+	Stopped  bool      `json:"stopped"`
+	Running  bool      `json:"running"`
+	Total    int       `json:"total"`
+	Changed  int       `json:"changed"`
+	// Done card IDs that were checked against the wiki without errors
+	Done []int `json:"done"`
+	// Missing card IDs that have no wiki page. They are not failures and are not tried again.
+	Missing []int `json:"missing"`
+	// Failures cards that could not be checked or saved. These are tried again when the job is resumed,
+	// and a job with failures does not count as finished.
+	Failures []BotBatchFailure `json:"failures"`
+}
+
+// Processed number of cards looked at so far
+func (p *BotBatchProgress) Processed() int {
+	return len(p.Done) + len(p.Missing) + len(p.Failures)
+}
+
+// save writes the progress to the changelog folder of the job
+func (p *BotBatchProgress) save() error {
+	b, err := json.MarshalIndent(p, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(p.Dir, botProgressFile), b, 0600)
+}
+
+// loadBotBatchProgress reads the progress of a job from its changelog folder
+func loadBotBatchProgress(dir string) (*BotBatchProgress, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, botProgressFile))
+	if err != nil {
+		return nil, err
+	}
+	p := &BotBatchProgress{}
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, err
+	}
+	p.Dir = dir
+	return p, nil
+}
+
+// unfinishedBotBatches lists the changelog folders of jobs that did not finish, newest first
+func unfinishedBotBatches() []*BotBatchProgress {
+	files, _ := filepath.Glob(filepath.Join(botChangelogRoot, "*", botProgressFile))
+	ret := make([]*BotBatchProgress, 0)
+	for _, f := range files {
+		p, err := loadBotBatchProgress(filepath.Dir(f))
+		if err != nil {
+			log.Printf("Unable to read wiki bot progress %s: %s", f, err.Error())
+			continue
+		}
+		if !p.Finished {
+			ret = append(ret, p)
+		}
+	}
+	sort.Slice(ret, func(a, b int) bool { return ret[a].Started.After(ret[b].Started) })
+	return ret
+}
+
+// botBatch the batch job that is running or ran last
+var botBatch struct {
+	lock     sync.Mutex
+	progress *BotBatchProgress
+	stop     chan struct{}
+}
+
+// botBatchStatus a copy of the progress of the current batch job, or nil if none was started
+func botBatchStatus() *BotBatchProgress {
+	botBatch.lock.Lock()
+	defer botBatch.lock.Unlock()
+	if botBatch.progress == nil {
+		return nil
+	}
+	p := *botBatch.progress
+	p.Done = append([]int(nil), p.Done...)
+	p.Missing = append([]int(nil), p.Missing...)
+	p.Failures = append([]BotBatchFailure(nil), p.Failures...)
+	return &p
+}
+
+// startBotBatch starts a batch job in the background. If resumeDir is set
+// the job recorded in that changelog folder is continued.
+func startBotBatch(ds *vc.Dataset, resumeDir, summary string, dryRun bool, delay time.Duration) error {
+	botBatch.lock.Lock()
+	defer botBatch.lock.Unlock()
+	if botBatch.progress != nil && botBatch.progress.Running {
+		return fmt.Errorf("a batch job is already running in %s", botBatch.progress.Dir)
+	}
+
+	var p *BotBatchProgress
+	if resumeDir != "" {
+		var err error
+		if p, err = loadBotBatchProgress(filepath.Clean(resumeDir)); err != nil {
+			return err
+		}
+		if p.Finished {
+			return fmt.Errorf("the batch job in %s already finished", p.Dir)
+		}
+		// failed cards are tried again
+		p.Failures = nil
+		p.Stopped = false
+	} else {
+		//Mon Jan 2 15:04:05 -0700 MST 2006
+		dir := filepath.Join(botChangelogRoot, time.Now().Format("20060102-150405"))
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return err
+		}
+		p = &BotBatchProgress{
+			Dir:     dir,
+			Summary: summary,
+			DryRun:  dryRun,
+			Started: time.Now(),
+			Done:    make([]int, 0),
+			Missing: make([]int, 0),
+		}
+	}
+	cards := botCards(ds)
+	p.Total = len(cards)
+	p.Running = true
+	p.Updated = time.Now()
+	if err := p.save(); err != nil {
+		return err
+	}
+	botBatch.progress = p
+	botBatch.stop = make(chan struct{})
+	go runBotBatch(cards, botBatch.stop, delay)
+	return nil
+}
+
+// stopBotBatch asks the running batch job to stop after the current card
+func stopBotBatch() {
+	botBatch.lock.Lock()
+	defer botBatch.lock.Unlock()
+	if botBatch.progress != nil && botBatch.progress.Running && !botBatch.progress.Stopped {
+		botBatch.progress.Stopped = true
+		close(botBatch.stop)
+	}
+}
+
+// runBotBatch checks each card against the wiki, saving the pages that changed
+func runBotBatch(cards vc.CardList, stop <-chan struct{}, delay time.Duration) {
+	botBatch.lock.Lock()
+	p := botBatch.progress
+	done := make(map[int]bool, len(p.Done)+len(p.Missing))
+	for _, id := range p.Done {
+		done[id] = true
+	}
+	for _, id := range p.Missing {
+		done[id] = true
+	}
+	botBatch.lock.Unlock()
+
+	stopped := false
+	for pos, card := range cards {
+		if done[card.ID] {
+			continue
+		}
+		select {
+		case <-stop:
+			stopped = true
+		default:
+		}
+		if stopped {
+			break
+		}
+
+		changed, err := botBatchCard(p, pos, card)
+
+		botBatch.lock.Lock()
+		if errors.Is(err, api.ErrPageNotFound) {
+			log.Printf("Wiki bot found no page for card %d:%s", card.ID, card.Name)
+			p.Missing = append(p.Missing, card.ID)
+		} else if err != nil {
+			log.Printf("Wiki bot failed to update card %d:%s - %s", card.ID, card.Name, err.Error())
+			p.Failures = append(p.Failures, BotBatchFailure{CardID: card.ID, Name: card.Name, Error: err.Error()})
+		} else {
+			p.Done = append(p.Done, card.ID)
+			if changed {
+				p.Changed++
+			}
+		}
+		p.Updated = time.Now()
+		if err := p.save(); err != nil {
+			os.Stderr.WriteString("Unable to save the wiki bot progress: " + err.Error() + "\n")
+		}
+		botBatch.lock.Unlock()
+
+		// give the wiki a break between pages
+		select {
+		case <-stop:
+			stopped = true
+		case <-time.After(delay):
+		}
+	}
+
+	botBatch.lock.Lock()
+	p.Running = false
+	p.Finished = !stopped && len(p.Failures) == 0
+	p.Updated = time.Now()
+	if err := p.save(); err != nil {
+		os.Stderr.WriteString("Unable to save the wiki bot progress: " + err.Error() + "\n")
+	}
+	botBatch.lock.Unlock()
+}
+
+// botBatchCard updates the wiki page for a single card. Returns true if the page had changes.
+func botBatchCard(p *BotBatchProgress, pos int, card *vc.Card) (bool, error) {
+	cardPage, diff, err := wikiCardUpdate(card)
+	if err != nil {
+		return false, err
+	}
+	if len(diff) == 0 {
+		log.Printf("Card %s had no updates, so nothing will be saved to the wiki", card.Name)
+		return false, nil
+	}
+	log.Printf("*****Card %s has updates, will be saved to the wiki", card.Name)
+	b, _ := json.MarshalIndent(diff, "", "\t")
+	fName := filepath.Join(p.Dir, fmt.Sprintf("%06d.%s.diff.json", pos, card.Name))
+	if err := ioutil.WriteFile(fName, b, 0600); err != nil {
+		return false, err
+	}
+	if !p.DryRun {
+		if err := api.EditCardPage(cardPage, p.Summary); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// BotBatchHandler shows the form to start, resume or stop a wiki bot batch job
+func BotBatchHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
+	if r.Method == "POST" {
+		var err error
+		if r.FormValue("stop") != "" {
+			stopBotBatch()
+		} else {
+			delay := 3 * time.Second
+			if d := r.FormValue("delay"); d != "" {
+				secs, perr := strconv.Atoi(d)
+				if perr != nil || secs < 0 {
+					http.Error(w, "Invalid delay: "+d, http.StatusBadRequest)
+					return
+				}
+				delay = time.Duration(secs) * time.Second
+			}
+			err = startBotBatch(ds, r.FormValue("resume"), r.FormValue("summary"), r.FormValue("dryrun") == "checked", delay)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Redirect(w, r, "/wikibot/batch/status", http.StatusSeeOther)
+		return
+	}
+
+	io.WriteString(w, "<html><head><title>Wikibot batch update</title></head><body>\n")
+	io.WriteString(w, `<p><a href="/wikibot">Wikibot home</a> <a href="/wikibot/batch/status">Status</a></p>`)
+	if p := botBatchStatus(); p != nil && p.Running {
+		fmt.Fprintf(w, `<form method="post"><p>A batch job is running in %s.</p><button name="stop" value="1" type="submit">Stop after the current card</button></form>`,
+			html.EscapeString(p.Dir))
+		io.WriteString(w, "</body></html>")
+		return
+	}
+	io.WriteString(w, `<form method="post">
+<h2>Start a new batch update</h2>
+<div><label for="f_summary">Bot Edit Summary: <input id="f_summary" name="summary" type="text"/></label></div>
+<div><label for="f_dryrun">Dry Run: <input id="f_dryrun" name="dryrun" type="checkbox" value="checked" checked="checked"/></label><small>(no actual edits, only the change log is written)</small></div>
+<div><label for="f_delay">Seconds between cards: <input id="f_delay" name="delay" type="number" value="3" min="0"/></label></div>
+<button type="submit">Start</button>
+</form>
+`)
+	unfinished := unfinishedBotBatches()
+	if len(unfinished) > 0 {
+		io.WriteString(w, "<h2>Resume an unfinished batch update</h2>\n<table><thead><tr><th>Change log</th><th>Started</th><th>Processed</th><th>Changed</th><th>Failed</th><th>Dry Run</th><th></th></tr></thead><tbody>\n")
+		for _, p := range unfinished {
+			fmt.Fprintf(w, `<tr><td>%[1]s</td><td>%s</td><td>%d of %d</td><td>%d</td><td>%d</td><td>%t</td><td><form method="post"><input type="hidden" name="resume" value="%[1]s"/><button type="submit">Resume</button></form></td></tr>`+"\n",
+				html.EscapeString(p.Dir),
+				p.Started.Format(time.RFC3339),
+				p.Processed(),
+				p.Total,
+				p.Changed,
+				len(p.Failures),
+				p.DryRun,
+			)
+		}
+		io.WriteString(w, "</tbody></table>\n")
+	}
+	io.WriteString(w, "</body></html>")
+}
+
+// BotBatchStatusHandler shows the progress of the wiki bot batch job. Use ?format=json for JSON output
+func BotBatchStatusHandler(w http.ResponseWriter, r *http.Request) {
+	p := botBatchStatus()
+	if r.FormValue("format") == "json" {
+		status := map[string]interface{}{"running": false}
+		if p != nil {
+			status = map[string]interface{}{
+				"dir":       p.Dir,
+				"running":   p.Running,
+				"finished":  p.Finished,
+				"stopped":   p.Stopped,
+				"dryRun":    p.DryRun,
+				"started":   p.Started,
+				"updated":   p.Updated,
+				"total":     p.Total,
+				"processed": p.Processed(),
+				"changed":   p.Changed,
+				"missing":   p.Missing,
+				"failed":    len(p.Failures),
+				"failures":  p.Failures,
+			}
+		}
+		b, err := json.MarshalIndent(status, "", "\t")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(b)
+		return
+	}
+
+	io.WriteString(w, "<html><head><title>Wikibot batch status</title>\n")
+	if p != nil && p.Running {
+		io.WriteString(w, `<meta http-equiv="refresh" content="5">`)
+	}
+	io.WriteString(w, "<style>table, th, td {border: 1px solid black;};</style>")
+	io.WriteString(w, "</head><body>\n")
+	io.WriteString(w, `<p><a href="/wikibot">Wikibot home</a> <a href="/wikibot/batch">Batch update</a> <a href="?format=json">JSON</a></p>`)
+	if p == nil {
+		io.WriteString(w, "<p>No batch job has been started.</p></body></html>")
+		return
+	}
+	state := "Running"
+	if p.Finished {
+		state = "Finished"
+	} else if p.Running && p.Stopped {
+		state = "Stopping"
+	} else if !p.Running {
+		state = "Stopped, can be resumed"
+	}
+	fmt.Fprintf(w, `<table>
+<tr><th>Status</th><td>%s</td></tr>
+<tr><th>Change log</th><td>%s</td></tr>
+<tr><th>Dry Run</th><td>%t</td></tr>
+<tr><th>Started</th><td>%s</td></tr>
+<tr><th>Last update</th><td>%s</td></tr>
+<tr><th>Processed</th><td>%d of %d</td></tr>
+<tr><th>Changed</th><td>%d</td></tr>
+<tr><th>No wiki page</th><td>%d</td></tr>
+<tr><th>Failed</th><td>%d</td></tr>
+</table>
+`,
+		state,
+		html.EscapeString(p.Dir),
+		p.DryRun,
+		p.Started.Format(time.RFC3339),
+		p.Updated.Format(time.RFC3339),
+		p.Processed(),
+		p.Total,
+		p.Changed,
+		len(p.Missing),
+		len(p.Failures),
+	)
+	if len(p.Failures) > 0 {
+		io.WriteString(w, "<h2>Failures</h2>\n<table><thead><tr><th>Card</th><th>Name</th><th>Error</th></tr></thead><tbody>\n")
+		for _, f := range p.Failures {
+			fmt.Fprintf(w, "<tr><td><a href=\"/cards/detail/%[1]d\">%[1]d</a></td><td>%s</td><td>%s</td></tr>\n",
+				f.CardID,
+				html.EscapeString(f.Name),
+				html.EscapeString(f.Error),
+			)
+		}
+		io.WriteString(w, "</tbody></table>\n")
+	}
+	io.WriteString(w, "</body></html>")
+}
@@ -26,6 +26,8 @@ func WikibotHandler(w http.ResponseWriter, r *http.Request) {
 	<li><a href="/wikibot/testLogin">Test Your Login</a></li>
 	<li><a href="/wikibot/testCardFetch">Test Fetch and compare.</a></li>
 	<li><a href="/wikibot/startMassUpdate">Start a mass update.</a></li>
+	<li><a href="/wikibot/batch">Run a mass update in the background.</a></li>
+	<li><a href="/wikibot/newCards">Create pages for new cards.</a></li>
 </ul>
 `)
 	io.WriteString(w, "</body></html>")
@@ -51,7 +53,7 @@ func TestLoginHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 //TestCardFetchHandler tests fetching a single card page
-func TestCardFetchHandler(w http.ResponseWriter, r *http.Request) {
+func TestCardFetchHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	/*
 		 313 Oracle R - HR
 		1879 Oracle Ascendant UR - GUR
@@ -62,7 +64,7 @@ func TestCardFetchHandler(w http.ResponseWriter, r *http.Request) {
 		9490 Cheerleader Pixie VR - GVR
 		9517 Christmas Lum Lum - XSR - ABB (skill expire)
 	*/
-	card := vc.CardScan(3934)
+	card := ds.CardScan(3934)
 	writeCardReviewForm(w, card, 1, 1, "", "checked", "", nil)
 }
 
@@ -70,17 +72,10 @@ var botCardList vc.CardList = nil
 var botLogRoot = ""
 
 //StartMassUpdateCardsHandler starts the mass update wizard.
-func StartMassUpdateCardsHandler(w http.ResponseWriter, r *http.Request) {
+func StartMassUpdateCardsHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
 	if botCardList == nil {
 		// initialilze the list
-		tmp := vc.CardsByNameByLowestID(true)
-		botCardList = make(vc.CardList, 0)
-		for _, cl := range tmp {
-			botCardList = append(botCardList, cl.Earliest())
-		}
-		botCardList = botCardList.Filter(func(c vc.Card) bool {
-			return c.CardCharaID > 0 && c.IsClosed == 0 && c.Name != "" && c.SkillID1 > 0
-		})
+		botCardList = botCards(ds)
 	}
 
 	var card *vc.Card
@@ -130,6 +125,9 @@ func StartMassUpdateCardsHandler(w http.ResponseWriter, r *http.Request) {
 		}
 		newCardPage := &wiki.CardPage{
 			PageName: api.CardNameToWiki(card.Name),
+			// the revision shown in the form, so edits made on the wiki since then are not overwritten
+			BaseTimestamp:  r.FormValue("basetimestamp"),
+			StartTimestamp: r.FormValue("starttimestamp"),
 		}
 		err = newCardPage.Parse(fixedPage)
 		if err != nil {
@@ -240,6 +238,8 @@ func writeCardReviewForm(w io.Writer, card *vc.Card, currentID, listLength int,
 		fmt.Fprintf(w, "<h1>%s</h1>\n", card.Name)
 		io.WriteString(w, `<form id="cardChanges" action="./" name="cardChanges" method="post" onsubmit="return vc.submit();">`)
 		fmt.Fprintf(w, `<input type="hidden" name="pos" value="%d" />`, currentID)
+		fmt.Fprintf(w, `<input type="hidden" name="basetimestamp" value="%s" />`, html.EscapeString(cardPage.BaseTimestamp))
+		fmt.Fprintf(w, `<input type="hidden" name="starttimestamp" value="%s" />`, html.EscapeString(cardPage.StartTimestamp))
 		fmt.Fprintf(w, `<div><label for="f_summary">Bot Edit Summary:<input id="f_summary" name="summary" type="text" value="%s"/></label></div>`, html.EscapeString(summary))
 		io.WriteString(w, `<div class="nav"><span><a href="/wikibot">Cancel</a></span>`)
 		if currentID < listLength {
@@ -253,12 +253,7 @@ func writeCardReviewForm(w io.Writer, card *vc.Card, currentID, listLength int,
 		io.WriteString(w, `</div>`)
 		io.WriteString(w, `<div class="flex">`)
 		fmt.Fprintf(w, `<div>Wiki Version<textarea readonly="readonly" name="orig">%s</textarea></div>`, html.EscapeString(rawPagebody))
-		cardPage.CardInfo.UpdateBaseData(card)
-		cardPage.CardInfo.UpdateSkills(card.GetEvolutions())
-		cardPage.CardInfo.UpdateExchangeInfo(card.GetEvolutions())
-		//cardPage.CardInfo.UpdateEvoStats(card.GetEvolutions())
-		cardPage.CardInfo.UpdateAwakenRebirthInfo(card.GetEvolutions())
-		cardPage.CardInfo.UpdateQuotes(card)
+		updateCardPage(cardPage, card)
 		fmt.Fprintf(w, `<div>Adjusted Version<textarea name="data">%s</textarea></div>`, html.EscapeString(cardPage.String()))
 		io.WriteString(w, `</div>`)
 		io.WriteString(w, `</form>`)
@@ -268,3 +263,60 @@ func writeCardReviewForm(w io.Writer, card *vc.Card, currentID, listLength int,
 </html>
 `)
 }
+
+// botCards the cards the wiki bot updates
+func botCards(ds *vc.Dataset) vc.CardList {
+	return wikiCards(ds).Filter(func(c vc.Card) bool {
+		return c.IsClosed == 0
+	})
+}
+
+// wikiCards the cards that can have a wiki page. Only the earliest card of each name is used
+// since a wiki page covers all of the evolutions.
+func wikiCards(ds *vc.Dataset) vc.CardList {
+	tmp := ds.CardsByNameByLowestID(true)
+	ret := make(vc.CardList, 0)
+	for _, cl := range tmp {
+		ret = append(ret, cl.Earliest())
+	}
+	return ret.Filter(func(c vc.Card) bool {
+		return c.CardCharaID > 0 && c.Name != "" && c.SkillID1 > 0
+	})
+}
+
+// updateCardPage updates the wiki card page with the current card information
+func updateCardPage(cardPage *wiki.CardPage, card *vc.Card) {
+	cardPage.CardInfo.UpdateBaseData(card)
+	cardPage.CardInfo.UpdateSkills(card.GetEvolutions())
+	cardPage.CardInfo.UpdateExchangeInfo(card.GetEvolutions())
+	//cardPage.CardInfo.UpdateEvoStats(card.GetEvolutions())
+	cardPage.CardInfo.UpdateAwakenRebirthInfo(card.GetEvolutions())
+	cardPage.CardInfo.UpdateQuotes(card)
+}
+
+// WikiCardDiff fetches the wiki page for the card and returns the changes the bot would make to it
+func WikiCardDiff(card *vc.Card) (diff map[string]wiki.OldNew, err error) {
+	_, diff, err = wikiCardUpdate(card)
+	return
+}
+
+// wikiCardUpdate fetches the wiki page for the card and updates it with the card information.
+// The updated page is returned along with the changes made to it.
+func wikiCardUpdate(card *vc.Card) (cardPage *wiki.CardPage, diff map[string]wiki.OldNew, err error) {
+	cardPage, rawPagebody, err := api.GetCardPage(card)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cardPage == nil {
+		return nil, nil, fmt.Errorf("no wiki page found for card %d: %w", card.ID, api.ErrPageNotFound)
+	}
+	origCardPage := wiki.CardPage{
+		PageName: cardPage.PageName,
+	}
+	err = origCardPage.Parse(rawPagebody)
+	if err != nil {
+		return nil, nil, err
+	}
+	updateCardPage(cardPage, card)
+	return cardPage, origCardPage.CardInfo.Differences(cardPage.CardInfo), nil
+}
@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"vc_file_grouper/vc"
+	"vc_file_grouper/wiki/api"
+)
+
+// newCardCandidates the cards that could need a new wiki page, newest first
+func newCardCandidates(ds *vc.Dataset, unreleased bool) vc.CardList {
+	ret := wikiCards(ds).Filter(func(c vc.Card) bool {
+		return unreleased || c.IsClosed == 0
+	})
+	sort.Slice(ret, func(a, b int) bool { return ret[a].ID > ret[b].ID })
+	return ret
+}
+
+// NewCardPagesHandler lists the newest cards that do not have a page on the wiki yet.
+// Use ?limit= to change how many cards are checked and ?unreleased=1 to include cards that are not released.
+func NewCardPagesHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
+	limit := 200
+	if l := r.FormValue("limit"); l != "" {
+		var err error
+		if limit, err = strconv.Atoi(l); err != nil || limit < 1 {
+			http.Error(w, "Invalid limit: "+l, http.StatusBadRequest)
+			return
+		}
+	}
+	unreleased := r.FormValue("unreleased") == "1"
+
+	cards := newCardCandidates(ds, unreleased)
+	if len(cards) > limit {
+		cards = cards[:limit]
+	}
+	pageNames := make([]string, 0, len(cards))
+	for _, card := range cards {
+		pageNames = append(pageNames, api.CardNameToWiki(card.Name))
+	}
+
+	io.WriteString(w, "<html><head><title>Wikibot new card pages</title>\n")
+	io.WriteString(w, "<style>table, th, td {border: 1px solid black;};</style>")
+	io.WriteString(w, "</head><body>\n")
+	io.WriteString(w, `<p><a href="/wikibot">Wikibot home</a></p>`)
+	fmt.Fprintf(w, "<p>Checked the newest %d cards.", len(cards))
+	if unreleased {
+		fmt.Fprintf(w, ` <a href="?limit=%d">Only released cards</a>`, limit)
+	} else {
+		fmt.Fprintf(w, ` <a href="?limit=%d&unreleased=1">Include unreleased cards</a>`, limit)
+	}
+	io.WriteString(w, "</p>\n")
+
+	missing, err := api.MissingPages(pageNames)
+	if err != nil {
+		fmt.Fprintf(w, "<h1>%s</h1>", html.EscapeString(err.Error()))
+		io.WriteString(w, "</body></html>")
+		return
+	}
+	if len(missing) == 0 {
+		io.WriteString(w, "<p>All the cards already have a wiki page.</p></body></html>")
+		return
+	}
+
+	io.WriteString(w, "<table><thead><tr><th>_id</th><th>Image</th><th>Name</th><th>Rarity</th><th>Element</th><th>Released</th><th></th></tr></thead><tbody>\n")
+	for i, card := range cards {
+		if !missing[pageNames[i]] {
+			continue
+		}
+		fmt.Fprintf(w, "<tr><td>%[1]d</td><td><img src=\"/images/cardthumb/%[2]s\"/></td><td><a href=\"/cards/detail/%[1]d\">%[3]s</a></td><td>%[4]s</td><td>%[5]s</td><td>%[6]t</td><td><a href=\"/wikibot/newCards/create/%[1]d\">Preview and create</a></td></tr>\n",
+			card.ID,
+			card.Image(),
+			html.EscapeString(card.Name),
+			card.MainRarity(),
+			card.Element(),
+			card.IsClosed == 0,
+		)
+	}
+	io.WriteString(w, "</tbody></table></body></html>")
+}
+
+// NewCardPageHandler previews the wiki page that will be created for a card.
+// Posting the form creates the page and uploads the card and icon images. If the page
+// already exists, only the images are uploaded, so a failed upload can be sent again.
+func NewCardPageHandler(w http.ResponseWriter, r *http.Request, ds *vc.Dataset) {
+	// card id is the last part of the path
+	pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	cardID, err := strconv.Atoi(pathParts[len(pathParts)-1])
+	if err != nil {
+		http.Error(w, "Invalid card id", http.StatusBadRequest)
+		return
+	}
+	card := ds.CardScan(cardID)
+	if card == nil {
+		http.Error(w, "Card not found", http.StatusNotFound)
+		return
+	}
+
+	summary := r.FormValue("summary")
+	var result string
+	if r.Method == "POST" {
+		created := "The page was created"
+		err = api.CreateCardPage(card, summary)
+		if errors.Is(err, api.ErrPageExists) {
+			created = "The page already exists"
+			err = nil
+		}
+		if err == nil {
+			err = api.UploadNewCardUniqueImages(card)
+			if err == nil {
+				result = created + " and the images were uploaded"
+			} else {
+				result = created + ", but the images could not be uploaded. Send the form again to retry the upload"
+			}
+		}
+	}
+
+	cardPage := api.NewCardPage(card)
+	evos := card.GetEvolutions()
+
+	fmt.Fprintf(w, "<html><head><title>New wiki page: %s</title>\n", html.EscapeString(card.Name))
+	io.WriteString(w, `<style type="text/css">
+			pre {
+				padding:5px;
+				border:solid black 1px;
+				max-height: 600px;
+				overflow: auto;
+			}
+		</style>`)
+	io.WriteString(w, "</head><body>\n")
+	io.WriteString(w, `<p><a href="/wikibot/newCards">New card pages</a> <a href="/wikibot">Wikibot home</a></p>`)
+	fmt.Fprintf(w, "<h1>%s</h1>\n", html.EscapeString(card.Name))
+	if result != "" {
+		fmt.Fprintf(w, "<h2>%s</h2>\n", result)
+	}
+	if err != nil {
+		fmt.Fprintf(w, "<h2>%s</h2>\n", html.EscapeString(err.Error()))
+	}
+
+	fmt.Fprintf(w, "<p>Page: <a href=\"%s\">%s</a></p>\n", html.EscapeString(api.PageURL(cardPage.PageName)), html.EscapeString(card.Name))
+	io.WriteString(w, "<h2>Images to upload</h2>\n<div>")
+	for _, evoID := range card.EvosWithDistinctImages(false) {
+		evo := evos[evoID]
+		fmt.Fprintf(w, `<div style="float: left; margin: 3px"><img src="/images/card/%s" height="300"/><br />%s.png</div>`,
+			evo.Image(),
+			html.EscapeString(evo.GetEvoImageName(false)),
+		)
+	}
+	for _, evoID := range card.EvosWithDistinctImages(true) {
+		evo := evos[evoID]
+		fmt.Fprintf(w, `<div style="float: left; margin: 3px"><img src="/images/cardthumb/%s"/><br />%s_icon.png</div>`,
+			evo.Image(),
+			html.EscapeString(evo.GetEvoImageName(true)),
+		)
+	}
+	io.WriteString(w, "</div><div style=\"clear: both\"></div>\n")
+
+	io.WriteString(w, "<h2>Page</h2>\n")
+	fmt.Fprintf(w, "<pre>%s</pre>\n", html.EscapeString(cardPage.String()))
+	fmt.Fprintf(w, `<form method="post">
+<div><label for="f_summary">Bot Edit Summary: <input id="f_summary" name="summary" type="text" value="%s"/></label></div>
+<button type="submit">Create the page and upload the images</button>
+</form>
+`,
+		html.EscapeString(summary),
+	)
+	io.WriteString(w, "</body></html>")
+}
@@ -8,17 +8,23 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"vc_file_grouper/handler"
 	"vc_file_grouper/vc"
+	"vc_file_grouper/wiki/api"
 )
 
 // Main function that starts the program
 func main() {
 
-	cmdLang := flag.String("lang", "en", "The language pack to use. 'en' for English, 'zhs' for Chinese. ")
+	cmdLang := flag.String("lang", "en", "The language pack to use. 'en' for English, 'zhs' for Chinese. A comma separated list such as 'en,zhs' also loads the other language packs for comparison. ")
 	cmdHelp := flag.Bool("help", false, "Show the help message")
 	cmdDbg := flag.Bool("debug", false, "Outputs log messages to the standard console")
+	cmdWiki := flag.String("wiki", "", "The wiki the bot works with. Defaults to the fandom wiki. Use a URL for another MediaWiki site, 'fake' for an in-memory test wiki or 'fake:/some/folder' to keep the test wiki pages in a folder. ")
+	cmdEditInterval := flag.Duration("editInterval", 0, "The least amount of time to wait between wiki edits and uploads, e.g. '2s'. ")
+	cmdDecodeDir := flag.String("decodeDir", handler.DecodeDir, "The folder decoded data files are written to. ")
+	cmdWatch := flag.Duration("watch", 0, "How often to check the data files for changes and reload them, e.g. '30s'. 0 turns off the watch. ")
 	flag.Parse()
 
 	if *cmdHelp {
@@ -30,105 +36,141 @@ func main() {
 		log.SetOutput(ioutil.Discard)
 	}
 
-	if cmdLang == nil {
-		vc.LangPack = "en"
-	} else {
-		vc.LangPack = *cmdLang
+	if *cmdWiki != "" {
+		api.Site = wikiSite(*cmdWiki)
 	}
+	api.EditInterval = *cmdEditInterval
+	handler.DecodeDir = *cmdDecodeDir
 
-	if len(flag.Args()) == 0 {
-		vc.FilePath = "."
-	} else {
-		vc.FilePath = filepath.Clean(flag.Args()[0])
+	langs := strings.Split(*cmdLang, ",")
+	for i := range langs {
+		langs[i] = strings.TrimSpace(langs[i])
+	}
+
+	if len(flag.Args()) > 0 {
+		if cmd, ok := commands[flag.Args()[0]]; ok {
+			os.Exit(runCommand(cmd, langs[0], flag.Args()[1:]))
+		}
 	}
 
-	if _, err := os.Stat(vc.FilePath); os.IsNotExist(err) {
+	dataPath := "."
+	if len(flag.Args()) > 0 {
+		dataPath = filepath.Clean(flag.Args()[0])
+	}
+
+	ds := vc.NewDataset(dataPath, langs[0], langs[1:]...)
+	if _, err := os.Stat(dataPath); os.IsNotExist(err) {
 		usage()
-		vc.Data = &vc.VFile{}
 		//return
 	} else {
-		vc.ReadMasterData(vc.FilePath)
+		if ds.ReadMasterData() == nil {
+			handler.WarnSchemaDrift(ds)
+		}
+	}
+	current := handler.NewCurrentDataset(ds)
+	if *cmdWatch > 0 {
+		go current.Watch(*cmdWatch, nil)
 	}
 
 	//main page
-	http.HandleFunc("/", handler.MasterDataHandler)
+	http.HandleFunc("/", current.Handle(handler.MasterDataHandler))
 	http.HandleFunc("/css/", handler.CSSHandler)
 	//image locations
-	http.HandleFunc("/images/card/", handler.ImageCardHandler)
-	http.HandleFunc("/images/cardthumb/", handler.ImageCardThumbHandler)
-	http.HandleFunc("/images/cardHD/", handler.ImageCardHDHandler)
-	http.HandleFunc("/images/cardSD/", handler.ImageCardSDHandler)
-	http.HandleFunc("/images/event/", handler.ImageHandlerFor("/event/", "/event/"))
-	http.HandleFunc("/images/battle/", handler.ImageHandlerFor("/battle/", "/battle/"))
-	http.HandleFunc("/images/garden/", handler.ImageHandlerFor("/garden/", "/garden/"))
-	http.HandleFunc("/images/garden/map/", handler.StructureImagesHandler)
-	http.HandleFunc("/images/dungeon/", handler.ImageHandlerFor("/dungeon/", "/dungeon/"))
-	http.HandleFunc("/images/alliance/", handler.ImageHandlerFor("/alliance/", "/guild/"))
-	http.HandleFunc("/images/summon/", handler.ImageHandlerFor("/summon/", "/gacha/"))
-	http.HandleFunc("/images/item/", handler.ImageHandlerFor("/item/", "/item/"))
-	http.HandleFunc("/images/treasure/", handler.ImageHandlerFor("/treasure/", "/treasure/"))
-	http.HandleFunc("/images/navi/", handler.ImageHandlerFor("/navi/", "/navi/"))
-	http.HandleFunc("/images/weapon/", handler.ImageHandlerFor("/weapon/", "/weapon/"))
-	http.HandleFunc("/images/weaponevent/", handler.ImageHandlerFor("/weaponevent/", "/weaponevent/"))
+	http.HandleFunc("/images/card/", current.Handle(handler.ImageCardHandler))
+	http.HandleFunc("/images/cardthumb/", current.Handle(handler.ImageCardThumbHandler))
+	http.HandleFunc("/images/cardHD/", current.Handle(handler.ImageCardHDHandler))
+	http.HandleFunc("/images/cardSD/", current.Handle(handler.ImageCardSDHandler))
+	http.HandleFunc("/images/event/", current.Handle(handler.ImageHandlerFor("/event/", "/event/")))
+	http.HandleFunc("/images/battle/", current.Handle(handler.ImageHandlerFor("/battle/", "/battle/")))
+	http.HandleFunc("/images/garden/", current.Handle(handler.ImageHandlerFor("/garden/", "/garden/")))
+	http.HandleFunc("/images/garden/map/", current.Handle(handler.StructureImagesHandler))
+	http.HandleFunc("/images/dungeon/", current.Handle(handler.ImageHandlerFor("/dungeon/", "/dungeon/")))
+	http.HandleFunc("/images/alliance/", current.Handle(handler.ImageHandlerFor("/alliance/", "/guild/")))
+	http.HandleFunc("/images/summon/", current.Handle(handler.ImageHandlerFor("/summon/", "/gacha/")))
+	http.HandleFunc("/images/item/", current.Handle(handler.ImageHandlerFor("/item/", "/item/")))
+	http.HandleFunc("/images/treasure/", current.Handle(handler.ImageHandlerFor("/treasure/", "/treasure/")))
+	http.HandleFunc("/images/navi/", current.Handle(handler.ImageHandlerFor("/navi/", "/navi/")))
+	http.HandleFunc("/images/weapon/", current.Handle(handler.ImageHandlerFor("/weapon/", "/weapon/")))
+	http.HandleFunc("/images/weaponevent/", current.Handle(handler.ImageHandlerFor("/weaponevent/", "/weaponevent/")))
 
 	// vc master data
-	http.HandleFunc("/config/dataLoc", handler.ConfigDataLocHandler)
+	http.HandleFunc("/config/dataLoc", handler.ConfigDataLocHandler(current))
 	http.HandleFunc("/config/setBotCreds", handler.ConfigBotCredsHandler)
+	http.HandleFunc("/config/overrides", handler.ConfigOverridesHandler(current))
 	//dynamic pages
-	http.HandleFunc("/cards/", handler.CardHandler)
-	http.HandleFunc("/cards/table/", handler.CardTableHandler)
-	http.HandleFunc("/cards/csv/", handler.CardCsvHandler)
-	http.HandleFunc("/cards/glrcsv/", handler.CardCsvGLRHandler)
-	http.HandleFunc("/cards/glrjson/", handler.CardJSONStatHandler)
-	http.HandleFunc("/cards/detail/", handler.CardDetailHandler)
-	http.HandleFunc("/cards/levels/", handler.CardLevelHandler)
-	http.HandleFunc("/archwitches/", handler.ArchwitchHandler)
-	http.HandleFunc("/characters/", handler.CharacterTableHandler)
-	http.HandleFunc("/characters/detail/", handler.CharacterDetailHandler)
+	http.HandleFunc("/cards/", current.Handle(handler.CardHandler))
+	http.HandleFunc("/cards/table/", current.Handle(handler.CardTableHandler))
+	http.HandleFunc("/cards/csv/", current.Handle(handler.CardCsvHandler))
+	http.HandleFunc("/cards/glrcsv/", current.Handle(handler.CardCsvGLRHandler))
+	http.HandleFunc("/cards/glrjson/", current.Handle(handler.CardJSONStatHandler))
+	http.HandleFunc("/cards/detail/", current.Handle(handler.CardDetailHandler))
+	http.HandleFunc("/cards/levels/", current.Handle(handler.CardLevelHandler))
+	http.HandleFunc("/cards/planner/", current.Handle(handler.EvoPlanHandler))
+	http.HandleFunc("/cards/levelup/", current.Handle(handler.LevelUpHandler))
+	http.HandleFunc("/cards/cost/", current.Handle(handler.CostRollupHandler))
+	http.HandleFunc("/cards/skillcost/", current.Handle(handler.SkillCostHandler))
+	http.HandleFunc("/archwitches/", current.Handle(handler.ArchwitchHandler))
+	http.HandleFunc("/characters/", current.Handle(handler.CharacterTableHandler))
+	http.HandleFunc("/characters/detail/", current.Handle(handler.CharacterDetailHandler))
 	// http.HandleFunc("/character/csv/", handler.CharacterCsvHandler)
 
-	http.HandleFunc("/weapons/", handler.WeaponHandler)
-	http.HandleFunc("/weapons/detail/", handler.WeaponDetailHandler)
+	http.HandleFunc("/weapons/", current.Handle(handler.WeaponHandler))
+	http.HandleFunc("/weapons/detail/", current.Handle(handler.WeaponDetailHandler))
 
-	http.HandleFunc("/items/", handler.ItemHandler)
+	http.HandleFunc("/items/", current.Handle(handler.ItemHandler))
 
 	http.HandleFunc("/skills/", handler.SkillTableHandler)
-	http.HandleFunc("/skills/csv/", handler.SkillCsvHandler)
+	http.HandleFunc("/skills/csv/", current.Handle(handler.SkillCsvHandler))
 
-	http.HandleFunc("/deckbonus/", handler.DeckBonusHandler)
-	http.HandleFunc("/deckbonus/WIKI/", handler.DeckBonusWikiHandler)
+	http.HandleFunc("/deckbonus/", current.Handle(handler.DeckBonusHandler))
+	http.HandleFunc("/deckbonus/WIKI/", current.Handle(handler.DeckBonusWikiHandler))
+	http.HandleFunc("/deck/", current.Handle(handler.DeckBuilderHandler))
 
-	http.HandleFunc("/events/", handler.EventHandler)
-	http.HandleFunc("/events/detail/", handler.EventDetailHandler)
-	http.HandleFunc("/events/dungeonScenario/", handler.ScenarioHandler("dungeon", "DRV"))
-	http.HandleFunc("/events/towerScenario/", handler.ScenarioHandler("tower", "Tower"))
-	http.HandleFunc("/events/weaponScenario/", handler.ScenarioHandler("weapon_event", "Weapon Event"))
+	http.HandleFunc("/events/", current.Handle(handler.EventHandler))
+	http.HandleFunc("/events/detail/", current.Handle(handler.EventDetailHandler))
+	http.HandleFunc("/events/dungeonScenario/", current.Handle(handler.ScenarioHandler("dungeon", "DRV")))
+	http.HandleFunc("/events/towerScenario/", current.Handle(handler.ScenarioHandler("tower", "Tower")))
+	http.HandleFunc("/events/weaponScenario/", current.Handle(handler.ScenarioHandler("weapon_event", "Weapon Event")))
 
 	http.HandleFunc("/wikibot/", handler.WikibotHandler)
-	http.HandleFunc("/wikibot/testCardFetch/", handler.TestCardFetchHandler)
+	http.HandleFunc("/wikibot/testCardFetch/", current.Handle(handler.TestCardFetchHandler))
 	http.HandleFunc("/wikibot/testLogin/", handler.TestLoginHandler)
-	http.HandleFunc("/wikibot/startMassUpdate/", handler.StartMassUpdateCardsHandler)
+	http.HandleFunc("/wikibot/startMassUpdate/", current.Handle(handler.StartMassUpdateCardsHandler))
+	http.HandleFunc("/wikibot/batch/", current.Handle(handler.BotBatchHandler))
+	http.HandleFunc("/wikibot/batch/status/", handler.BotBatchStatusHandler)
+	http.HandleFunc("/wikibot/newCards/", current.Handle(handler.NewCardPagesHandler))
+	http.HandleFunc("/wikibot/newCards/create/", current.Handle(handler.NewCardPageHandler))
 
-	http.HandleFunc("/thor/", handler.ThorHandler)
+	http.HandleFunc("/thor/", current.Handle(handler.ThorHandler))
 
-	http.HandleFunc("/maps/", handler.MapHandler)
+	http.HandleFunc("/maps/", current.Handle(handler.MapHandler))
 
-	http.HandleFunc("/strb/", handler.StrbHandler)
+	http.HandleFunc("/strb/", current.Handle(handler.StrbHandler))
 
-	http.HandleFunc("/garden/structures/", handler.StructureListHandler)
-	http.HandleFunc("/garden/structures/detail/", handler.StructureDetailHandler)
+	http.HandleFunc("/garden/structures/", current.Handle(handler.StructureListHandler))
+	http.HandleFunc("/garden/structures/detail/", current.Handle(handler.StructureDetailHandler))
 
-	http.HandleFunc("/awakenings/", handler.AwakeningsTableHandler)
-	http.HandleFunc("/awakenings/csv/", handler.AwakeningsCsvHandler)
+	http.HandleFunc("/awakenings/", current.Handle(handler.AwakeningsTableHandler))
+	http.HandleFunc("/awakenings/csv/", current.Handle(handler.AwakeningsCsvHandler))
 
-	http.HandleFunc("/decode/", handler.DecodeHandler)
+	http.HandleFunc("/decode/", current.Handle(handler.DecodeHandler))
+	http.HandleFunc("/encode/", current.Handle(handler.EncodeHandler))
 
-	http.HandleFunc("/zipData/", handler.ZipDataHandler)
+	http.HandleFunc("/zipData/", current.Handle(handler.ZipDataHandler))
 
-	http.HandleFunc("/downloadMaps/", handler.DownloadAwMapsHandler)
+	http.HandleFunc("/downloadMaps/", current.Handle(handler.DownloadAwMapsHandler))
 
-	http.HandleFunc("/raw/", handler.RawDataHandler)
-	http.HandleFunc("/raw/KEYS", handler.RawDataKeysHandler)
+	http.HandleFunc("/api/", current.Handle(handler.APIHandler))
+
+	http.HandleFunc("/diff/", current.Handle(handler.MasterDiffHandler))
+	http.HandleFunc("/validate/", current.Handle(handler.IntegrityHandler))
+
+	http.HandleFunc("/translations/", current.Handle(handler.TranslationsHandler))
+	http.HandleFunc("/translations/export/", current.Handle(handler.TranslationsExportHandler))
+
+	http.HandleFunc("/raw/", current.Handle(handler.RawDataHandler))
+	http.HandleFunc("/raw/KEYS", current.Handle(handler.RawDataKeysHandler))
+	http.HandleFunc("/raw/SCHEMA", current.Handle(handler.RawDataSchemaHandler))
 
 	http.HandleFunc("/SHUTDOWN/", func(w http.ResponseWriter, r *http.Request) { os.Exit(0) })
 
@@ -139,17 +181,48 @@ func main() {
 	}
 }
 
+// wikiSite the wiki for the -wiki option
+func wikiSite(site string) api.Wiki {
+	if site == "fake" {
+		return api.NewFakeWiki("")
+	}
+	if strings.HasPrefix(site, "fake:") {
+		return api.NewFakeWiki(filepath.Clean(strings.TrimPrefix(site, "fake:")))
+	}
+	return api.NewMediaWiki(strings.TrimSuffix(site, "/"))
+}
+
 // Prints useage to the console
 func usage() {
 	os.Stdout.WriteString(fmt.Sprintf("To use this program you can specify the following command options:\n"+
 		"-help\n\tShow this help message\n"+
-		"-lang\n\tSelect a language pack to use. 'en' is the default\n"+
+		"-lang\n\tSelect a language pack to use. 'en' is the default. Extra language packs can be added after a comma, e.g. 'en,zhs', to compare the translations\n"+
 		"-debug\n\tOutputs error message to the standard error console\n"+
+		"-wiki\n\tThe wiki the bot works with. A URL of a MediaWiki site, 'fake' for an in-memory test wiki or 'fake:/some/folder' for a test wiki kept in a folder\n"+
+		"-editInterval\n\tThe least amount of time to wait between wiki edits and uploads, e.g. '2s'\n"+
+		"-decodeDir\n\tThe folder decoded data files are written to. Defaults to 'decoded'\n"+
+		"-watch\n\tHow often to check the master data and string files for changes, e.g. '30s'. The data is reloaded automatically when the game data is refreshed\n"+
 		"file1\n\tlocation of the VC master data file\n"+
+		"\nInstead of starting the web server, one of the following commands can be run:\n"+
+		"export <cards|glrcards|skills|awakenings> [-format csv|json] [-o file] [file1]\n\tExports the data to the standard output or a file\n"+
+		"decode [-o dir] [-workers n] [-force] [file1]\n\tDecodes all the encoded files in the data location into a separate folder. Files that are already decoded are skipped\n"+
+		"encode [-like original] -o file <file>\n\tEncodes a changed JSON or PNG file. The header is copied from the original encoded file, or from the output file if it is already encoded\n"+
+		"strb export [-o file] <file.strb>\n\tWrites the strings of a string file as a JSON list\n"+
+		"strb import [-o file] <file.strb> <strings.json>\n\tWrites a copy of the string file with the strings from the JSON list\n"+
+		"translate export -target lang [-source lang] [-format po|xliff] [-file MsgCardName] [-o file] [file1]\n\tWrites the string files as a PO or XLIFF file for translators\n"+
+		"translate import [-o dir] <file.po|file.xlf> [file1]\n\tWrites the translated string files. Defaults to the string folder of the data\n"+
+		"zip [-o file] [file1]\n\tWrites the fan archive zip to the standard output or a file\n"+
+		"wiki diff [-o file] <card id> [file1]\n\tShows the changes the wiki bot would make to a card page\n"+
+		"diff [-format html|json] [-o file] <old file1> <new file1>\n\tShows the changes between two versions of the master data\n"+
+		"validate [-format text|html|json] [-o file] [file1]\n\tChecks the references between the master data tables. Exits with 1 if any are broken\n"+
 		"example usages:\n\t%[1]s -help\n"+
 		"\t%[1]s -lang %[2]s\n"+
 		"\t%[1]s \"%[3]s\"\n"+
-		"\t%[1]s -lang %[2]s \"%[3]s\"\n",
+		"\t%[1]s -lang %[2]s \"%[3]s\"\n"+
+		"\t%[1]s -lang en,%[2]s \"%[3]s\"\n"+
+		"\t%[1]s -watch 30s \"%[3]s\"\n"+
+		"\t%[1]s export cards -format csv -o cards.csv \"%[3]s\"\n"+
+		"\t%[1]s wiki diff 3934 \"%[3]s\"\n",
 		os.Args[0],
 		"zhs",
 		"/path/to/vc/data/file",
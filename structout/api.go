@@ -0,0 +1,298 @@
+package structout
+
+import (
+	"vc_file_grouper/vc"
+)
+
+// The API types embed the master data record so the field names match master_all,
+// and add the related information that would otherwise need to be resolved by hand.
+
+// APICard card with its skills, evolutions and related cards resolved
+type APICard struct {
+	*vc.Card
+	Rarity        string            `json:"rarity"`
+	Element       string            `json:"element"`
+	Description   string            `json:"description"`
+	Evolutions    map[string]int    `json:"evolutions"`
+	Skill1        *vc.Skill         `json:"skill_1,omitempty"`
+	Skill2        *vc.Skill         `json:"skill_2,omitempty"`
+	Skill3        *vc.Skill         `json:"skill_3,omitempty"`
+	SpecialSkill1 *vc.Skill         `json:"special_skill_1,omitempty"`
+	ThorSkill1    *vc.Skill         `json:"thor_skill_1,omitempty"`
+	AwakensTo     int               `json:"awakens_to,omitempty"`
+	AwakensFrom   int               `json:"awakens_from,omitempty"`
+	RebirthsTo    int               `json:"rebirths_to,omitempty"`
+	RebirthsFrom  int               `json:"rebirths_from,omitempty"`
+	Amalgamations []vc.Amalgamation `json:"amalgamations"`
+}
+
+// APICharacter character with its quotes and cards
+type APICharacter struct {
+	*vc.CardCharacter
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	Friendship      string `json:"friendship"`
+	Login           string `json:"login"`
+	Meet            string `json:"meet"`
+	BattleStart     string `json:"battle_start"`
+	BattleEnd       string `json:"battle_end"`
+	FriendshipMax   string `json:"friendship_max"`
+	FriendshipEvent string `json:"friendship_event"`
+	Rebirth         string `json:"rebirth"`
+	CardIDs         []int  `json:"card_ids"`
+}
+
+// APISkill skill with its levels and target information
+type APISkill struct {
+	*vc.Skill
+	Effect      string          `json:"effect"`
+	TargetScope string          `json:"target_scope"`
+	TargetLogic string          `json:"target_logic"`
+	Levels      []vc.SkillLevel `json:"levels"`
+}
+
+// APIEvent event with its archwitches and rank rewards
+type APIEvent struct {
+	*vc.Event
+	Archwitches  []APIArchwitch       `json:"archwitches"`
+	MidRewards   []vc.RankRewardSheet `json:"mid_rewards"`
+	FinalRewards []vc.RankRewardSheet `json:"final_rewards"`
+}
+
+// APIArchwitch archwitch with its card and likeability
+type APIArchwitch struct {
+	*vc.Archwitch
+	CardName    string                   `json:"card_name"`
+	Likeability []APIArchwitchFriendship `json:"likeability"`
+}
+
+// APIArchwitchFriendship friendship rate with the likeability text
+type APIArchwitchFriendship struct {
+	vc.ArchwitchFriendship
+	Likability string `json:"likability"`
+}
+
+// APIWeapon weapon with its ranks, rarities and skills
+type APIWeapon struct {
+	*vc.Weapon
+	Names            []string               `json:"names"`
+	Descriptions     []string               `json:"descriptions"`
+	Status           *vc.WeaponStatus       `json:"status"`
+	Ranks            []vc.WeaponRank        `json:"ranks"`
+	Rarities         []vc.WeaponRarity      `json:"rarities"`
+	SkillUnlocks     []APIWeaponSkillUnlock `json:"skill_unlocks"`
+	UpgradeMaterials []vc.WeaponMaterial    `json:"upgrade_materials"`
+}
+
+// APIWeaponSkillUnlock skill unlocked at a weapon rank
+type APIWeaponSkillUnlock struct {
+	vc.WeaponSkillUnlockRank
+	Description string `json:"description"`
+}
+
+// APIItem item with its descriptions
+type APIItem struct {
+	*vc.Item
+	NameEng           string `json:"name_eng"`
+	Description       string `json:"description"`
+	DescriptionInShop string `json:"description_in_shop"`
+	DescriptionSub    string `json:"description_sub"`
+	MsgUse            string `json:"msg_use"`
+}
+
+// APIStructure structure with its levels and costs
+type APIStructure struct {
+	*vc.Structure
+	Name          string              `json:"name"`
+	Description   string              `json:"description"`
+	Levels        []vc.StructureLevel `json:"levels"`
+	PurchaseCosts []vc.StructureCost  `json:"purchase_costs"`
+	CastleBonuses []vc.CastleLevel    `json:"castle_bonuses"`
+}
+
+// APIDeckBonus deck bonus with its conditions
+type APIDeckBonus struct {
+	*vc.DeckBonus
+	Conditions []APIDeckBonusCond `json:"conditions"`
+}
+
+// APIDeckBonusCond deck bonus condition with the referenced name
+type APIDeckBonusCond struct {
+	vc.DeckBonusCond
+	RefName string `json:"ref_name"`
+}
+
+// APIThorEvent thor event with its kings
+type APIThorEvent struct {
+	*vc.ThorEvent
+	Kings []vc.ThorKing `json:"kings"`
+}
+
+// ToAPICard converts a card to the API form
+func ToAPICard(c *vc.Card) APICard {
+	ret := APICard{
+		Card:          c,
+		Rarity:        c.Rarity(),
+		Element:       c.Element(),
+		Description:   c.Description(),
+		Evolutions:    make(map[string]int),
+		Skill1:        c.Skill1(),
+		Skill2:        c.Skill2(),
+		Skill3:        c.Skill3(),
+		SpecialSkill1: c.SpecialSkill1(),
+		ThorSkill1:    c.ThorSkill1(),
+		AwakensTo:     cardID(c.AwakensTo()),
+		AwakensFrom:   cardID(c.AwakensFrom()),
+		RebirthsTo:    cardID(c.RebirthsTo()),
+		RebirthsFrom:  cardID(c.RebirthsFrom()),
+		Amalgamations: c.Amalgamations(),
+	}
+	for k, evo := range c.GetEvolutions() {
+		ret.Evolutions[k] = evo.ID
+	}
+	return ret
+}
+
+// ToAPICharacter converts a character to the API form
+func ToAPICharacter(c *vc.CardCharacter) APICharacter {
+	ret := APICharacter{
+		CardCharacter:   c,
+		Description:     c.Description,
+		Friendship:      c.Friendship,
+		Login:           c.Login,
+		Meet:            c.Meet,
+		BattleStart:     c.BattleStart,
+		BattleEnd:       c.BattleEnd,
+		FriendshipMax:   c.FriendshipMax,
+		FriendshipEvent: c.FriendshipEvent,
+		Rebirth:         c.Rebirth,
+		CardIDs:         make([]int, 0),
+	}
+	if first := c.FirstEvoCard(); first != nil {
+		ret.Name = first.Name
+	}
+	for _, card := range c.Cards() {
+		ret.CardIDs = append(ret.CardIDs, card.ID)
+	}
+	return ret
+}
+
+// ToAPISkill converts a skill to the API form
+func ToAPISkill(s *vc.Skill) APISkill {
+	return APISkill{
+		Skill:       s,
+		Effect:      s.Effect(),
+		TargetScope: s.TargetScope(),
+		TargetLogic: s.TargetLogic(),
+		Levels:      s.Levels(),
+	}
+}
+
+// ToAPIEvent converts an event to the API form
+func ToAPIEvent(e *vc.Event) APIEvent {
+	ret := APIEvent{
+		Event:       e,
+		Archwitches: make([]APIArchwitch, 0),
+	}
+	for _, aw := range e.Archwitches() {
+		ret.Archwitches = append(ret.Archwitches, ToAPIArchwitch(aw))
+	}
+	if rr := e.RankRewards(); rr != nil {
+		ret.MidRewards = rr.MidRewards()
+		ret.FinalRewards = rr.FinalRewards()
+	}
+	return ret
+}
+
+// ToAPIArchwitch converts an archwitch to the API form
+func ToAPIArchwitch(a *vc.Archwitch) APIArchwitch {
+	ret := APIArchwitch{
+		Archwitch:   a,
+		Likeability: make([]APIArchwitchFriendship, 0),
+	}
+	if card := a.Dataset().CardScan(a.CardMasterID); card != nil {
+		ret.CardName = card.Name
+	}
+	for _, l := range a.Likeability() {
+		ret.Likeability = append(ret.Likeability, APIArchwitchFriendship{
+			ArchwitchFriendship: l,
+			Likability:          l.Likability,
+		})
+	}
+	return ret
+}
+
+// ToAPIWeapon converts a weapon to the API form
+func ToAPIWeapon(w *vc.Weapon) APIWeapon {
+	ret := APIWeapon{
+		Weapon:           w,
+		Names:            w.Names,
+		Descriptions:     w.Descriptions,
+		Status:           w.Status(),
+		Ranks:            w.Ranks(),
+		Rarities:         w.Rarities(),
+		SkillUnlocks:     make([]APIWeaponSkillUnlock, 0),
+		UpgradeMaterials: w.UpgradeMaterials(),
+	}
+	for _, su := range w.SkillUnlocks() {
+		ret.SkillUnlocks = append(ret.SkillUnlocks, APIWeaponSkillUnlock{
+			WeaponSkillUnlockRank: su,
+			Description:           su.Skill().DescriptionFormatted(),
+		})
+	}
+	return ret
+}
+
+// ToAPIItem converts an item to the API form
+func ToAPIItem(i *vc.Item) APIItem {
+	return APIItem{
+		Item:              i,
+		NameEng:           i.NameEng,
+		Description:       i.Description,
+		DescriptionInShop: i.DescriptionInShop,
+		DescriptionSub:    i.DescriptionSub,
+		MsgUse:            i.MsgUse,
+	}
+}
+
+// ToAPIStructure converts a structure to the API form
+func ToAPIStructure(s *vc.Structure) APIStructure {
+	return APIStructure{
+		Structure:     s,
+		Name:          s.Name,
+		Description:   s.Description,
+		Levels:        s.Levels(),
+		PurchaseCosts: s.PurchaseCosts(),
+		CastleBonuses: s.CastleBonuses(),
+	}
+}
+
+// ToAPIDeckBonus converts a deck bonus to the API form
+func ToAPIDeckBonus(d *vc.DeckBonus) APIDeckBonus {
+	ret := APIDeckBonus{
+		DeckBonus:  d,
+		Conditions: make([]APIDeckBonusCond, 0),
+	}
+	for _, cond := range d.Conditions() {
+		ret.Conditions = append(ret.Conditions, APIDeckBonusCond{
+			DeckBonusCond: cond,
+			RefName:       cond.RefName,
+		})
+	}
+	return ret
+}
+
+// ToAPIThorEvent converts a thor event to the API form
+func ToAPIThorEvent(t *vc.ThorEvent) APIThorEvent {
+	return APIThorEvent{
+		ThorEvent: t,
+		Kings:     t.Archwitches(),
+	}
+}
+
+func cardID(c *vc.Card) int {
+	if c == nil {
+		return 0
+	}
+	return c.ID
+}
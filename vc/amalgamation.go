@@ -2,6 +2,7 @@ package vc
 
 // Amalgamation List of possible Fusions (Amalgamations) from master file field "fusion_list"
 type Amalgamation struct {
+	datasetRef
 	// internal id
 	ID int `json:"_id"`
 	// card 1
@@ -39,28 +40,28 @@ func (a *Amalgamation) Materials() CardList {
 
 // Result result card from the amalgamation
 func (a *Amalgamation) Result() *Card {
-	return CardScan(a.FusionCardID)
+	return a.ds.CardScan(a.FusionCardID)
 }
 
 // MaterialsOnly material used in the amalgamation excluding the result
 func (a *Amalgamation) MaterialsOnly() CardList {
 	ret := make(CardList, 0)
-	c := CardScan(a.Material1)
+	c := a.ds.CardScan(a.Material1)
 	if c != nil {
 		ret = append(ret, c)
 	}
-	c = CardScan(a.Material2)
+	c = a.ds.CardScan(a.Material2)
 	if c != nil {
 		ret = append(ret, c)
 	}
 	if a.Material3 > 0 {
-		c = CardScan(a.Material3)
+		c = a.ds.CardScan(a.Material3)
 		if c != nil {
 			ret = append(ret, c)
 		}
 	}
 	if a.Material4 > 0 {
-		c = CardScan(a.Material4)
+		c = a.ds.CardScan(a.Material4)
 		if c != nil {
 			ret = append(ret, c)
 		}
@@ -7,6 +7,7 @@ import (
 
 // Archwitch represents the "kings" istructure in the data file
 type Archwitch struct {
+	datasetRef
 	ID            int    `json:"_id"`
 	KingSeriesID  int    `json:"king_series_id"`
 	CardMasterID  int    `json:"card_master_id"`
@@ -34,6 +35,7 @@ type ArchwitchList []*Archwitch
 // list of AW events and the RR prizes Indicates what "event"
 // the archwitch was a part of.
 type ArchwitchSeries struct {
+	datasetRef
 	ID                   int       `json:"_id"`
 	RewardCardID         int       `json:"reward_card_id"`
 	PublicStartDatetime  Timestamp `json:"public_start_datetime"`
@@ -42,6 +44,9 @@ type ArchwitchSeries struct {
 	IsBeginnerKing       int       `json:"is_beginner_king"`
 	Description          string    `json:"-"`
 	archwitches          ArchwitchList
+
+	// Translations text from the string files in each loaded language pack
+	Translations Translations `json:"-"`
 }
 
 //ArchwitchFriendship is "king_friendship" in the data file.
@@ -52,6 +57,9 @@ type ArchwitchFriendship struct {
 	Friendship int    `json:"friendship"`
 	UpRate     int    `json:"up_rate"`
 	Likability string `json:"-"` // MsgKingFriendshipDesc_en.strb
+
+	// Translations text from the string files in each loaded language pack
+	Translations Translations `json:"-"`
 }
 
 /*
@@ -83,18 +91,25 @@ func (a *Archwitch) Likeability() []ArchwitchFriendship {
 	if a == nil {
 		return []ArchwitchFriendship{}
 	}
-	if a.likeability == nil {
-		a.likeability = make([]ArchwitchFriendship, 0)
-		for _, af := range Data.ArchwitchFriendships {
+	lock := a.cacheLock()
+	lock.RLock()
+	likeability := a.likeability
+	lock.RUnlock()
+	if likeability == nil {
+		likeability = make([]ArchwitchFriendship, 0)
+		for _, af := range a.ds.Data.ArchwitchFriendships {
 			if a.ID == af.KingID {
-				a.likeability = append(a.likeability, af)
+				likeability = append(likeability, af)
 			}
 		}
-		sort.Slice(a.likeability, func(l1, l2 int) bool {
-			return a.likeability[l1].Friendship < a.likeability[l2].Friendship
+		sort.Slice(likeability, func(l1, l2 int) bool {
+			return likeability[l1].Friendship < likeability[l2].Friendship
 		})
+		lock.Lock()
+		a.likeability = likeability
+		lock.Unlock()
 	}
-	return a.likeability
+	return likeability
 }
 
 // IsFAW returns true if this AW is a FAW
@@ -114,20 +129,27 @@ func (a *Archwitch) IsAW() bool {
 
 // Archwitches in this AW Series
 func (as *ArchwitchSeries) Archwitches() ArchwitchList {
-	if as.archwitches == nil {
-		as.archwitches = make(ArchwitchList, 0)
-		for _, a := range Data.Archwitches {
+	lock := as.cacheLock()
+	lock.RLock()
+	aws := as.archwitches
+	lock.RUnlock()
+	if aws == nil {
+		aws = make(ArchwitchList, 0)
+		for _, a := range as.ds.Data.Archwitches {
 			if as.ID == a.KingSeriesID {
-				as.archwitches = append(as.archwitches, a)
+				aws = append(aws, a)
 			}
 		}
-		sort.Slice(as.archwitches, func(a, b int) bool {
-			awa := as.archwitches[a]
-			awb := as.archwitches[b]
+		sort.Slice(aws, func(a, b int) bool {
+			awa := aws[a]
+			awb := aws[b]
 			return awa.ID < awb.ID
 		})
+		lock.Lock()
+		as.archwitches = aws
+		lock.Unlock()
 	}
-	return as.archwitches
+	return aws
 }
 
 // Earliest gets the earliest AW from the list
@@ -0,0 +1,223 @@
+package vc
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// decodeManifestName file in the output folder that remembers which version of each file was decoded
+const decodeManifestName = ".decoded.json"
+
+// defaultManifestSaveEvery number of decoded files between manifest saves when SaveEvery is not set
+const defaultManifestSaveEvery = 100
+
+// BulkDecoder decodes all the encoded files in a data location into a separate folder.
+// Several files are decoded at once and files that were already decoded are skipped.
+type BulkDecoder struct {
+	// Root the data location to decode
+	Root string
+	// OutDir folder the decoded files are written to. The folders of the data location are kept.
+	OutDir string
+	// Workers number of files to decode at the same time. Defaults to the number of CPUs.
+	Workers int
+	// Force decodes the files again even if they are up to date
+	Force bool
+	// SaveEvery number of decoded files between saves of the manifest, so a stopped run keeps most of its work.
+	// Defaults to 100.
+	SaveEvery int
+}
+
+// DecodeResult the outcome of decoding one file
+type DecodeResult struct {
+	// File the encoded file
+	File string
+	// NewFile the decoded file
+	NewFile string
+	// Skipped true if the decoded file was already up to date
+	Skipped bool
+	Err     error
+}
+
+// DecodeSummary the outcome of a BulkDecoder run
+type DecodeSummary struct {
+	Decoded int
+	Skipped int
+	// Failed the files that could not be decoded
+	Failed []DecodeResult
+}
+
+// decodedFile a file that was decoded by an earlier run
+type decodedFile struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+	NewFile string    `json:"newFile"`
+}
+
+// Run decodes the files. Bad files are reported and the run carries on with the rest.
+// The progress function, if not nil, is called after each encoded file. It is never called by two files at once.
+func (b *BulkDecoder) Run(progress func(DecodeResult)) (summary DecodeSummary, err error) {
+	if err = os.MkdirAll(b.OutDir, 0755); err != nil {
+		return
+	}
+	outDir, err := filepath.Abs(b.OutDir)
+	if err != nil {
+		return
+	}
+	manifest := b.readManifest()
+	var manifestLock sync.Mutex
+
+	workers := b.Workers
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	paths := make(chan string)
+	results := make(chan DecodeResult)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				rel, err := filepath.Rel(b.Root, path)
+				if err != nil {
+					results <- DecodeResult{File: path, Err: err}
+					continue
+				}
+				info, err := os.Stat(path)
+				if err != nil {
+					results <- DecodeResult{File: path, Err: err}
+					continue
+				}
+				manifestLock.Lock()
+				prev, ok := manifest[rel]
+				manifestLock.Unlock()
+				if ok && !b.Force && prev.Size == info.Size() && prev.ModTime.Equal(info.ModTime()) {
+					if _, err := os.Stat(filepath.Join(b.OutDir, prev.NewFile)); err == nil {
+						results <- DecodeResult{File: path, NewFile: filepath.Join(b.OutDir, prev.NewFile), Skipped: true}
+						continue
+					}
+				}
+
+				encoded, err := IsFileEncoded(path)
+				if err != nil || !encoded {
+					if err != nil && info.Size() >= 4 {
+						results <- DecodeResult{File: path, Err: err}
+					}
+					continue
+				}
+				newFile, err := decodeTo(path, filepath.Join(b.OutDir, rel))
+				if err != nil {
+					results <- DecodeResult{File: path, Err: err}
+					continue
+				}
+				newRel, _ := filepath.Rel(b.OutDir, newFile)
+				manifestLock.Lock()
+				manifest[rel] = decodedFile{Size: info.Size(), ModTime: info.ModTime(), NewFile: newRel}
+				manifestLock.Unlock()
+				results <- DecodeResult{File: path, NewFile: newFile}
+			}
+		}()
+	}
+
+	go func() {
+		filepath.Walk(b.Root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				results <- DecodeResult{File: path, Err: err}
+				return nil
+			}
+			if info.IsDir() {
+				// don't decode the output of an earlier run if it is kept in the data location
+				if abs, aerr := filepath.Abs(path); aerr == nil && abs == outDir {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			paths <- path
+			return nil
+		})
+		close(paths)
+		wg.Wait()
+		close(results)
+	}()
+
+	saveEvery := b.SaveEvery
+	if saveEvery < 1 {
+		saveEvery = defaultManifestSaveEvery
+	}
+	for r := range results {
+		switch {
+		case r.Err != nil:
+			summary.Failed = append(summary.Failed, r)
+		case r.Skipped:
+			summary.Skipped++
+		default:
+			summary.Decoded++
+			if summary.Decoded%saveEvery == 0 {
+				manifestLock.Lock()
+				werr := b.writeManifest(manifest)
+				manifestLock.Unlock()
+				if werr != nil {
+					summary.Failed = append(summary.Failed, DecodeResult{File: filepath.Join(b.OutDir, decodeManifestName), Err: werr})
+				}
+			}
+		}
+		if progress != nil {
+			progress(r)
+		}
+	}
+
+	manifestLock.Lock()
+	err = b.writeManifest(manifest)
+	manifestLock.Unlock()
+	return
+}
+
+func (b *BulkDecoder) readManifest() map[string]decodedFile {
+	manifest := make(map[string]decodedFile)
+	data, err := ioutil.ReadFile(filepath.Join(b.OutDir, decodeManifestName))
+	if err == nil {
+		json.Unmarshal(data, &manifest)
+	}
+	return manifest
+}
+
+func (b *BulkDecoder) writeManifest(manifest map[string]decodedFile) error {
+	data, err := json.MarshalIndent(manifest, "", "\t")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(b.OutDir, decodeManifestName), data)
+}
+
+// decodeTo decodes the file and saves the result as outFile with a .png or .json extension
+func decodeTo(file, outFile string) (string, error) {
+	data, err := Decode(file)
+	if err != nil {
+		return "", err
+	}
+	if len(data) >= 4 && bytes.Equal(data[:4], []byte{0x89, 'P', 'N', 'G'}) {
+		outFile += ".png"
+	} else {
+		outFile += ".json"
+		data = trimTrailingZeros(data)
+	}
+	if err = os.MkdirAll(filepath.Dir(outFile), 0755); err != nil {
+		return "", err
+	}
+	return outFile, writeFileAtomic(outFile, data)
+}
+
+// writeFileAtomic writes the file under a temporary name first so a stopped run never leaves half a file behind
+func writeFileAtomic(fileName string, data []byte) error {
+	tmp := fileName + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, fileName)
+}
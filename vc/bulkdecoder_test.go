@@ -0,0 +1,116 @@
+package vc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeTestFile(t *testing.T, file string, data []byte) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(file, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBulkDecoder(t *testing.T) {
+	root := t.TempDir()
+	out := t.TempDir()
+	png := []byte{0x89, 'P', 'N', 'G', 1, 2, 3, 4}
+	writeTestFile(t, filepath.Join(root, "response", "master_all"), Encode([]byte(`{"v":1}`), CodeHeader{Key: 7}))
+	writeTestFile(t, filepath.Join(root, "image", "a"), Encode(png, CodeHeader{Key: 9}))
+	writeTestFile(t, filepath.Join(root, "plain.txt"), []byte("not encoded"))
+	// has the signature but is too short to decode
+	writeTestFile(t, filepath.Join(root, "bad"), []byte("CODE1234"))
+
+	b := BulkDecoder{Root: root, OutDir: out, Workers: 2}
+	run := func(name string, decoded, skipped int) {
+		t.Helper()
+		summary, err := b.Run(nil)
+		if err != nil {
+			t.Fatalf("%s: Run returned an error: %s", name, err.Error())
+		}
+		if summary.Decoded != decoded || summary.Skipped != skipped {
+			t.Errorf("%s: %d decoded and %d skipped, expected %d and %d", name, summary.Decoded, summary.Skipped, decoded, skipped)
+		}
+		// the bad file is reported on every run without stopping the others
+		if len(summary.Failed) != 1 || summary.Failed[0].File != filepath.Join(root, "bad") {
+			t.Errorf("%s: unexpected failures %v", name, summary.Failed)
+		}
+	}
+	readOut := func(rel string) string {
+		t.Helper()
+		data, err := ioutil.ReadFile(filepath.Join(out, rel))
+		if err != nil {
+			t.Fatalf("The decoded file %s is missing: %s", rel, err.Error())
+		}
+		return string(data)
+	}
+
+	run("first run", 2, 0)
+	if got := readOut(filepath.Join("response", "master_all.json")); got != `{"v":1}` {
+		t.Errorf("Unexpected decoded master data: %s", got)
+	}
+	if got := readOut(filepath.Join("image", "a.png")); got != string(png) {
+		t.Errorf("Unexpected decoded image: %v", []byte(got))
+	}
+	if _, err := os.Stat(filepath.Join(root, "response", "master_all.json")); !os.IsNotExist(err) {
+		t.Errorf("A decoded file was written next to the game data")
+	}
+
+	run("nothing changed", 0, 2)
+
+	// a changed file is decoded again
+	master := filepath.Join(root, "response", "master_all")
+	writeTestFile(t, master, Encode([]byte(`{"v":22}`), CodeHeader{Key: 7}))
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(master, later, later); err != nil {
+		t.Fatal(err)
+	}
+	run("changed file", 1, 1)
+	if got := readOut(filepath.Join("response", "master_all.json")); got != `{"v":22}` {
+		t.Errorf("The changed master data was not decoded again: %s", got)
+	}
+
+	// a decoded file that was deleted is written again
+	if err := os.Remove(filepath.Join(out, "image", "a.png")); err != nil {
+		t.Fatal(err)
+	}
+	run("deleted output", 1, 1)
+	readOut(filepath.Join("image", "a.png"))
+
+	b.Force = true
+	run("forced", 2, 0)
+}
+
+func TestBulkDecoderSaveManifest(t *testing.T) {
+	root := t.TempDir()
+	out := t.TempDir()
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		writeTestFile(t, filepath.Join(root, name), Encode([]byte(`{"name":"`+name+`"}`), CodeHeader{Key: 5}))
+	}
+
+	// the manifest is saved during the run, not only at the end. The worker may already have decoded the next file.
+	b := BulkDecoder{Root: root, OutDir: out, Workers: 1, SaveEvery: 2}
+	decoded := 0
+	_, err := b.Run(func(r DecodeResult) {
+		if r.Err != nil || r.Skipped {
+			return
+		}
+		decoded++
+		if n, want := len(b.readManifest()), decoded-decoded%2; n < want {
+			t.Errorf("After %d decoded files the saved manifest has %d files, expected at least %d", decoded, n, want)
+		}
+	})
+	if err != nil {
+		t.Fatalf("Run returned an error: %s", err.Error())
+	}
+	if n := len(b.readManifest()); n != 5 {
+		t.Errorf("Expected all 5 files in the final manifest, got %d", n)
+	}
+}
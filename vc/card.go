@@ -21,6 +21,7 @@ import (
 
 // Card is a distinct card in the game. The card names match the ones listed in the MsgCardName_en.strb file
 type Card struct {
+	datasetRef
 	ID                        int    `json:"_id"`                                      // card id
 	CardNo                    int    `json:"card_no"`                                  // card number, matches to the image file
 	CardCharaID               int    `json:"card_chara_id"`                            // card character id
@@ -66,6 +67,9 @@ type Card struct {
 	prevEvo  *Card
 	nextEvo  *Card
 	_allEvos map[string]*Card
+
+	// Translations text from the string files in each loaded language pack
+	Translations Translations `json:"-"`
 }
 
 // CardList helper interface for looking at lists of cards
@@ -120,7 +124,7 @@ func (c *Card) EvosWithDistinctImages(icons bool) []string {
 	images := make(map[string][]byte)
 	for _, evoID := range EvoOrder {
 		if evo, ok := evos[evoID]; ok {
-			file := filepath.Join(FilePath, "card")
+			file := filepath.Join(c.ds.FilePath, "card")
 			if icons {
 				file = filepath.Join(file, "thumb")
 			} else {
@@ -160,9 +164,9 @@ func (c *Card) Rarity() (ret string) {
 		return "?"
 	}
 
-	ret = Rarity[c.CardRareID-1]
+	ret = c.ds.Rarity[c.CardRareID-1]
 	// need to handle X cards that have actual Evolutions (Philospher Stone)
-	if ret == "X" && c.EvolutionRank > 0 && c.EvolutionRank == c.LastEvolutionRank && len(c._allEvos) > 1 {
+	if ret == "X" && c.EvolutionRank > 0 && c.EvolutionRank == c.LastEvolutionRank && len(c.cachedEvolutions()) > 1 {
 		ret = "HX"
 	}
 	return
@@ -191,7 +195,7 @@ func (c *Card) MainRarity() string {
 
 // CardRarity with full rarity information
 func (c *Card) CardRarity() *CardRarity {
-	return CardRarityScan(c.CardRareID)
+	return c.ds.CardRarityScan(c.CardRareID)
 }
 
 // Symbol with full rarity information
@@ -199,8 +203,8 @@ func (c *Card) Symbol() string {
 	if c == nil || c.CardSymbolID == 0 {
 		return ""
 	}
-	if c.CardSymbolID > 0 && c.CardSymbolID < len(Data.SymbolNames) {
-		return Data.SymbolNames[c.CardSymbolID]
+	if c.CardSymbolID > 0 && c.CardSymbolID < len(c.ds.Data.SymbolNames) {
+		return c.ds.Data.SymbolNames[c.CardSymbolID]
 	}
 	log.Printf("Unknown symbol id %d. Unable to locate name", c.CardSymbolID)
 	return strconv.Itoa(c.CardSymbolID)
@@ -242,11 +246,11 @@ func (c *Card) EvoIsReborn() bool {
 }
 
 //CardRarityScan scans for a card rarity by id
-func CardRarityScan(id int) *CardRarity {
+func (d *Dataset) CardRarityScan(id int) *CardRarity {
 	if id >= 0 {
-		for idx, cr := range Data.CardRarities {
+		for idx, cr := range d.Data.CardRarities {
 			if cr.ID == id {
-				return &(Data.CardRarities[idx])
+				return &(d.Data.CardRarities[idx])
 			}
 		}
 	}
@@ -265,8 +269,7 @@ func (c *Card) Element() string {
 // IsRetired returns true if this card is no longer available because a newer card
 // of the same character was released.
 func (c *Card) IsRetired() bool {
-	oldIDx := sort.SearchInts(retiredCards, c.ID)
-	return oldIDx >= 0 && oldIDx < len(retiredCards) && retiredCards[oldIDx] == c.ID
+	return c.ds.nameOverrides().IsRetired(c.ID)
 }
 
 // Character information of the card
@@ -274,15 +277,17 @@ func (c *Card) Character() *CardCharacter {
 	if c == nil {
 		return nil
 	}
-	if c.character == nil && c.CardCharaID > 0 {
-		for k, val := range Data.CardCharacters {
-			if val.ID == c.CardCharaID {
-				c.character = &(Data.CardCharacters[k])
-				break
-			}
-		}
+	lock := c.cacheLock()
+	lock.RLock()
+	character := c.character
+	lock.RUnlock()
+	if character == nil && c.CardCharaID > 0 {
+		character = c.ds.lookups().characters[c.CardCharaID]
+		lock.Lock()
+		c.character = character
+		lock.Unlock()
 	}
-	return c.character
+	return character
 }
 
 // NextEvo is the next evolution of this card, or nil if no further evolutions are possible.
@@ -292,16 +297,19 @@ func (c *Card) NextEvo() *Card {
 		// bad data
 		return nil
 	}
-	if c.nextEvo == nil {
+	lock := c.cacheLock()
+	lock.RLock()
+	nextEvo := c.nextEvo
+	lock.RUnlock()
+	if nextEvo == nil {
 		if c.CardCharaID <= 0 || c.EvolutionCardID <= 0 || c.EvoIsHigh() {
 			return nil
 		}
 
 		var tmp *Card
-		character := c.Character()
-		for i, cd := range character.Cards() {
+		for _, cd := range c.Character().Cards() {
 			if cd.ID == c.EvolutionCardID {
-				tmp = character._cards[i]
+				tmp = cd
 			}
 		}
 
@@ -313,25 +321,32 @@ func (c *Card) NextEvo() *Card {
 			// bad data...
 			return nil
 		}
+		lock.Lock()
 		c.nextEvo = tmp
 		tmp.prevEvo = c
+		lock.Unlock()
+		nextEvo = tmp
 	}
-	return c.nextEvo
+	return nextEvo
 }
 
 // PrevEvo is the previous evolution of this card, or nil if no further evolutions are possible.
 // Evo Accidents or amalgamtions may still be possible.
 func (c *Card) PrevEvo() *Card {
-	if c.prevEvo == nil {
+	lock := c.cacheLock()
+	lock.RLock()
+	prevEvo := c.prevEvo
+	lock.RUnlock()
+	if prevEvo == nil {
 		// no charcter ID or already lowest evo rank
 		if c.CardCharaID <= 0 || c.EvolutionRank <= 0 {
 			return nil
 		}
 
 		var tmp *Card
-		for i, cd := range c.Character().Cards() {
+		for _, cd := range c.Character().Cards() {
 			if c.ID == cd.EvolutionCardID {
-				tmp = c.Character()._cards[i]
+				tmp = cd
 			}
 		}
 
@@ -343,10 +358,13 @@ func (c *Card) PrevEvo() *Card {
 			// bad data...
 			return nil
 		}
+		lock.Lock()
 		c.prevEvo = tmp
 		tmp.nextEvo = c
+		lock.Unlock()
+		prevEvo = tmp
 	}
-	return c.prevEvo
+	return prevEvo
 }
 
 // FirstEvo Gets the first evolution for the card excluding pre-awakening/amalgamations
@@ -374,24 +392,31 @@ func (c *Card) Archwitches() ArchwitchList {
 	if c == nil {
 		return ArchwitchList{}
 	}
-	if c.archwitches == nil {
-		c.archwitches = make(ArchwitchList, 0)
-		for _, aw := range Data.Archwitches {
-			if c.ID == aw.CardMasterID && !c.archwitches.Contains(aw) {
-				c.archwitches = append(c.archwitches, aw)
+	lock := c.cacheLock()
+	lock.RLock()
+	aws := c.archwitches
+	lock.RUnlock()
+	if aws == nil {
+		aws = make(ArchwitchList, 0)
+		for _, aw := range c.ds.Data.Archwitches {
+			if c.ID == aw.CardMasterID && !aws.Contains(aw) {
+				aws = append(aws, aw)
 			}
 		}
-		sort.Slice(c.archwitches, func(a, b int) bool {
-			awa := c.archwitches[a]
-			awb := c.archwitches[b]
+		sort.Slice(aws, func(a, b int) bool {
+			awa := aws[a]
+			awb := aws[b]
 			if awa.KingSeriesID == awb.KingSeriesID {
 				return awa.ID < awb.ID
 			}
 			return awa.KingSeriesID < awb.KingSeriesID
 		})
+		lock.Lock()
+		c.archwitches = aws
+		lock.Unlock()
 	}
-	log.Printf("Found %d Archwitch records for card %d:%s", len(c.archwitches), c.ID, c.Name)
-	return c.archwitches
+	log.Printf("Found %d Archwitch records for card %d:%s", len(aws), c.ID, c.Name)
+	return aws
 }
 
 // ArchwitchesWithLikeabilityQuotes If this card was used as an AW, get the AW information.
@@ -411,144 +436,85 @@ func (c *Card) ArchwitchesWithLikeabilityQuotes() ArchwitchList {
 
 // EvoAccident If this card can produce an evolution accident, get the result card.
 func (c *Card) EvoAccident() *Card {
-	return CardScan(c.TransCardID)
+	return c.ds.CardScan(c.TransCardID)
 }
 
 // EvoAccidentOf If this card is the result of an evo accident, get the source card.
 func (c *Card) EvoAccidentOf() *Card {
-	for key, val := range Data.Cards {
-		if val.TransCardID == c.ID {
-			return Data.Cards[key]
-		}
-	}
-	return nil
+	return c.ds.lookups().evoAccidentOf[c.ID]
 }
 
 // Amalgamations get any amalgamations for this card (material or result)
 func (c *Card) Amalgamations() []Amalgamation {
-	ret := make([]Amalgamation, 0)
-	for _, a := range Data.Amalgamations {
-		if c.ID == a.FusionCardID ||
-			c.ID == a.Material1 ||
-			c.ID == a.Material2 ||
-			c.ID == a.Material3 ||
-			c.ID == a.Material4 {
-
-			ret = append(ret, a)
-		}
-	}
-	return ret
+	return c.ds.amalgamationsAt(c.ds.lookups().amalgamationsByCard[c.ID])
 }
 
 // AmalgamationsAsMaterial get any amalgamations for this card (material or result)
 func (c *Card) AmalgamationsAsMaterial() []Amalgamation {
-	ret := make([]Amalgamation, 0)
-	for _, a := range Data.Amalgamations {
-		if c.ID == a.Material1 ||
-			c.ID == a.Material2 ||
-			c.ID == a.Material3 ||
-			c.ID == a.Material4 {
-
-			ret = append(ret, a)
-		}
+	return c.ds.amalgamationsAt(c.ds.lookups().amalgamationsByMaterial[c.ID])
+}
+
+// amalgamationsAt copies the amalgamations at the positions in the amalgamation table
+func (d *Dataset) amalgamationsAt(positions []int) []Amalgamation {
+	ret := make([]Amalgamation, 0, len(positions))
+	for _, i := range positions {
+		ret = append(ret, d.Data.Amalgamations[i])
 	}
 	return ret
 }
 
-// AwakensTo Gets the card this card awakens to. Call LastEvo first if
-// you want the awoken card and aren't sure if this is the direct material.
-func (c *Card) AwakensTo() *Card {
+// awakenRecord picks the awakening or rebirth record to use from the records for a card.
+// Closed records are only used if the card is also closed, and then the last one is used.
+func (c *Card) awakenRecord(records []*CardAwaken) *CardAwaken {
 	var mostRecentclosed *CardAwaken
-	for i, val := range Data.Awakenings {
-		if val.IsClosed != 0 {
-			if c.ID == val.BaseCardID {
-				mostRecentclosed = &(Data.Awakenings[i])
-			}
-		} else if c.ID == val.BaseCardID {
-			return CardScan(val.ResultCardID)
+	for _, val := range records {
+		if val.IsClosed == 0 {
+			return val
 		}
+		mostRecentclosed = val
+	}
+	if c.IsClosed != 0 {
+		return mostRecentclosed
 	}
-	// only used the closed awakening/rebirth info if the card is also closed
-	if c.IsClosed != 0 && mostRecentclosed != nil {
-		return CardScan(mostRecentclosed.ResultCardID)
+	return nil
+}
+
+// AwakensTo Gets the card this card awakens to. Call LastEvo first if
+// you want the awoken card and aren't sure if this is the direct material.
+func (c *Card) AwakensTo() *Card {
+	if a := c.awakenRecord(c.ds.lookups().awakeningsByBase[c.ID]); a != nil {
+		return c.ds.CardScan(a.ResultCardID)
 	}
 	return nil
 }
 
 // AwakensFrom gets the source card of this awoken card
 func (c *Card) AwakensFrom() *Card {
-	var mostRecentclosed *CardAwaken
-	for i, val := range Data.Awakenings {
-		if val.IsClosed != 0 {
-			if c.ID == val.ResultCardID {
-				mostRecentclosed = &(Data.Awakenings[i])
-			}
-		} else if c.ID == val.ResultCardID {
-			return CardScan(val.BaseCardID)
-		}
-	}
-	// only used the closed awakening/rebirth info if the card is also closed
-	if c.IsClosed != 0 && mostRecentclosed != nil {
-		return CardScan(mostRecentclosed.BaseCardID)
+	if a := c.awakenRecord(c.ds.lookups().awakeningsByResult[c.ID]); a != nil {
+		return c.ds.CardScan(a.BaseCardID)
 	}
 	return nil
 }
 
 // HasRebirth Gets the card this card rebirths to.
 func (c *Card) HasRebirth() bool {
-	var mostRecentclosed *CardAwaken
-	for i, val := range Data.Rebirths {
-		if val.IsClosed != 0 {
-			if c.ID == val.BaseCardID {
-				mostRecentclosed = &(Data.Rebirths[i])
-			}
-		} else if c.ID == val.BaseCardID {
-			return true
-		}
-	}
-	// only used the closed awakening/rebirth info if the card is also closed
-	if c.IsClosed != 0 && mostRecentclosed != nil {
-		return true
-	}
-	return false
+	return c.awakenRecord(c.ds.lookups().rebirthsByBase[c.ID]) != nil
 }
 
 // RebirthsTo Gets the card this card rebirths to. Call LastEvo().AwakensTo()
 // first if you want the rebith card and aren't sure if this is the direct
 // material.
 func (c *Card) RebirthsTo() *Card {
-	var mostRecentclosed *CardAwaken
-	for i, val := range Data.Rebirths {
-		if val.IsClosed != 0 {
-			if c.ID == val.BaseCardID {
-				mostRecentclosed = &(Data.Rebirths[i])
-			}
-		} else if c.ID == val.BaseCardID {
-			return CardScan(val.ResultCardID)
-		}
-	}
-	// only used the closed awakening/rebirth info if the card is also closed
-	if c.IsClosed != 0 && mostRecentclosed != nil {
-		return CardScan(mostRecentclosed.ResultCardID)
+	if a := c.awakenRecord(c.ds.lookups().rebirthsByBase[c.ID]); a != nil {
+		return c.ds.CardScan(a.ResultCardID)
 	}
 	return nil
 }
 
 // RebirthsFrom gets the source card of this rebirth card
 func (c *Card) RebirthsFrom() *Card {
-	var mostRecentclosed *CardAwaken
-	for i, val := range Data.Rebirths {
-		if val.IsClosed != 0 {
-			if c.ID == val.ResultCardID {
-				mostRecentclosed = &(Data.Rebirths[i])
-			}
-		} else if c.ID == val.ResultCardID {
-			return CardScan(val.BaseCardID)
-		}
-	}
-	// only used the closed awakening/rebirth info if the card is also closed
-	if c.IsClosed != 0 && mostRecentclosed != nil {
-		return CardScan(mostRecentclosed.BaseCardID)
+	if a := c.awakenRecord(c.ds.lookups().rebirthsByResult[c.ID]); a != nil {
+		return c.ds.CardScan(a.BaseCardID)
 	}
 	return nil
 }
@@ -556,26 +522,13 @@ func (c *Card) RebirthsFrom() *Card {
 // HasAmalgamation returns true if this card has an amalgamation
 // (is used as a material)
 func (c *Card) HasAmalgamation() bool {
-	for _, a := range Data.Amalgamations {
-		if c.ID == a.Material1 ||
-			c.ID == a.Material2 ||
-			c.ID == a.Material3 ||
-			c.ID == a.Material4 {
-			return true
-		}
-	}
-	return false
+	return len(c.ds.lookups().amalgamationsByMaterial[c.ID]) > 0
 }
 
 // IsAmalgamation returns true if this card has an amalgamation
 // (is the result of amalgamating other material)
 func (c *Card) IsAmalgamation() bool {
-	for _, a := range Data.Amalgamations {
-		if c.ID == a.FusionCardID {
-			return true
-		}
-	}
-	return false
+	return len(c.ds.lookups().amalgamationsByResult[c.ID]) > 0
 }
 
 // Skill1 of the card
@@ -583,10 +536,17 @@ func (c *Card) Skill1() *Skill {
 	if c == nil {
 		return nil
 	}
-	if c.skill1 == nil && c.SkillID1 > 0 {
-		c.skill1 = SkillScan(c.SkillID1)
+	lock := c.cacheLock()
+	lock.RLock()
+	skill := c.skill1
+	lock.RUnlock()
+	if skill == nil && c.SkillID1 > 0 {
+		skill = c.ds.SkillScan(c.SkillID1)
+		lock.Lock()
+		c.skill1 = skill
+		lock.Unlock()
 	}
-	return c.skill1
+	return skill
 }
 
 // Skill2 of the card
@@ -594,10 +554,17 @@ func (c *Card) Skill2() *Skill {
 	if c == nil {
 		return nil
 	}
-	if c.skill2 == nil && c.SkillID2 > 0 {
-		c.skill2 = SkillScan(c.SkillID2)
+	lock := c.cacheLock()
+	lock.RLock()
+	skill := c.skill2
+	lock.RUnlock()
+	if skill == nil && c.SkillID2 > 0 {
+		skill = c.ds.SkillScan(c.SkillID2)
+		lock.Lock()
+		c.skill2 = skill
+		lock.Unlock()
 	}
-	return c.skill2
+	return skill
 }
 
 // Skill3 of the card
@@ -605,10 +572,17 @@ func (c *Card) Skill3() *Skill {
 	if c == nil {
 		return nil
 	}
-	if c.skill3 == nil && c.SkillID3 > 0 {
-		c.skill3 = SkillScan(c.SkillID3)
+	lock := c.cacheLock()
+	lock.RLock()
+	skill := c.skill3
+	lock.RUnlock()
+	if skill == nil && c.SkillID3 > 0 {
+		skill = c.ds.SkillScan(c.SkillID3)
+		lock.Lock()
+		c.skill3 = skill
+		lock.Unlock()
 	}
-	return c.skill3
+	return skill
 }
 
 // SpecialSkill1 of the card (Awoken Burst)
@@ -616,10 +590,17 @@ func (c *Card) SpecialSkill1() *Skill {
 	if c == nil {
 		return nil
 	}
-	if c.specialSkill1 == nil && c.SpecialSkillID1 > 0 {
-		c.specialSkill1 = SkillScan(c.SpecialSkillID1)
+	lock := c.cacheLock()
+	lock.RLock()
+	skill := c.specialSkill1
+	lock.RUnlock()
+	if skill == nil && c.SpecialSkillID1 > 0 {
+		skill = c.ds.SkillScan(c.SpecialSkillID1)
+		lock.Lock()
+		c.specialSkill1 = skill
+		lock.Unlock()
 	}
-	return c.specialSkill1
+	return skill
 }
 
 // ThorSkill1 of the card
@@ -627,53 +608,58 @@ func (c *Card) ThorSkill1() *Skill {
 	if c == nil {
 		return nil
 	}
-	if c.thorSkill1 == nil && c.ThorSkillID1 > 0 {
-		c.thorSkill1 = SkillScan(c.ThorSkillID1)
+	lock := c.cacheLock()
+	lock.RLock()
+	skill := c.thorSkill1
+	lock.RUnlock()
+	if skill == nil && c.ThorSkillID1 > 0 {
+		skill = c.ds.SkillScan(c.ThorSkillID1)
+		lock.Lock()
+		c.thorSkill1 = skill
+		lock.Unlock()
 	}
-	return c.thorSkill1
+	return skill
 }
 
 // CardScan searches for a card by ID
-func CardScan(id int) *Card {
+func (d *Dataset) CardScan(id int) *Card {
 	if id <= 0 {
 		return nil
 	}
-	l := len(Data.Cards)
-	i := sort.Search(l, func(i int) bool { return Data.Cards[i].ID >= id })
-	if i >= 0 && i < l && Data.Cards[i].ID == id {
-		return Data.Cards[i]
+	l := len(d.Data.Cards)
+	i := sort.Search(l, func(i int) bool { return d.Data.Cards[i].ID >= id })
+	if i >= 0 && i < l && d.Data.Cards[i].ID == id {
+		return d.Data.Cards[i]
 	}
 	log.Printf("No card found with ID: %d", id)
 	//if id > 0 && id <= l {
 	//	log.Printf("trying to return card at index: %d/%d", id, l)
-	//	return Data.Cards[id-1]
+	//	return d.Data.Cards[id-1]
 	//}
 	return nil
 }
 
 // CardScanCharacter searches for a card by the character ID
-func CardScanCharacter(charID int) *Card {
+func (d *Dataset) CardScanCharacter(charID int) *Card {
 	if charID > 0 {
-		for k, val := range Data.Cards {
-			//return the first one we find.
-			if val.CardCharaID == charID {
-				return Data.Cards[k]
-			}
+		//return the first one we find.
+		if cards := d.lookups().characterCards[charID]; len(cards) > 0 {
+			return cards[0]
 		}
 	}
 	return nil
 }
 
 // CardScanImage searches for a card by the card image number
-func CardScanImage(cardID string) *Card {
+func (d *Dataset) CardScanImage(cardID string) *Card {
 	if cardID != "" {
 		i, err := strconv.Atoi(cardID)
 		if err != nil {
 			return nil
 		}
-		for k, val := range Data.Cards {
+		for k, val := range d.Data.Cards {
 			if val.CardNo == i {
-				return Data.Cards[k]
+				return d.Data.Cards[k]
 			}
 		}
 	}
@@ -939,7 +925,7 @@ func getAmalBaseCard(card *Card) *Card {
 		for _, amal := range card.Amalgamations() {
 			if card.ID == amal.FusionCardID {
 				// material 1
-				ac := CardScan(amal.Material1)
+				ac := card.ds.CardScan(amal.Material1)
 				if ac.ID != card.ID && ac.Name == card.Name {
 					if ac.IsAmalgamation() {
 						return getAmalBaseCard(ac)
@@ -947,7 +933,7 @@ func getAmalBaseCard(card *Card) *Card {
 					return ac
 				}
 				// material 2
-				ac = CardScan(amal.Material2)
+				ac = card.ds.CardScan(amal.Material2)
 				if ac.ID != card.ID && ac.Name == card.Name {
 					if ac.IsAmalgamation() {
 						return getAmalBaseCard(ac)
@@ -955,7 +941,7 @@ func getAmalBaseCard(card *Card) *Card {
 					return ac
 				}
 				// material 3
-				ac = CardScan(amal.Material3)
+				ac = card.ds.CardScan(amal.Material3)
 				if ac != nil && ac.ID != card.ID && ac.Name == card.Name {
 					if ac.IsAmalgamation() {
 						return getAmalBaseCard(ac)
@@ -963,7 +949,7 @@ func getAmalBaseCard(card *Card) *Card {
 					return ac
 				}
 				// material 4
-				ac = CardScan(amal.Material4)
+				ac = card.ds.CardScan(amal.Material4)
 				if ac != nil && ac.ID != card.ID && ac.Name == card.Name {
 					if ac.IsAmalgamation() {
 						return getAmalBaseCard(ac)
@@ -987,7 +973,7 @@ func checkEndCards(c *Card) (awakening, amalCard, amalAwakening, rebirth, rebirt
 		amals := c.AmalgamationsAsMaterial()
 		for _, amal := range amals {
 			// get the result card
-			tamalCard := CardScan(amal.FusionCardID)
+			tamalCard := c.ds.CardScan(amal.FusionCardID)
 			if tamalCard != nil && tamalCard.ID != c.ID {
 				log.Printf("Found amalgamation: %d, Name: '%s', Evo: %d\n", tamalCard.ID, tamalCard.Name, tamalCard.EvolutionRank)
 				if tamalCard.Name == c.Name {
@@ -1057,10 +1043,10 @@ func (c *Card) GetImageData(isThumb bool) (imageName string, b []byte, info fs.F
 	if c == nil {
 		return
 	}
-	var sdPath string = filepath.Join(FilePath, "card", "sd")
-	var mdPath string = filepath.Join(FilePath, "card", "md")
-	var hdPath string = filepath.Join(FilePath, "card", "hd")
-	var thumbPath string = filepath.Join(FilePath, "card", "thumb")
+	var sdPath string = filepath.Join(c.ds.FilePath, "card", "sd")
+	var mdPath string = filepath.Join(c.ds.FilePath, "card", "md")
+	var hdPath string = filepath.Join(c.ds.FilePath, "card", "hd")
+	var thumbPath string = filepath.Join(c.ds.FilePath, "card", "thumb")
 
 	var fullpath string
 	if isThumb {
@@ -1096,161 +1082,179 @@ func (c *Card) GetImageData(isThumb bool) (imageName string, b []byte, info fs.F
 
 // GetEvolutions gets the evolutions for a card including Awakening and same character(by name) amalgamations
 func (c *Card) GetEvolutions() map[string]*Card {
-	if c._allEvos == nil {
-		ret := make(map[string]*Card)
-
-		// handle cards like Chimrey and Time Traveler (enemy)
-		if c.CardCharaID < 1 {
-			log.Printf("No character info Card: %d, Name: %s, Evo: %d\n", c.ID, c.Name, c.EvolutionRank)
-			ret["0"] = c
-			c._allEvos = ret
-			return ret
+	evos := c.cachedEvolutions()
+	if evos == nil {
+		evos = c.findEvolutions()
+		lock := c.cacheLock()
+		lock.Lock()
+		for _, card := range evos {
+			card._allEvos = evos
 		}
+		c._allEvos = evos
+		lock.Unlock()
+	}
+	return evos
+}
 
-		c2 := c
-		// check if this is a rebirth card
-		if c2.EvoIsReborn() {
-			log.Printf("Card %d:%s is reborn, finding it's source", c2.ID, c2.Name)
-			tmp := c2.RebirthsFrom()
-			if tmp == nil {
-				ch := c2.Character()
-				if ch != nil && ch.Cards()[0].Name == c2.Name {
-					c2 = ch.Cards()[0]
-					log.Printf("Found card %d:%s", c2.ID, c2.Name)
-				}
-				// the name changed, so we'll keep this card
-			} else {
-				c2 = tmp
+// cachedEvolutions the evolutions already found for the card, or nil if they have not been looked up yet
+func (c *Card) cachedEvolutions() map[string]*Card {
+	lock := c.cacheLock()
+	lock.RLock()
+	defer lock.RUnlock()
+	return c._allEvos
+}
+
+// findEvolutions looks up the evolutions for GetEvolutions
+func (c *Card) findEvolutions() map[string]*Card {
+	ret := make(map[string]*Card)
+
+	// handle cards like Chimrey and Time Traveler (enemy)
+	if c.CardCharaID < 1 {
+		log.Printf("No character info Card: %d, Name: %s, Evo: %d\n", c.ID, c.Name, c.EvolutionRank)
+		ret["0"] = c
+		return ret
+	}
+
+	c2 := c
+	// check if this is a rebirth card
+	if c2.EvoIsReborn() {
+		log.Printf("Card %d:%s is reborn, finding it's source", c2.ID, c2.Name)
+		tmp := c2.RebirthsFrom()
+		if tmp == nil {
+			ch := c2.Character()
+			if ch != nil && ch.Cards()[0].Name == c2.Name {
+				c2 = ch.Cards()[0]
 				log.Printf("Found card %d:%s", c2.ID, c2.Name)
 			}
+			// the name changed, so we'll keep this card
+		} else {
+			c2 = tmp
+			log.Printf("Found card %d:%s", c2.ID, c2.Name)
 		}
-		// check if this is an awoken card
-		if c2.EvoIsAwoken() {
-			log.Printf("Card %d:%s is awoken, finding it's source", c2.ID, c2.Name)
-			tmp := c2.AwakensFrom()
-			if tmp == nil {
-				ch := c2.Character()
-				if ch != nil && ch.Cards()[0].Name == c2.Name {
-					c2 = ch.Cards()[0]
-					log.Printf("Found card %d:%s", c2.ID, c2.Name)
-				}
-				// the name changed, so we'll keep this card
-			} else {
-				c2 = tmp
+	}
+	// check if this is an awoken card
+	if c2.EvoIsAwoken() {
+		log.Printf("Card %d:%s is awoken, finding it's source", c2.ID, c2.Name)
+		tmp := c2.AwakensFrom()
+		if tmp == nil {
+			ch := c2.Character()
+			if ch != nil && ch.Cards()[0].Name == c2.Name {
+				c2 = ch.Cards()[0]
 				log.Printf("Found card %d:%s", c2.ID, c2.Name)
 			}
-		}
-
-		// get earliest evo
-		for tmp := c2.PrevEvo(); tmp != nil; tmp = tmp.PrevEvo() {
+			// the name changed, so we'll keep this card
+		} else {
 			c2 = tmp
-			log.Printf("Looking for earliest Evo for Card: %d, Name: %s, Evo: %d\n", c2.ID, c2.Name, c2.EvolutionRank)
+			log.Printf("Found card %d:%s", c2.ID, c2.Name)
 		}
+	}
 
-		// at this point we should have the first card in the evolution path
-		c2 = getAmalBaseCard(c2)
+	// get earliest evo
+	for tmp := c2.PrevEvo(); tmp != nil; tmp = tmp.PrevEvo() {
+		c2 = tmp
+		log.Printf("Looking for earliest Evo for Card: %d, Name: %s, Evo: %d\n", c2.ID, c2.Name, c2.EvolutionRank)
+	}
 
-		// get earliest evo (again...)
-		for tmp := c2.PrevEvo(); tmp != nil; tmp = tmp.PrevEvo() {
-			c2 = tmp
-			log.Printf("Looking for earliest Evo for Card: %d, Name: %s, Evo: %d\n", c2.ID, c2.Name, c2.EvolutionRank)
-		}
+	// at this point we should have the first card in the evolution path
+	c2 = getAmalBaseCard(c2)
+
+	// get earliest evo (again...)
+	for tmp := c2.PrevEvo(); tmp != nil; tmp = tmp.PrevEvo() {
+		c2 = tmp
+		log.Printf("Looking for earliest Evo for Card: %d, Name: %s, Evo: %d\n", c2.ID, c2.Name, c2.EvolutionRank)
+	}
 
-		log.Printf("Base Card: %d, Name: '%s', Evo: %d\n", c2.ID, c2.Name, c2.EvolutionRank)
+	log.Printf("Base Card: %d, Name: '%s', Evo: %d\n", c2.ID, c2.Name, c2.EvolutionRank)
 
-		// assigns evolutions found
-		assignLastEvos := func(awakening, amalCard, amalAwakening, rebirth, rebirthAmal *Card) {
-			if awakening != nil {
-				ret["G"] = awakening
-			}
-			if amalCard != nil {
-				ret["A"] = amalCard
-			}
-			if amalAwakening != nil {
-				ret["GA"] = amalAwakening
-			}
-			if rebirth != nil {
-				ret["X"] = rebirth
-				if rebirthAmal != nil {
-					ret["XA"] = rebirthAmal
-				}
-			} else if rebirthAmal != nil {
-				ret["X"] = rebirthAmal
+	// assigns evolutions found
+	assignLastEvos := func(awakening, amalCard, amalAwakening, rebirth, rebirthAmal *Card) {
+		if awakening != nil {
+			ret["G"] = awakening
+		}
+		if amalCard != nil {
+			ret["A"] = amalCard
+		}
+		if amalAwakening != nil {
+			ret["GA"] = amalAwakening
+		}
+		if rebirth != nil {
+			ret["X"] = rebirth
+			if rebirthAmal != nil {
+				ret["XA"] = rebirthAmal
 			}
+		} else if rebirthAmal != nil {
+			ret["X"] = rebirthAmal
 		}
+	}
 
-		// populate the actual evos.
-		for nextEvo := c2; nextEvo != nil; nextEvo = nextEvo.NextEvo() {
-			log.Printf("Next Evo is Card: %d, Name: '%s', Evo: %d\n", nextEvo.ID, nextEvo.Name, nextEvo.EvolutionRank)
-			if nextEvo.EvolutionRank <= 0 {
-				// first/only evolution
-				evoRank := "0"
-				if nextEvo.Rarity()[0] == 'H' {
-					evoRank = "H"
-				}
-				ret[evoRank] = nextEvo
-				if nextEvo.LastEvolutionRank < 0 || nextEvo.EvolutionCardID < 0 {
-					// check for awakening
-					awakening, amalCard, amalAwakening, rebirth, rebirthAmal := checkEndCards(nextEvo)
-					assignLastEvos(awakening, amalCard, amalAwakening, rebirth, rebirthAmal)
-				}
-			} else if nextEvo.EvoIsReborn() {
-				// for some reason we hit a X during Evo traversal. Probably a X originating
-				// from amalgamation
-
-				// check for awakening/rebirth
-				_, rebirthAmal, _, _, _ := checkEndCards(nextEvo)
-				assignLastEvos(nil, nil, nil, nextEvo, rebirthAmal)
-			} else if nextEvo.EvoIsAwoken() {
-				// for some reason we hit a G during Evo traversal. Probably a G originating
-				// from amalgamation
-
-				// check for awakening/rebirth
-				_, amalCard, amalAwakening, rebirth, rebirthAmal := checkEndCards(nextEvo)
-				assignLastEvos(nextEvo, amalCard, amalAwakening, rebirth, rebirthAmal)
-			} else if nextEvo.EvolutionRank == c2.LastEvolutionRank || nextEvo.EvoIsHigh() || nextEvo.LastEvolutionRank < 0 {
-				ret["H"] = nextEvo
+	// populate the actual evos.
+	for nextEvo := c2; nextEvo != nil; nextEvo = nextEvo.NextEvo() {
+		log.Printf("Next Evo is Card: %d, Name: '%s', Evo: %d\n", nextEvo.ID, nextEvo.Name, nextEvo.EvolutionRank)
+		if nextEvo.EvolutionRank <= 0 {
+			// first/only evolution
+			evoRank := "0"
+			if nextEvo.Rarity()[0] == 'H' {
+				evoRank = "H"
+			}
+			ret[evoRank] = nextEvo
+			if nextEvo.LastEvolutionRank < 0 || nextEvo.EvolutionCardID < 0 {
 				// check for awakening
 				awakening, amalCard, amalAwakening, rebirth, rebirthAmal := checkEndCards(nextEvo)
 				assignLastEvos(awakening, amalCard, amalAwakening, rebirth, rebirthAmal)
-			} else {
-				// not the last evo. These never awaken or have amalgamations
-				ret[strconv.Itoa(nextEvo.EvolutionRank)] = nextEvo
 			}
+		} else if nextEvo.EvoIsReborn() {
+			// for some reason we hit a X during Evo traversal. Probably a X originating
+			// from amalgamation
+
+			// check for awakening/rebirth
+			_, rebirthAmal, _, _, _ := checkEndCards(nextEvo)
+			assignLastEvos(nil, nil, nil, nextEvo, rebirthAmal)
+		} else if nextEvo.EvoIsAwoken() {
+			// for some reason we hit a G during Evo traversal. Probably a G originating
+			// from amalgamation
+
+			// check for awakening/rebirth
+			_, amalCard, amalAwakening, rebirth, rebirthAmal := checkEndCards(nextEvo)
+			assignLastEvos(nextEvo, amalCard, amalAwakening, rebirth, rebirthAmal)
+		} else if nextEvo.EvolutionRank == c2.LastEvolutionRank || nextEvo.EvoIsHigh() || nextEvo.LastEvolutionRank < 0 {
+			ret["H"] = nextEvo
+			// check for awakening
+			awakening, amalCard, amalAwakening, rebirth, rebirthAmal := checkEndCards(nextEvo)
+			assignLastEvos(awakening, amalCard, amalAwakening, rebirth, rebirthAmal)
+		} else {
+			// not the last evo. These never awaken or have amalgamations
+			ret[strconv.Itoa(nextEvo.EvolutionRank)] = nextEvo
 		}
+	}
 
-		// if we have a GA with no H and no G, just change GA -> G for simplicity
-		if _, ok := ret["GA"]; ok {
-			_, hasH := ret["H"]
-			_, hasG := ret["G"]
-			if !hasH && !hasG {
-				ret["G"] = ret["GA"]
-				delete(ret, "GA")
-			}
+	// if we have a GA with no H and no G, just change GA -> G for simplicity
+	if _, ok := ret["GA"]; ok {
+		_, hasH := ret["H"]
+		_, hasG := ret["G"]
+		if !hasH && !hasG {
+			ret["G"] = ret["GA"]
+			delete(ret, "GA")
 		}
+	}
 
-		// normalize X cards
-		lenEvoKeys := len(ret)
-		if lenEvoKeys == 1 {
-			for k, evo := range ret {
-				r := evo.Rarity()[0]
-				if k != "0" && (evo.EvolutionRank == 1 || evo.EvolutionRank < 0) && r != 'H' && r != 'G' {
-					ret["0"] = evo
-					delete(ret, k)
-				}
+	// normalize X cards
+	lenEvoKeys := len(ret)
+	if lenEvoKeys == 1 {
+		for k, evo := range ret {
+			r := evo.Rarity()[0]
+			if k != "0" && (evo.EvolutionRank == 1 || evo.EvolutionRank < 0) && r != 'H' && r != 'G' {
+				ret["0"] = evo
+				delete(ret, k)
 			}
 		}
+	}
 
-		log.Printf("Found Evos: ")
-		for key, card := range ret {
-			card._allEvos = ret
-			log.Printf("(%s: %d) ", key, card.ID)
-		}
-		log.Printf("\n")
-		c._allEvos = ret
-		return ret
+	log.Printf("Found Evos: ")
+	for key, card := range ret {
+		log.Printf("(%s: %d) ", key, card.ID)
 	}
-	return c._allEvos
+	log.Printf("\n")
+	return ret
 }
 
 // GetEvolutionCards same as GetEvolutions, but only returns the cards
@@ -1270,10 +1274,10 @@ func (c *Card) GetEvolutionCards() CardList {
 }
 
 //CardsByName gets the cards by name. If multiple cards have the same name, they are groupped together
-func CardsByName() map[string]CardList {
+func (d *Dataset) CardsByName() map[string]CardList {
 	ret := make(map[string]CardList)
 
-	for _, card := range Data.Cards {
+	for _, card := range d.Data.Cards {
 		if _, ok := ret[card.Name]; !ok {
 			ret[card.Name] = make(CardList, 0)
 		}
@@ -1298,8 +1302,8 @@ func (d CardList) CardsByName() map[string]CardList {
 }
 
 //CardsByNameByLowestID gets the CardsByName then sorts them by lowest ID first.
-func CardsByNameByLowestID(asc bool) []CardList {
-	byName := CardsByName()
+func (d *Dataset) CardsByNameByLowestID(asc bool) []CardList {
+	byName := d.CardsByName()
 	ret := make([]CardList, 0, len(byName))
 	for _, cl := range byName {
 		ret = append(ret, cl)
@@ -1318,8 +1322,8 @@ func CardsByNameByLowestID(asc bool) []CardList {
 	return ret
 }
 
-func renameSpecialAmalCardsWithDupNames() {
-	cards := Data.Cards.Filter(func(c Card) bool {
+func (d *Dataset) renameSpecialAmalCardsWithDupNames() {
+	cards := d.Data.Cards.Filter(func(c Card) bool {
 		return c.Element() == "Special" && c.HasAmalgamation()
 	}).CardsByName()
 
@@ -1368,8 +1372,8 @@ func (d CardList) Filter(f func(Card) bool) CardList {
 	return ret
 }
 
-func firstCardWithName(name string) *Card {
-	for _, card := range Data.Cards {
+func (d *Dataset) firstCardWithName(name string) *Card {
+	for _, card := range d.Data.Cards {
 		if card.Name == name {
 			return card
 		}
@@ -1384,5 +1388,3 @@ var EvoOrder = [10]string{"0", "1", "2", "3", "H", "A", "G", "GA", "X", "XA"}
 // Elements of the cards.
 var Elements = [5]string{"Light", "Passion", "Cool", "Dark", "Special"}
 
-// Rarity of the cards
-var Rarity = []string{}
@@ -2,6 +2,7 @@ package vc
 
 // CardAwaken list of possible card awakeneings and their cost from master file field "card_awaken"
 type CardAwaken struct {
+	datasetRef
 	// awakening id
 	ID int `json:"_id"`
 	// case card
@@ -37,27 +38,27 @@ func (ca *CardAwaken) Item(i int) *Item {
 		if ca.Material1Item <= 0 {
 			return nil
 		}
-		return ItemScan(ca.Material1Item)
+		return ca.ds.ItemScan(ca.Material1Item)
 	case 2:
 		if ca.Material2Item <= 0 {
 			return nil
 		}
-		return ItemScan(ca.Material2Item)
+		return ca.ds.ItemScan(ca.Material2Item)
 	case 3:
 		if ca.Material3Item <= 0 {
 			return nil
 		}
-		return ItemScan(ca.Material3Item)
+		return ca.ds.ItemScan(ca.Material3Item)
 	case 4:
 		if ca.Material4Item <= 0 {
 			return nil
 		}
-		return ItemScan(ca.Material4Item)
+		return ca.ds.ItemScan(ca.Material4Item)
 	case 5:
 		if ca.Material5Item <= 0 {
 			return nil
 		}
-		return ItemScan(ca.Material5Item)
+		return ca.ds.ItemScan(ca.Material5Item)
 	}
 	return nil
 }
@@ -72,27 +73,27 @@ func (ca *CardAwaken) ItemAndCount(i int) (*Item, int) {
 		if ca.Material1Item <= 0 {
 			return nil, 0
 		}
-		return ItemScan(ca.Material1Item), ca.Material1Count
+		return ca.ds.ItemScan(ca.Material1Item), ca.Material1Count
 	case 2:
 		if ca.Material2Item <= 0 {
 			return nil, 0
 		}
-		return ItemScan(ca.Material2Item), ca.Material2Count
+		return ca.ds.ItemScan(ca.Material2Item), ca.Material2Count
 	case 3:
 		if ca.Material3Item <= 0 {
 			return nil, 0
 		}
-		return ItemScan(ca.Material3Item), ca.Material3Count
+		return ca.ds.ItemScan(ca.Material3Item), ca.Material3Count
 	case 4:
 		if ca.Material4Item <= 0 {
 			return nil, 0
 		}
-		return ItemScan(ca.Material4Item), ca.Material4Count
+		return ca.ds.ItemScan(ca.Material4Item), ca.Material4Count
 	case 5:
 		if ca.Material5Item <= 0 {
 			return nil, 0
 		}
-		return ItemScan(ca.Material5Item), ca.Material5Count
+		return ca.ds.ItemScan(ca.Material5Item), ca.Material5Count
 	}
 	return nil, 0
 }
@@ -108,35 +109,35 @@ type AwakenItem struct {
 func (ca *CardAwaken) ItemCounts() (items []AwakenItem) {
 	items = make([]AwakenItem, 0, 5)
 
-	if mat := ItemScan(ca.Material1Item); mat != nil && ca.Material1Count > 0 {
+	if mat := ca.ds.ItemScan(ca.Material1Item); mat != nil && ca.Material1Count > 0 {
 		items = append(items, AwakenItem{
 			Awakens: ca,
 			Item:    mat,
 			Count:   ca.Material1Count,
 		})
 	}
-	if mat := ItemScan(ca.Material2Item); mat != nil && ca.Material2Count > 0 {
+	if mat := ca.ds.ItemScan(ca.Material2Item); mat != nil && ca.Material2Count > 0 {
 		items = append(items, AwakenItem{
 			Awakens: ca,
 			Item:    mat,
 			Count:   ca.Material2Count,
 		})
 	}
-	if mat := ItemScan(ca.Material3Item); mat != nil && ca.Material3Count > 0 {
+	if mat := ca.ds.ItemScan(ca.Material3Item); mat != nil && ca.Material3Count > 0 {
 		items = append(items, AwakenItem{
 			Awakens: ca,
 			Item:    mat,
 			Count:   ca.Material3Count,
 		})
 	}
-	if mat := ItemScan(ca.Material4Item); mat != nil && ca.Material4Count > 0 {
+	if mat := ca.ds.ItemScan(ca.Material4Item); mat != nil && ca.Material4Count > 0 {
 		items = append(items, AwakenItem{
 			Awakens: ca,
 			Item:    mat,
 			Count:   ca.Material4Count,
 		})
 	}
-	if mat := ItemScan(ca.Material5Item); mat != nil && ca.Material5Count > 0 {
+	if mat := ca.ds.ItemScan(ca.Material5Item); mat != nil && ca.Material5Count > 0 {
 		items = append(items, AwakenItem{
 			Awakens: ca,
 			Item:    mat,
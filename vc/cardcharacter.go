@@ -5,6 +5,7 @@ import "sort"
 // CardCharacter info from master_data field "card_character"
 // These match up with all the MsgChara*_en.strb files
 type CardCharacter struct {
+	datasetRef
 	// card  charcter ID, matches to Card -> card_chara_id
 	ID int `json:"_id"`
 	// hidden param 1
@@ -26,23 +27,27 @@ type CardCharacter struct {
 	FriendshipEvent string `json:"-"` // MsgCharaBonds_en.strb
 	Rebirth         string `json:"-"` // MsgCharaSuperAwaken_en.strb
 	_cards          CardList
+
+	// Translations text from the string files in each loaded language pack
+	Translations Translations `json:"-"`
 }
 
 // Cards that are under this character
 func (c *CardCharacter) Cards() CardList {
-	if c._cards == nil || len(c._cards) == 0 {
-		c._cards = make(CardList, 0)
-		for _, val := range Data.Cards {
-			//return the first one we find.
-			if val.CardCharaID == c.ID {
-				c._cards = append(c._cards, val)
-			}
-		}
-		sort.Slice(c._cards, func(a, b int) bool {
-			return c._cards[a].EvolutionRank < c._cards[b].EvolutionRank
+	lock := c.cacheLock()
+	lock.RLock()
+	cards := c._cards
+	lock.RUnlock()
+	if len(cards) == 0 {
+		cards = append(make(CardList, 0), c.ds.lookups().characterCards[c.ID]...)
+		sort.Slice(cards, func(a, b int) bool {
+			return cards[a].EvolutionRank < cards[b].EvolutionRank
 		})
+		lock.Lock()
+		c._cards = cards
+		lock.Unlock()
 	}
-	return c._cards
+	return cards
 }
 
 func (c *CardCharacter) HasQuotes() bool {
@@ -54,23 +59,18 @@ func (c *CardCharacter) HasQuotes() bool {
 // FirstEvoCard first evolution of the cards under this character
 func (c *CardCharacter) FirstEvoCard() (card *Card) {
 	card = nil
-	for i, cd := range c.Cards() {
+	for _, cd := range c.Cards() {
 		if card == nil || cd.EvolutionRank <= card.EvolutionRank {
-			card = c._cards[i]
+			card = cd
 		}
 	}
 	return
 }
 
 // CardCharacterScan searches for a character by id
-func CardCharacterScan(charID int) *CardCharacter {
+func (d *Dataset) CardCharacterScan(charID int) *CardCharacter {
 	if charID > 0 {
-		for k, val := range Data.CardCharacters {
-			//return the first one we find.
-			if val.ID == charID {
-				return &(Data.CardCharacters[k])
-			}
-		}
+		return d.lookups().characters[charID]
 	}
 	return nil
 }
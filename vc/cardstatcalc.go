@@ -106,7 +106,7 @@ func baseStats(c *Card) Stats {
 // false positives for cards that can only be obtained through
 // amalgamation at evo[0] and there is no drop/RR
 func (c *Card) PossibleMixedEvo() bool {
-	if len(c._allEvos) == 1 && c.IsAmalgamation() {
+	if len(c.cachedEvolutions()) == 1 && c.IsAmalgamation() {
 		// if this is an amalgamation only card, we want to find the previous
 		// cards and see if any of those is a "possible mix evos"
 		for _, amal := range c.Amalgamations() {
@@ -0,0 +1,162 @@
+package vc
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CostRollup the total cost of getting a card from scratch: the cards that have to be collected,
+// the awakening and rebirth items, and the resources to level the cards up on the way
+type CostRollup struct {
+	CardID int    `json:"cardId"`
+	Name   string `json:"name"`
+	// Plan the progression the totals are for, with the stats of each step
+	Plan  *EvoPlanNode `json:"plan"`
+	Cards []RollupCard `json:"cards"`
+	Items []RollupItem `json:"items"`
+	Steps []RollupStep `json:"steps"`
+	// LevelExp the EXP needed to level the cards that are used at max level
+	LevelExp  int       `json:"levelExp"`
+	LevelCost Resources `json:"levelCost"`
+}
+
+// RollupCard a card that has to be collected
+type RollupCard struct {
+	CardID int    `json:"cardId"`
+	Name   string `json:"name"`
+	Count  int    `json:"count"`
+}
+
+// RollupItem an item used by the awakenings and rebirths
+type RollupItem struct {
+	ItemID int    `json:"itemId"`
+	Name   string `json:"name"`
+	// Count the items used if every awakening succeeds
+	Count int `json:"count"`
+	// ExpectedCount the items used on average, counting the failed awakenings
+	ExpectedCount float64 `json:"expectedCount"`
+}
+
+// RollupStep an evolution, amalgamation, awakening or rebirth in the progression
+type RollupStep struct {
+	CardID int    `json:"cardId"`
+	Name   string `json:"name"`
+	Method string `json:"method"`
+	// Count how many times the step is done
+	Count int `json:"count"`
+	// Percent the chance an awakening or rebirth succeeds
+	Percent int `json:"percent,omitempty"`
+	// ExpectedAttempts the number of tries needed on average
+	ExpectedAttempts float64 `json:"expectedAttempts"`
+}
+
+// ProgressionPlan the standard plan to get the card from scratch. Unlike DefaultEvoPlan,
+// amalgamations are made from their materials instead of being owned
+func (c *Card) ProgressionPlan() *EvoPlanNode {
+	return c.defaultEvoPlan(true, true, make(map[int]bool))
+}
+
+// CostRollup adds up the cost of the card's standard progression from scratch
+func (c *Card) CostRollup() (*CostRollup, error) {
+	return c.ds.RollupPlan(c.ProgressionPlan())
+}
+
+// RollupPlan adds up the cost of an evolution plan
+func (d *Dataset) RollupPlan(plan *EvoPlanNode) (*CostRollup, error) {
+	if err := d.PlanEvolution(plan); err != nil {
+		return nil, err
+	}
+	r := &CostRollup{
+		CardID: plan.CardID,
+		Name:   plan.Name,
+		Plan:   plan,
+		Cards:  make([]RollupCard, 0),
+		Items:  make([]RollupItem, 0),
+		Steps:  make([]RollupStep, 0),
+	}
+	cards := make(map[int]*RollupCard)
+	items := make(map[int]*RollupItem)
+	steps := make(map[string]*RollupStep)
+	for _, n := range plan.Nodes() {
+		if n.MaxLevel && n.Stats == nil {
+			r.LevelExp += n.Card.LevelExp(n.Card.MaxLevel())
+			r.LevelCost = r.LevelCost.Add(n.Card.LevelCost(1, n.Card.MaxLevel()))
+		}
+		if n.Method == EvoPlanOwned {
+			rc, ok := cards[n.CardID]
+			if !ok {
+				rc = &RollupCard{CardID: n.CardID, Name: n.Name}
+				cards[n.CardID] = rc
+			}
+			rc.Count++
+			continue
+		}
+
+		key := fmt.Sprintf("%d %s", n.CardID, n.Method)
+		step, ok := steps[key]
+		if !ok {
+			step = &RollupStep{CardID: n.CardID, Name: n.Name, Method: n.Method}
+			steps[key] = step
+		}
+		step.Count++
+
+		awaken := n.awakenRecord()
+		if awaken == nil {
+			step.ExpectedAttempts += 1
+			continue
+		}
+		step.Percent = awaken.Percent
+		attempts := 1.0
+		if awaken.Percent > 0 && awaken.Percent < 100 {
+			attempts = 100 / float64(awaken.Percent)
+		}
+		step.ExpectedAttempts += attempts
+		for _, ic := range awaken.ItemCounts() {
+			ri, ok := items[ic.Item.ID]
+			if !ok {
+				ri = &RollupItem{ItemID: ic.Item.ID, Name: ic.Item.NameEng}
+				items[ic.Item.ID] = ri
+			}
+			ri.Count += ic.Count
+			ri.ExpectedCount += float64(ic.Count) * attempts
+		}
+	}
+
+	for _, rc := range cards {
+		r.Cards = append(r.Cards, *rc)
+	}
+	sort.Slice(r.Cards, func(i, j int) bool { return r.Cards[i].CardID < r.Cards[j].CardID })
+	for _, ri := range items {
+		r.Items = append(r.Items, *ri)
+	}
+	sort.Slice(r.Items, func(i, j int) bool { return r.Items[i].ItemID < r.Items[j].ItemID })
+	// steps in the order they are first done
+	for _, n := range plan.Nodes() {
+		key := fmt.Sprintf("%d %s", n.CardID, n.Method)
+		if step, ok := steps[key]; ok {
+			r.Steps = append(r.Steps, *step)
+			delete(steps, key)
+		}
+	}
+	return r, nil
+}
+
+// awakenRecord the awakening or rebirth record of a calculated awakening or rebirth step
+func (n *EvoPlanNode) awakenRecord() *CardAwaken {
+	var records []*CardAwaken
+	switch n.Method {
+	case EvoPlanAwakening:
+		records = n.Card.ds.lookups().awakeningsByResult[n.CardID]
+	case EvoPlanRebirth:
+		records = n.Card.ds.lookups().rebirthsByResult[n.CardID]
+	default:
+		return nil
+	}
+	matching := make([]*CardAwaken, 0, len(records))
+	for _, a := range records {
+		if a.BaseCardID == n.Materials[0].CardID {
+			matching = append(matching, a)
+		}
+	}
+	return n.Card.awakenRecord(matching)
+}
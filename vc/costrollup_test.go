@@ -0,0 +1,26 @@
+package vc
+
+import "testing"
+
+func TestCostRollup(t *testing.T) {
+	d := testEvoPlanDataset()
+	d.Data.Cards = append(d.Data.Cards, &Card{ID: 6, CardCharaID: 12, EvolutionCardID: -1})
+	d.Data.Items = []Item{{ID: 7, NameEng: "Crystal"}}
+	d.Data.Awakenings = append(d.Data.Awakenings, CardAwaken{ID: 2, BaseCardID: 5, ResultCardID: 6, Percent: 50, Material1Item: 7, Material1Count: 3})
+	d.link()
+
+	rollup, err := d.CardScan(6).CostRollup()
+	if err != nil {
+		t.Fatalf("CostRollup returned an error: %s", err.Error())
+	}
+	// card 6 awakens from the amalgamation of card 1 and card 4
+	if len(rollup.Cards) != 2 || rollup.Cards[0].CardID != 1 || rollup.Cards[1].CardID != 4 {
+		t.Errorf("Unexpected cards to collect: %v", rollup.Cards)
+	}
+	if len(rollup.Items) != 1 || rollup.Items[0].Count != 3 || rollup.Items[0].ExpectedCount != 6 {
+		t.Errorf("Unexpected items: %v", rollup.Items)
+	}
+	if len(rollup.Steps) != 2 || rollup.Steps[0].Method != EvoPlanAmalgamation || rollup.Steps[1].Method != EvoPlanAwakening || rollup.Steps[1].ExpectedAttempts != 2 {
+		t.Errorf("Unexpected steps: %v", rollup.Steps)
+	}
+}
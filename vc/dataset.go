@@ -0,0 +1,135 @@
+package vc
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Dataset a single copy of the VC game data. It owns the location it was read from,
+// the language pack used for its strings and the master data itself.
+// Records loaded into a dataset keep a reference back to it so their
+// methods can look up related records.
+type Dataset struct {
+	// FilePath path to the main VC data folder
+	FilePath string
+	// LangPack language pack to use
+	LangPack string
+	// Languages additional language packs to load translations from
+	Languages []string
+	// Data Main data file
+	Data *VFile
+	// MasterDataStr master data as read from the file as a string.
+	MasterDataStr string
+	// Rarity card rarity names in order of their ID
+	Rarity []string
+	// Overrides fixes to the card names, read with the master data
+	Overrides *NameOverrides
+
+	// cacheLock guards the values the records lazily cache off of the data
+	cacheLock sync.RWMutex
+	// index lookups between the records, see lookups
+	index *datasetIndex
+}
+
+// datasetRef links a record back to the dataset it was loaded from
+type datasetRef struct {
+	ds *Dataset
+}
+
+// Dataset the dataset this record was loaded from
+func (r *datasetRef) Dataset() *Dataset {
+	return r.ds
+}
+
+func (r *datasetRef) setDataset(ds *Dataset) {
+	r.ds = ds
+}
+
+// orphanCacheLock guards the cached values of records that are not part of a dataset
+var orphanCacheLock sync.RWMutex
+
+// cacheLock the lock guarding the values the record lazily caches.
+// Values should be built without holding the lock and only stored under it,
+// since building them usually looks at the cached values of other records.
+func (r *datasetRef) cacheLock() *sync.RWMutex {
+	if r.ds == nil {
+		return &orphanCacheLock
+	}
+	return &r.ds.cacheLock
+}
+
+// datasetLinker is implemented by all records that reference their dataset
+type datasetLinker interface {
+	setDataset(ds *Dataset)
+}
+
+// NewDataset creates an empty dataset for the data location and language pack.
+// Translations are also loaded for any additional languages. Call Read to load the data.
+func NewDataset(filePath, langPack string, languages ...string) *Dataset {
+	if langPack == "" {
+		langPack = "en"
+	}
+	return &Dataset{
+		FilePath:  filePath,
+		LangPack:  langPack,
+		Languages: languages,
+		Data:      &VFile{},
+		Rarity:    []string{},
+	}
+}
+
+// LoadDataset creates a dataset and reads the master data from the file location
+func LoadDataset(filePath, langPack string, languages ...string) (*Dataset, error) {
+	ds := NewDataset(filePath, langPack, languages...)
+	if err := ds.ReadMasterData(); err != nil {
+		return nil, err
+	}
+	return ds, nil
+}
+
+// ReadMasterData Reads the master data from the dataset file location
+func (d *Dataset) ReadMasterData() error {
+	b, err := d.Read()
+	if err != nil {
+		os.Stderr.WriteString(err.Error() + "\n")
+		return err
+	}
+	d.MasterDataStr = string(b)
+	return nil
+}
+
+// link sets the dataset on every record in the data file
+func (d *Dataset) link() {
+	v := reflect.ValueOf(d.Data).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if f.Kind() != reflect.Slice {
+			continue
+		}
+		for j := 0; j < f.Len(); j++ {
+			e := f.Index(j)
+			if e.Kind() != reflect.Ptr {
+				e = e.Addr()
+			}
+			if e.IsNil() {
+				continue
+			}
+			if l, ok := e.Interface().(datasetLinker); ok {
+				l.setDataset(d)
+			}
+		}
+	}
+}
+
+// ReadStringFile Reads a binary string file for the dataset language pack filtering common issues out.
+// The file name should be given with the "_en.strb" suffix.
+func (d *Dataset) ReadStringFile(fname string) ([]string, error) {
+	return d.ReadStringFileFilter(fname, true)
+}
+
+// ReadStringFileFilter Reads a binary string file for the dataset language pack with the strings optionally filtered
+func (d *Dataset) ReadStringFileFilter(fname string, filtered bool) ([]string, error) {
+	return ReadStringFileFilter(strings.Replace(fname, "_en.strb", "_"+d.LangPack+".strb", 1), filtered)
+}
@@ -0,0 +1,247 @@
+package vc
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MaxDeckSize the most cards a deck can hold
+const MaxDeckSize = 5
+
+// Deck a set of cards checked for its cost and the deck bonuses it triggers
+type Deck struct {
+	CardIDs []int `json:"cardIds"`
+
+	// filled in by EvaluateDeck
+	Cards    []*Card `json:"-"`
+	DeckCost int     `json:"deckCost"`
+	// Bonuses the deck bonuses the cards trigger
+	Bonuses []DeckBonusResult `json:"bonuses"`
+	// NearMisses the deck bonuses that one more card would trigger
+	NearMisses []DeckBonusResult `json:"nearMisses"`
+	// AtkBonus and DefBonus the total of the values of the triggered bonuses
+	AtkBonus int `json:"atkBonus"`
+	DefBonus int `json:"defBonus"`
+}
+
+// DeckBonusResult how well a deck meets a deck bonus
+type DeckBonusResult struct {
+	BonusID     int    `json:"bonusId"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	AtkDefFlg   int    `json:"atkDefFlg"`
+	Value       int    `json:"value"`
+	ReqNum      int    `json:"reqNum"`
+	// Matched how many cards count towards the bonus
+	Matched int `json:"matched"`
+	// CardIDs the cards that count towards the bonus
+	CardIDs []int `json:"cardIds"`
+	// Missing the conditions a card could meet to count towards the bonus
+	Missing []string `json:"missing,omitempty"`
+}
+
+// Triggered if enough cards count towards the bonus
+func (r *DeckBonusResult) Triggered() bool {
+	return r.Matched >= r.ReqNum
+}
+
+// AffectsAtk if the bonus raises ATK. atk_def_flg 1 is ATK, 2 is DEF and 3 is both
+func (r *DeckBonusResult) AffectsAtk() bool {
+	return r.AtkDefFlg == 1 || r.AtkDefFlg == 3
+}
+
+// AffectsDef if the bonus raises DEF
+func (r *DeckBonusResult) AffectsDef() bool {
+	return r.AtkDefFlg == 2 || r.AtkDefFlg == 3
+}
+
+// EvaluateDeck calculates the cost of the deck, the deck bonuses it triggers and the ones that
+// one more card would trigger
+func (d *Dataset) EvaluateDeck(deck *Deck) error {
+	if len(deck.CardIDs) == 0 {
+		return fmt.Errorf("the deck has no cards")
+	}
+	if len(deck.CardIDs) > MaxDeckSize {
+		return fmt.Errorf("the deck has %d cards, the most it can hold is %d", len(deck.CardIDs), MaxDeckSize)
+	}
+	deck.Cards = make([]*Card, 0, len(deck.CardIDs))
+	deck.DeckCost = 0
+	for _, id := range deck.CardIDs {
+		c := d.CardScan(id)
+		if c == nil {
+			return fmt.Errorf("card %d not found", id)
+		}
+		deck.Cards = append(deck.Cards, c)
+		deck.DeckCost += c.DeckCost
+	}
+
+	deck.Bonuses = make([]DeckBonusResult, 0)
+	deck.NearMisses = make([]DeckBonusResult, 0)
+	deck.AtkBonus = 0
+	deck.DefBonus = 0
+	for i := range d.Data.DeckBonuses {
+		b := &d.Data.DeckBonuses[i]
+		r, ok := b.evaluate(deck.Cards)
+		if !ok {
+			continue
+		}
+		if r.Triggered() {
+			deck.Bonuses = append(deck.Bonuses, r)
+			if r.AffectsAtk() {
+				deck.AtkBonus += r.Value
+			}
+			if r.AffectsDef() {
+				deck.DefBonus += r.Value
+			}
+		} else if r.Matched == r.ReqNum-1 && len(deck.Cards) < MaxDeckSize && len(r.Missing) > 0 {
+			deck.NearMisses = append(deck.NearMisses, r)
+		}
+	}
+	return nil
+}
+
+// evaluate counts the cards that meet the bonus conditions. Conditions in the same group are
+// alternatives. A bonus with one group needs req_num cards that meet it, and a bonus with several
+// groups needs req_num of the groups met, each by a different card. Unless dup_flg is set, cards
+// of the same character only count once. ok is false if the bonus has no conditions.
+func (b *DeckBonus) evaluate(cards []*Card) (r DeckBonusResult, ok bool) {
+	conds := b.ds.lookups().deckBonusConditions[b.ID]
+	if len(conds) == 0 {
+		return r, false
+	}
+	r = DeckBonusResult{
+		BonusID:     b.ID,
+		Name:        b.Name,
+		Description: b.Description,
+		AtkDefFlg:   b.AtkDefFlg,
+		Value:       b.Value,
+		ReqNum:      b.ReqNum,
+		CardIDs:     make([]int, 0),
+	}
+	if r.ReqNum < 1 {
+		r.ReqNum = 1
+	}
+
+	names := make(map[int]string, len(conds))
+	for _, c := range b.Conditions() {
+		names[c.ID] = c.RefName
+	}
+	groups := make(map[int][]*DeckBonusCond)
+	groupIDs := make([]int, 0)
+	for _, c := range conds {
+		if _, seen := groups[c.Group]; !seen {
+			groupIDs = append(groupIDs, c.Group)
+		}
+		groups[c.Group] = append(groups[c.Group], c)
+	}
+	sort.Ints(groupIDs)
+
+	candidates := cards
+	if b.DupFlg == 0 {
+		candidates = make([]*Card, 0, len(cards))
+		characters := make(map[int]bool)
+		for _, c := range cards {
+			if !characters[c.CardCharaID] {
+				characters[c.CardCharaID] = true
+				candidates = append(candidates, c)
+			}
+		}
+	}
+
+	meets := func(group int, c *Card) bool {
+		for _, cond := range groups[group] {
+			if b.condMet(cond, c) {
+				return true
+			}
+		}
+		return false
+	}
+	// without duplicates, another card of a character already in the deck does not count, so
+	// those characters are not suggested
+	inDeck := func(cond *DeckBonusCond) bool {
+		if b.DupFlg != 0 || b.CondType != 2 {
+			return false
+		}
+		for _, c := range cards {
+			if b.condMet(cond, c) {
+				return true
+			}
+		}
+		return false
+	}
+	listed := make(map[string]bool)
+	missing := func(group int) {
+		for _, cond := range groups[group] {
+			if inDeck(cond) {
+				continue
+			}
+			if name, ok := names[cond.ID]; ok && !listed[name] {
+				listed[name] = true
+				r.Missing = append(r.Missing, name)
+			}
+		}
+	}
+
+	if len(groupIDs) == 1 {
+		for _, c := range candidates {
+			if meets(groupIDs[0], c) {
+				r.CardIDs = append(r.CardIDs, c.ID)
+			}
+		}
+		r.Matched = len(r.CardIDs)
+		if r.Matched < r.ReqNum {
+			missing(groupIDs[0])
+		}
+		return r, true
+	}
+
+	// match each group to a different card, trying to free up a card already taken by
+	// another group when there is no free card left
+	owner := make(map[int]int, len(candidates))
+	var assign func(group int, visited map[int]bool) bool
+	assign = func(group int, visited map[int]bool) bool {
+		for i, c := range candidates {
+			if visited[i] || !meets(group, c) {
+				continue
+			}
+			visited[i] = true
+			if other, taken := owner[i]; !taken || assign(other, visited) {
+				owner[i] = group
+				return true
+			}
+		}
+		return false
+	}
+	met := make(map[int]bool, len(groupIDs))
+	for _, g := range groupIDs {
+		assign(g, make(map[int]bool))
+	}
+	for i, c := range candidates {
+		if g, taken := owner[i]; taken {
+			met[g] = true
+			r.CardIDs = append(r.CardIDs, c.ID)
+		}
+	}
+	r.Matched = len(r.CardIDs)
+	if r.Matched < r.ReqNum {
+		for _, g := range groupIDs {
+			if !met[g] {
+				missing(g)
+			}
+		}
+	}
+	return r, true
+}
+
+// condMet if the card meets the condition
+func (b *DeckBonus) condMet(cond *DeckBonusCond, c *Card) bool {
+	switch b.CondType {
+	case 2:
+		return c.CardCharaID == cond.RefID
+	case 3:
+		return c.CardTypeID == cond.RefID
+	case 8:
+		return c.CardRareID == cond.RefID
+	}
+	return false
+}
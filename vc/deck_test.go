@@ -0,0 +1,74 @@
+package vc
+
+import "testing"
+
+func TestEvaluateDeck(t *testing.T) {
+	d := NewDataset("", "en")
+	d.Data.CardCharacters = []CardCharacter{{ID: 10}, {ID: 11}, {ID: 12}}
+	d.Data.Cards = CardList{
+		{ID: 1, Name: "A", CardCharaID: 10, CardTypeID: 1, CardRareID: 5, DeckCost: 10},
+		{ID: 2, Name: "A", CardCharaID: 10, CardTypeID: 1, CardRareID: 6, DeckCost: 12},
+		{ID: 3, Name: "B", CardCharaID: 11, CardTypeID: 2, CardRareID: 6, DeckCost: 15},
+		{ID: 4, Name: "C", CardCharaID: 12, CardTypeID: 1, CardRareID: 6, DeckCost: 20},
+	}
+	d.Data.DeckBonuses = []DeckBonus{
+		// 2 light cards, duplicates allowed
+		{ID: 1, AtkDefFlg: 1, Value: 5, CondType: 3, ReqNum: 2, DupFlg: 1},
+		// 2 light cards of different characters
+		{ID: 2, AtkDefFlg: 2, Value: 7, CondType: 3, ReqNum: 2},
+		// characters A and B together
+		{ID: 3, AtkDefFlg: 3, Value: 10, CondType: 2, ReqNum: 2},
+		// characters A, B and C together
+		{ID: 4, AtkDefFlg: 1, Value: 20, CondType: 2, ReqNum: 3},
+		// any 3 of characters A, B and C
+		{ID: 5, AtkDefFlg: 2, Value: 15, CondType: 2, ReqNum: 3},
+	}
+	d.Data.DeckBonusConditions = []DeckBonusCond{
+		{ID: 1, DeckBonusID: 1, Group: 1, CondTypeID: 3, RefID: 1},
+		{ID: 2, DeckBonusID: 2, Group: 1, CondTypeID: 3, RefID: 1},
+		{ID: 3, DeckBonusID: 3, Group: 1, CondTypeID: 2, RefID: 10},
+		{ID: 4, DeckBonusID: 3, Group: 2, CondTypeID: 2, RefID: 11},
+		{ID: 5, DeckBonusID: 4, Group: 1, CondTypeID: 2, RefID: 10},
+		{ID: 6, DeckBonusID: 4, Group: 2, CondTypeID: 2, RefID: 11},
+		{ID: 7, DeckBonusID: 4, Group: 3, CondTypeID: 2, RefID: 12},
+		{ID: 8, DeckBonusID: 5, Group: 1, CondTypeID: 2, RefID: 10},
+		{ID: 9, DeckBonusID: 5, Group: 1, CondTypeID: 2, RefID: 11},
+		{ID: 10, DeckBonusID: 5, Group: 1, CondTypeID: 2, RefID: 12},
+	}
+	d.link()
+
+	deck := &Deck{CardIDs: []int{1, 2, 3}}
+	if err := d.EvaluateDeck(deck); err != nil {
+		t.Fatalf("EvaluateDeck returned an error: %s", err.Error())
+	}
+	if deck.DeckCost != 37 {
+		t.Errorf("Expected a deck cost of 37, got %d", deck.DeckCost)
+	}
+	triggered := make(map[int]bool)
+	for _, b := range deck.Bonuses {
+		triggered[b.BonusID] = true
+	}
+	if !triggered[1] || triggered[2] || !triggered[3] || triggered[4] {
+		t.Errorf("Unexpected bonuses: %v", deck.Bonuses)
+	}
+	if deck.AtkBonus != 15 || deck.DefBonus != 10 {
+		t.Errorf("Expected ATK +15 and DEF +10, got ATK +%d and DEF +%d", deck.AtkBonus, deck.DefBonus)
+	}
+	// the second light card and character C are each one card away
+	if len(deck.NearMisses) != 3 || deck.NearMisses[0].BonusID != 2 || deck.NearMisses[1].BonusID != 4 || deck.NearMisses[2].BonusID != 5 {
+		t.Fatalf("Unexpected near misses: %v", deck.NearMisses)
+	}
+	if m := deck.NearMisses[0].Missing; len(m) != 1 || m[0] != "Light" {
+		t.Errorf("Expected a light card to be missing, got %v", m)
+	}
+	// characters A and B are in the deck so another copy of them would not count
+	for _, nm := range deck.NearMisses[1:] {
+		if m := nm.Missing; len(m) != 1 || m[0] != "C" {
+			t.Errorf("Expected character C to be missing for bonus %d, got %v", nm.BonusID, m)
+		}
+	}
+
+	if err := d.EvaluateDeck(&Deck{CardIDs: []int{1, 2, 3, 4, 1, 2}}); err == nil {
+		t.Error("Expected an error for a deck over the max size")
+	}
+}
@@ -10,6 +10,7 @@ import (
 // these match with the strings in MsgDeckBonusName_en.strb
 // and MsgDeckBonusDesc_en.strb
 type DeckBonus struct {
+	datasetRef
 	ID          int    `json:"_id"`         // bonus id
 	AtkDefFlg   int    `json:"atk_def_flg"` // Affects ATK or DEF
 	ValueType   int    `json:"value_type"`  // ?
@@ -20,46 +21,48 @@ type DeckBonus struct {
 	DupFlg      int    `json:"dup_flg"`     // allows duplicates
 	Name        string `json:"name"`
 	Description string `json:"description"`
+
+	// Translations text from the string files in each loaded language pack
+	Translations Translations `json:"-"`
 }
 
 // Conditions that trigger the bonus
 func (d *DeckBonus) Conditions() DeckBonusCondArray {
 	ret := make([]DeckBonusCond, 0)
-	for _, val := range Data.DeckBonusConditions {
-		if val.DeckBonusID == d.ID {
-			switch d.CondType {
+	for _, cond := range d.ds.lookups().deckBonusConditions[d.ID] {
+		val := *cond
+		switch d.CondType {
+		case 2:
+			c := d.ds.CardScanCharacter(val.RefID)
+			if c == nil || c.Name == "" {
+				continue
+			} else {
+				val.RefName = c.Name
+			}
+		case 3:
+			switch val.RefID {
+			case 1:
+				val.RefName = "Light"
 			case 2:
-				c := CardScanCharacter(val.RefID)
-				if c == nil || c.Name == "" {
-					continue
-				} else {
-					val.RefName = c.Name
-				}
+				val.RefName = "Passion"
 			case 3:
-				switch val.RefID {
-				case 1:
-					val.RefName = "Light"
-				case 2:
-					val.RefName = "Passion"
-				case 3:
-					val.RefName = "Cool"
-				case 4:
-					val.RefName = "Dark"
-				default:
-					val.RefName = fmt.Sprintf("Unknown Element (%d)", val.RefID)
-				}
-			case 8:
-				r := CardRarityScan(val.RefID)
-				if r == nil {
-					val.RefName = fmt.Sprintf("Unknown Rarity (%d)", val.RefID)
-				} else {
-					val.RefName = strings.ToUpper(r.Signature)
-				}
+				val.RefName = "Cool"
+			case 4:
+				val.RefName = "Dark"
 			default:
-				val.RefName = fmt.Sprintf("Unknown Type (%d)", val.CondTypeID)
+				val.RefName = fmt.Sprintf("Unknown Element (%d)", val.RefID)
+			}
+		case 8:
+			r := d.ds.CardRarityScan(val.RefID)
+			if r == nil {
+				val.RefName = fmt.Sprintf("Unknown Rarity (%d)", val.RefID)
+			} else {
+				val.RefName = strings.ToUpper(r.Signature)
 			}
-			ret = append(ret, val)
+		default:
+			val.RefName = fmt.Sprintf("Unknown Type (%d)", val.CondTypeID)
 		}
+		ret = append(ret, val)
 	}
 	return ret
 }
@@ -2,11 +2,13 @@ package vc
 
 import (
 	"bytes"
+	"crypto/rand"
 	"encoding/binary"
 	"errors"
 	"io"
 	"io/ioutil"
 	"os"
+	"time"
 )
 
 // Original Author: Kellindil Maendellyn
@@ -28,12 +30,19 @@ func Decode(file string) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	if len(data) < 16 {
+		return nil, errors.New("File '" + file + "' is too short to be encoded")
+	}
 	if !bytes.Equal(data[0:4], []byte("CODE")) {
 		return nil, errors.New("File '" + file + "' is not encoded")
 	}
+	return decodeBytes(data), nil
+}
 
+// decodeBytes decodes the data of an encoded file, including the header
+func decodeBytes(data []byte) []byte {
 	subMe := toInt32(data, 12)
-	xorMe := int32(0x45AF6E5D)
+	xorMe := codeXorKey
 
 	// We'll ignore the 16-bytes signature
 	excessBytes := len(data) % 4
@@ -52,7 +61,91 @@ func Decode(file string) ([]byte, error) {
 		result = append(result, data[(16+encodedLength):]...)
 	}
 
-	return result, nil
+	return result
+}
+
+// codeXorKey the encoding key that is built into the app
+const codeXorKey = int32(0x45AF6E5D)
+
+// CodeHeader the part of the 16 byte header of an encoded file that can change between files
+type CodeHeader struct {
+	// Unknown the 8 bytes after the signature
+	Unknown [8]byte
+	// Key the encoding key that is subtracted from each 4 bytes
+	Key int32
+}
+
+// ReadCodeHeader reads the header of an encoded file so a changed copy can be encoded the same way
+func ReadCodeHeader(file string) (h CodeHeader, err error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	data := make([]byte, 16)
+	if _, err = io.ReadFull(f, data); err != nil {
+		return h, errors.New("File '" + file + "' is too short to be encoded")
+	}
+	if !bytes.Equal(data[0:4], []byte("CODE")) {
+		return h, errors.New("File '" + file + "' is not encoded")
+	}
+	copy(h.Unknown[:], data[4:12])
+	h.Key = toInt32(data, 12)
+	return
+}
+
+// NewCodeHeader a header with a new random key, for a file that does not replace an encoded one
+func NewCodeHeader() (h CodeHeader) {
+	key := make([]byte, 4)
+	for h.Key == 0 {
+		if _, err := rand.Read(key); err != nil {
+			// any key other than 0 will do
+			binary.LittleEndian.PutUint32(key, uint32(time.Now().UnixNano()))
+		}
+		h.Key = toInt32(key, 0)
+	}
+	return
+}
+
+// Encode encodes the data with the header, the reverse of Decode. The result starts with the 16 byte header.
+func Encode(data []byte, h CodeHeader) []byte {
+	excessBytes := len(data) % 4
+	encodedLength := len(data) - excessBytes
+	result := make([]byte, 16, 16+len(data))
+	copy(result, "CODE")
+	copy(result[4:12], h.Unknown[:])
+	binary.LittleEndian.PutUint32(result[12:], uint32(h.Key))
+
+	for i := 0; i < encodedLength/4; i++ {
+		encodedBytes := (toInt32(data, i*4) + h.Key) ^ codeXorKey
+
+		buf := bytes.NewBuffer(make([]byte, 0, 4))
+		binary.Write(buf, binary.LittleEndian, encodedBytes)
+		result = append(result, buf.Bytes()[:]...)
+	}
+
+	if excessBytes > 0 {
+		result = append(result, data[encodedLength:]...)
+	}
+
+	return result
+}
+
+// EncodeAndSave encodes the file and saves it as newFile. The header is taken from the
+// original encoded file if one is given, so the game reads the new file the same way.
+// Otherwise a new key is used.
+func EncodeAndSave(file, original, newFile string) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	h := NewCodeHeader()
+	if original != "" {
+		if h, err = ReadCodeHeader(original); err != nil {
+			return err
+		}
+	}
+	return ioutil.WriteFile(newFile, Encode(data, h), 0644)
 }
 
 // DecodeAndSave Decodes the file and saves the result in the same location as the coded file.
@@ -68,13 +161,7 @@ func DecodeAndSave(file string) (string, []byte, error) {
 		fileName = file + ".png"
 	} else {
 		fileName = file + ".json"
-
-		// remove trailing 0's from the end of the file.
-		dataLen := len(data)
-		for data[dataLen-1] == 0 {
-			dataLen--
-		}
-		data = data[:dataLen]
+		data = trimTrailingZeros(data)
 	}
 
 	err = ioutil.WriteFile(fileName, data, os.FileMode(0655))
@@ -105,6 +192,15 @@ func IsFileEncoded(path string) (bool, error) {
 	return bytes.Equal(b, []byte("CODE")), nil
 }
 
+// remove trailing 0's from the end of the decoded data.
+func trimTrailingZeros(data []byte) []byte {
+	dataLen := len(data)
+	for dataLen > 0 && data[dataLen-1] == 0 {
+		dataLen--
+	}
+	return data[:dataLen]
+}
+
 // reads a single 32bit int from the data starting at sliceStart position.
 func toInt32(data []byte, sliceStart int) (ret int32) {
 	buf := bytes.NewBuffer(data[sliceStart:(sliceStart + 4)])
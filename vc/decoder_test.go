@@ -0,0 +1,97 @@
+package vc
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestEncodeRoundTrip(t *testing.T) {
+	headers := []CodeHeader{
+		{},
+		{Key: 0x12345678},
+		{Unknown: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}, Key: -42},
+	}
+	inputs := [][]byte{
+		{},
+		[]byte("a"),
+		[]byte("abcd"),
+		[]byte(`{"mst_card":[{"_id":1}]}`),
+		{0x89, 'P', 'N', 'G', 0, 0, 0, 0, 0xff, 0xfe, 0x7f},
+	}
+	for _, h := range headers {
+		for _, x := range inputs {
+			encoded := Encode(x, h)
+			if len(encoded) != 16+len(x) {
+				t.Errorf("Encoded length %d, expected %d", len(encoded), 16+len(x))
+			}
+			if !bytes.Equal(encoded[:4], []byte("CODE")) {
+				t.Errorf("Missing CODE signature: %v", encoded[:4])
+			}
+			if got := decodeBytes(encoded); !bytes.Equal(got, x) {
+				t.Errorf("Decode(Encode(%v)) with key %d = %v", x, h.Key, got)
+			}
+		}
+	}
+}
+
+func TestEncodeAndSaveKeepsHeader(t *testing.T) {
+	dir := t.TempDir()
+	original := filepath.Join(dir, "master_all")
+	h := CodeHeader{Unknown: [8]byte{9, 8, 7, 6, 5, 4, 3, 2}, Key: 0x0badf00d}
+	if err := ioutil.WriteFile(original, Encode([]byte(`{"old":true}`), h), 0644); err != nil {
+		t.Fatal(err)
+	}
+	changed := filepath.Join(dir, "master_all.json")
+	if err := ioutil.WriteFile(changed, []byte(`{"new":true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	newFile := filepath.Join(dir, "master_all.new")
+	if err := EncodeAndSave(changed, original, newFile); err != nil {
+		t.Fatalf("EncodeAndSave returned an error: %s", err.Error())
+	}
+	got, err := ReadCodeHeader(newFile)
+	if err != nil {
+		t.Fatalf("ReadCodeHeader returned an error: %s", err.Error())
+	}
+	if got != h {
+		t.Errorf("The header was not kept: %v, expected %v", got, h)
+	}
+	data, err := Decode(newFile)
+	if err != nil {
+		t.Fatalf("Decode returned an error: %s", err.Error())
+	}
+	if string(data) != `{"new":true}` {
+		t.Errorf("Unexpected decoded data: %s", data)
+	}
+}
+
+func TestEncodeAndSaveNewKey(t *testing.T) {
+	dir := t.TempDir()
+	changed := filepath.Join(dir, "master_all.json")
+	if err := ioutil.WriteFile(changed, []byte(`{"new":true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// without an original file a new key is used instead of a zero header
+	newFile := filepath.Join(dir, "master_all.new")
+	if err := EncodeAndSave(changed, "", newFile); err != nil {
+		t.Fatalf("EncodeAndSave returned an error: %s", err.Error())
+	}
+	h, err := ReadCodeHeader(newFile)
+	if err != nil {
+		t.Fatalf("ReadCodeHeader returned an error: %s", err.Error())
+	}
+	if h.Key == 0 {
+		t.Errorf("The file was encoded without a key")
+	}
+	data, err := Decode(newFile)
+	if err != nil {
+		t.Fatalf("Decode returned an error: %s", err.Error())
+	}
+	if string(data) != `{"new":true}` {
+		t.Errorf("Unexpected decoded data: %s", data)
+	}
+}
@@ -23,7 +23,7 @@ type DungeonAreaType struct {
 func (t *Dungeon) RankRewards() []RankRewardSheet {
 	set := make([]RankRewardSheet, 0)
 	if t.RankingRewardGroupID > 0 {
-		for _, val := range Data.DungeonRewards {
+		for _, val := range t.ds.Data.DungeonRewards {
 			if val.GroupID == t.RankingRewardGroupID {
 				set = append(set, val)
 			}
@@ -39,7 +39,7 @@ func (t *Dungeon) ArrivalRewards() []RankRewardSheet {
 		return set
 	}
 	if t.ArrivalRewardGroupID > 0 {
-		for _, val := range Data.DungeonArrivalRewards {
+		for _, val := range t.ds.Data.DungeonArrivalRewards {
 			if val.GroupID == t.ArrivalRewardGroupID {
 				set = append(set, val)
 			}
@@ -49,12 +49,12 @@ func (t *Dungeon) ArrivalRewards() []RankRewardSheet {
 }
 
 // DungeonScan search for a Dungeon by ID
-func DungeonScan(id int) *Dungeon {
+func (d *Dataset) DungeonScan(id int) *Dungeon {
 	if id > 0 {
-		l := len(Data.Dungeons)
-		i := sort.Search(l, func(i int) bool { return Data.Dungeons[i].ID >= id })
-		if i >= 0 && i < l && Data.Dungeons[i].ID == id {
-			return &(Data.Dungeons[i])
+		l := len(d.Data.Dungeons)
+		i := sort.Search(l, func(i int) bool { return d.Data.Dungeons[i].ID >= id })
+		if i >= 0 && i < l && d.Data.Dungeons[i].ID == id {
+			return &(d.Data.Dungeons[i])
 		}
 	}
 	return nil
@@ -70,7 +70,7 @@ func (d *Dungeon) EventName() string {
 	if d == nil {
 		return ""
 	}
-	for _, evt := range Data.Events {
+	for _, evt := range d.ds.Data.Events {
 		if evt.DungeonEventID == d.ID {
 			return evt.Name
 		}
@@ -7,6 +7,7 @@ import (
 
 // Event information
 type Event struct {
+	datasetRef
 	ID                    int             `json:"_id"`
 	EventTypeID           int             `json:"event_type_id"`
 	BannerID              int             `json:"banner_id"`
@@ -57,6 +58,9 @@ type Event struct {
 	Description           string          `json:"description"` // MsgEventDesc_en.strb
 	_map                  *Map
 	_archwitches          ArchwitchList
+
+	// Translations text from the string files in each loaded language pack
+	Translations Translations `json:"-"`
 }
 
 // EventBook event book
@@ -76,6 +80,7 @@ type EventCard struct {
 
 // RankReward rank rewards for an event
 type RankReward struct {
+	datasetRef
 	ID                       int       `json:"_id"`
 	KingListID               int       `json:"king_list_id"` // same as King Series
 	SheetID                  int       `json:"sheet_id"`     // maps to the reward sheet below
@@ -108,10 +113,17 @@ type RankRewardSheet struct {
 
 // Map for an event if one exists (usually just AW events)
 func (e *Event) Map() *Map {
-	if e._map == nil && e.MapID > 0 {
-		e._map = MapScan(e.MapID, Data.Maps)
+	lock := e.cacheLock()
+	lock.RLock()
+	m := e._map
+	lock.RUnlock()
+	if m == nil && e.MapID > 0 {
+		m = MapScan(e.MapID, e.ds.Data.Maps)
+		lock.Lock()
+		e._map = m
+		lock.Unlock()
 	}
-	return e._map
+	return m
 }
 
 //SubEvent get the sub-event info
@@ -149,7 +161,7 @@ func (e *Event) Tower() *Tower {
 		return nil
 	}
 
-	return TowerScan(e.TowerEventID)
+	return e.ds.TowerScan(e.TowerEventID)
 }
 
 // DemonRealm information for the event if it's a Demon Realm Voyage event
@@ -158,7 +170,7 @@ func (e *Event) DemonRealm() *Dungeon {
 		return nil
 	}
 
-	return DungeonScan(e.DungeonEventID)
+	return e.ds.DungeonScan(e.DungeonEventID)
 }
 
 // Weapon information for the event if it's a Weapon event
@@ -167,7 +179,7 @@ func (e *Event) Weapon() *WeaponEvent {
 		return nil
 	}
 
-	return WeaponEventScan(e.WeaponEventID)
+	return e.ds.WeaponEventScan(e.WeaponEventID)
 }
 
 // GuildBattle information if it's an Alliance Battle
@@ -176,14 +188,14 @@ func (e *Event) GuildBattle() *GuildBattle {
 		return nil
 	}
 
-	return GuildBattleScan(e.GuildBattleID)
+	return e.ds.GuildBattleScan(e.GuildBattleID)
 }
 
 // Thor information for Thor events
 func (e *Event) Thor() *ThorEvent {
-	for k, te := range Data.ThorEvents {
+	for k, te := range e.ds.Data.ThorEvents {
 		if te.PublicStartDatetime == e.StartDatetime && te.PublicEndDatetime == e.EndDatetime {
-			return &(Data.ThorEvents[k])
+			return &(e.ds.Data.ThorEvents[k])
 		}
 	}
 	return nil
@@ -191,34 +203,38 @@ func (e *Event) Thor() *ThorEvent {
 
 // Archwitches for this event.
 func (e *Event) Archwitches() ArchwitchList {
-	if e.KingSeriesID > 0 {
-		if e._archwitches == nil {
-
+	lock := e.cacheLock()
+	lock.RLock()
+	aws := e._archwitches
+	lock.RUnlock()
+	if aws == nil {
+		aws = make(ArchwitchList, 0)
+		if e.KingSeriesID > 0 {
 			// picks only unique Cards for the event
 			set := make(map[int]*Archwitch)
-			for _, a := range Data.Archwitches {
+			for _, a := range e.ds.Data.Archwitches {
 				if e.KingSeriesID == a.KingSeriesID {
 					set[a.CardMasterID] = a
 				}
 			}
 
-			e._archwitches = make(ArchwitchList, 0)
 			for _, a := range set {
-				e._archwitches = append(e._archwitches, a)
+				aws = append(aws, a)
 			}
 		}
-	} else if e._archwitches == nil {
-		e._archwitches = make(ArchwitchList, 0)
+		lock.Lock()
+		e._archwitches = aws
+		lock.Unlock()
 	}
-	return e._archwitches
+	return aws
 }
 
 // RankRewards for this event
 func (e *Event) RankRewards() *RankReward {
 	if e.KingSeriesID > 0 {
-		for k, val := range Data.RankRewards {
+		for k, val := range e.ds.Data.RankRewards {
 			if val.KingListID == e.KingSeriesID {
-				return &(Data.RankRewards[k])
+				return &(e.ds.Data.RankRewards[k])
 			}
 		}
 	}
@@ -229,7 +245,7 @@ func (e *Event) RankRewards() *RankReward {
 func (r *RankReward) MidRewards() []RankRewardSheet {
 	set := make([]RankRewardSheet, 0)
 	if r.MidSheetID > 0 {
-		for _, val := range Data.RankRewardSheets {
+		for _, val := range r.ds.Data.RankRewardSheets {
 			if val.SheetID == r.MidSheetID {
 				set = append(set, val)
 			}
@@ -242,7 +258,7 @@ func (r *RankReward) MidRewards() []RankRewardSheet {
 func (r *RankReward) FinalRewards() []RankRewardSheet {
 	set := make([]RankRewardSheet, 0)
 	if r.SheetID > 0 {
-		for _, val := range Data.RankRewardSheets {
+		for _, val := range r.ds.Data.RankRewardSheets {
 			if val.SheetID == r.SheetID {
 				set = append(set, val)
 			}
@@ -263,12 +279,12 @@ func MaxEventID(events []Event) (max int) {
 }
 
 // EventScan searches for an event by ID
-func EventScan(id int) *Event {
+func (d *Dataset) EventScan(id int) *Event {
 	if id > 0 {
-		l := len(Data.Events)
-		i := sort.Search(l, func(i int) bool { return Data.Events[i].ID >= id })
-		if i >= 0 && i < l && Data.Events[i].ID == id {
-			return &(Data.Events[i])
+		l := len(d.Data.Events)
+		i := sort.Search(l, func(i int) bool { return d.Data.Events[i].ID >= id })
+		if i >= 0 && i < l && d.Data.Events[i].ID == id {
+			return &(d.Data.Events[i])
 		}
 	}
 	return nil
@@ -0,0 +1,209 @@
+package vc
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Ways a card in an evolution plan is obtained
+const (
+	EvoPlanOwned        = "owned"
+	EvoPlanEvolution    = "evolution"
+	EvoPlanAmalgamation = "amalgamation"
+	EvoPlanAwakening    = "awakening"
+	EvoPlanRebirth      = "rebirth"
+)
+
+// EvoPlanNode a card in an evolution plan. A node without materials is a card that is already
+// owned. A node with materials is made from them by evolution (2 materials of the same
+// character), amalgamation or awakening and rebirth (1 material).
+type EvoPlanNode struct {
+	CardID int `json:"cardId"`
+	// Stats the current stats of an owned card. If not given the level 1 or max level stats of the card are used
+	Stats *Stats `json:"stats,omitempty"`
+	// MaxLevel the card is, or will be levelled to, its max level before it is used
+	MaxLevel  bool           `json:"maxLevel,omitempty"`
+	Materials []*EvoPlanNode `json:"materials,omitempty"`
+
+	// filled in by PlanEvolution
+	Card   *Card  `json:"-"`
+	Name   string `json:"name,omitempty"`
+	Method string `json:"method,omitempty"`
+	Result Stats  `json:"result"`
+}
+
+// Nodes the node and all its materials, depth first with the materials before the card they make
+func (n *EvoPlanNode) Nodes() []*EvoPlanNode {
+	ret := make([]*EvoPlanNode, 0)
+	for _, m := range n.Materials {
+		ret = append(ret, m.Nodes()...)
+	}
+	return append(ret, n)
+}
+
+// PlanEvolution calculates the stats of each card in the plan, starting from the owned materials,
+// using the same rules as the fixed evolution calculations
+func (d *Dataset) PlanEvolution(n *EvoPlanNode) error {
+	if n == nil {
+		return fmt.Errorf("missing plan")
+	}
+	c := d.CardScan(n.CardID)
+	if c == nil {
+		return fmt.Errorf("card %d not found", n.CardID)
+	}
+	n.Card = c
+	n.Name = c.Name
+	for _, m := range n.Materials {
+		if err := d.PlanEvolution(m); err != nil {
+			return err
+		}
+	}
+
+	switch len(n.Materials) {
+	case 0:
+		n.Method = EvoPlanOwned
+		if n.Stats != nil {
+			n.Result = *n.Stats
+		} else if n.MaxLevel {
+			n.Result = maxStats(c)
+		} else {
+			n.Result = baseStats(c)
+		}
+		return nil
+	case 1:
+		mat := n.Materials[0]
+		if r := c.RebirthsFrom(); r != nil && r.ID == mat.CardID {
+			n.Method = EvoPlanRebirth
+		} else if a := c.AwakensFrom(); a != nil && a.ID == mat.CardID {
+			n.Method = EvoPlanAwakening
+		} else {
+			return fmt.Errorf("%s (%d) does not awaken or rebirth from %s (%d)", c.Name, c.ID, mat.Card.Name, mat.CardID)
+		}
+		// only the stats the material gained above its own level are kept
+		gain := mat.Result.Subtract(planLevelStats(mat))
+		n.Result = c.calculateAwakeningStat(gain, !n.MaxLevel)
+		return nil
+	}
+
+	if amal := c.amalgamationOf(n.Materials); amal != nil {
+		n.Method = EvoPlanAmalgamation
+		stats := planLevelStats(n)
+		for _, m := range n.Materials {
+			stats = applyAmal(m.Result, stats, m.MaxLevel)
+		}
+		stats.ensureMaxCap(c.CardRarity())
+		n.Result = stats
+		return nil
+	}
+
+	if len(n.Materials) != 2 {
+		return fmt.Errorf("%s (%d) is not an amalgamation of the %d materials", c.Name, c.ID, len(n.Materials))
+	}
+	for _, m := range n.Materials {
+		if m.Card.CardCharaID != c.CardCharaID || m.Card.EvolutionRank >= c.EvolutionRank {
+			return fmt.Errorf("%s (%d) can not be evolved from %s (%d)", c.Name, c.ID, m.Card.Name, m.CardID)
+		}
+	}
+	n.Method = EvoPlanEvolution
+	resultCard := c
+	if firstEvo := c.GetEvolutions()["0"]; firstEvo != nil && c.MainRarity() != "LR" && c.LastEvolutionRank == 4 {
+		// 4* cards do not use the result card stats
+		resultCard = firstEvo
+	}
+	resultStats := baseStats(resultCard)
+	if n.MaxLevel {
+		resultStats = maxStats(resultCard)
+	}
+	n.Result = c.calculateEvoStats(n.Materials[0].Result, n.Materials[1].Result, resultStats)
+	return nil
+}
+
+// planLevelStats the stats of the node's card at the level it is used at
+func planLevelStats(n *EvoPlanNode) Stats {
+	if n.MaxLevel {
+		return maxStats(n.Card)
+	}
+	return baseStats(n.Card)
+}
+
+// amalgamationOf the amalgamation that makes this card from exactly the given materials
+func (c *Card) amalgamationOf(materials []*EvoPlanNode) *Amalgamation {
+	ids := make([]int, 0, len(materials))
+	for _, m := range materials {
+		ids = append(ids, m.CardID)
+	}
+	sort.Ints(ids)
+	for _, a := range c.Amalgamations() {
+		if a.FusionCardID != c.ID {
+			continue
+		}
+		mats := make([]int, 0, 4)
+		for _, m := range []int{a.Material1, a.Material2, a.Material3, a.Material4} {
+			if m > 0 {
+				mats = append(mats, m)
+			}
+		}
+		if len(mats) != len(ids) {
+			continue
+		}
+		sort.Ints(mats)
+		match := true
+		for i := range mats {
+			if mats[i] != ids[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			amal := a
+			return &amal
+		}
+	}
+	return nil
+}
+
+// DefaultEvoPlan a plan that makes the card the standard way: each evolution uses the previous
+// evolution and a max level copy of the first evolution, awakenings use a level 1 material, and
+// cards without a previous evolution are owned at max level. It is a starting point to be changed
+// to the materials that are really owned.
+func (c *Card) DefaultEvoPlan() *EvoPlanNode {
+	return c.defaultEvoPlan(true, false, nil)
+}
+
+// defaultEvoPlan builds the standard plan. When fromScratch is set every material is made from
+// the cards it comes from, amalgamations included, instead of being owned. path holds the
+// amalgamations being expanded so a loop in the data can not recurse forever.
+func (c *Card) defaultEvoPlan(maxLevel, fromScratch bool, path map[int]bool) *EvoPlanNode {
+	n := &EvoPlanNode{CardID: c.ID, MaxLevel: maxLevel}
+	if prev := c.PrevEvo(); prev != nil {
+		if firstEvo := c.GetEvolutions()["0"]; firstEvo != nil {
+			second := &EvoPlanNode{CardID: firstEvo.ID, MaxLevel: true}
+			if fromScratch {
+				second = firstEvo.defaultEvoPlan(true, fromScratch, path)
+			}
+			n.Materials = []*EvoPlanNode{
+				prev.defaultEvoPlan(true, fromScratch, path),
+				second,
+			}
+		}
+	} else if c.IsAmalgamation() {
+		if fromScratch && !path[c.ID] {
+			for _, a := range c.Amalgamations() {
+				if a.FusionCardID != c.ID {
+					continue
+				}
+				path[c.ID] = true
+				for _, mat := range a.MaterialsOnly() {
+					n.Materials = append(n.Materials, mat.defaultEvoPlan(true, fromScratch, path))
+				}
+				delete(path, c.ID)
+				break
+			}
+		}
+	} else if mat := c.RebirthsFrom(); mat != nil {
+		n.Materials = []*EvoPlanNode{mat.defaultEvoPlan(false, fromScratch, path)}
+	} else if mat := c.AwakensFrom(); mat != nil {
+		n.Materials = []*EvoPlanNode{mat.defaultEvoPlan(false, fromScratch, path)}
+	}
+	return n
+}
@@ -0,0 +1,69 @@
+package vc
+
+import "testing"
+
+func testEvoPlanDataset() *Dataset {
+	d := NewDataset("", "en")
+	d.Data.Cards = CardList{
+		{ID: 1, CardCharaID: 10, EvolutionRank: 0, LastEvolutionRank: 1, EvolutionCardID: 2,
+			DefaultOffense: 1000, MaxOffense: 2000, DefaultDefense: 900, MaxDefense: 1800, DefaultFollower: 1000, MaxFollower: 2000},
+		{ID: 2, CardCharaID: 10, EvolutionRank: 1, LastEvolutionRank: 1, EvolutionCardID: -1,
+			DefaultOffense: 1500, MaxOffense: 3000, DefaultDefense: 1400, MaxDefense: 2800, DefaultFollower: 1500, MaxFollower: 3000},
+		{ID: 3, CardCharaID: 10, EvolutionCardID: -1,
+			DefaultOffense: 2500, MaxOffense: 4000, DefaultDefense: 2400, MaxDefense: 3800, DefaultFollower: 2500, MaxFollower: 4000},
+		{ID: 4, CardCharaID: 11, EvolutionCardID: -1,
+			DefaultOffense: 500, MaxOffense: 1000, DefaultDefense: 500, MaxDefense: 1000, DefaultFollower: 500, MaxFollower: 1000},
+		{ID: 5, CardCharaID: 12, EvolutionCardID: -1,
+			DefaultOffense: 3000, MaxOffense: 5000, DefaultDefense: 3000, MaxDefense: 5000, DefaultFollower: 3000, MaxFollower: 5000},
+	}
+	d.Data.CardCharacters = []CardCharacter{{ID: 10}, {ID: 11}, {ID: 12}}
+	d.Data.Awakenings = []CardAwaken{{ID: 1, BaseCardID: 2, ResultCardID: 3}}
+	d.Data.Amalgamations = []Amalgamation{{ID: 1, Material1: 1, Material2: 4, FusionCardID: 5}}
+	d.link()
+	return d
+}
+
+func TestDefaultEvoPlan(t *testing.T) {
+	d := testEvoPlanDataset()
+	for _, id := range []int{1, 2, 3} {
+		c := d.CardScan(id)
+		plan := c.DefaultEvoPlan()
+		if err := d.PlanEvolution(plan); err != nil {
+			t.Fatalf("PlanEvolution(%d) returned an error: %s", id, err.Error())
+		}
+		if want := c.EvoStandard(); plan.Result.NotEquals(want) {
+			t.Errorf("The standard plan for card %d gives %s, expected %s", id, plan.Result, want)
+		}
+	}
+}
+
+func TestPlanEvolution(t *testing.T) {
+	d := testEvoPlanDataset()
+	plan := &EvoPlanNode{CardID: 5, Materials: []*EvoPlanNode{
+		{CardID: 4, MaxLevel: true},
+		{CardID: 1, Stats: &Stats{Attack: 1500, Defense: 1000, Soldiers: 1200}},
+	}}
+	if err := d.PlanEvolution(plan); err != nil {
+		t.Fatalf("PlanEvolution returned an error: %s", err.Error())
+	}
+	if plan.Method != EvoPlanAmalgamation {
+		t.Errorf("Expected an amalgamation, got %s", plan.Method)
+	}
+	// level 1 result with 8% of the max level material and 3% of the other
+	want := Stats{Attack: 3000 + 80 + 45, Defense: 3000 + 80 + 30, Soldiers: 3000 + 80 + 36}
+	if plan.Result.NotEquals(want) {
+		t.Errorf("Expected %s, got %s", want, plan.Result)
+	}
+	if nodes := plan.Nodes(); len(nodes) != 3 || nodes[2] != plan {
+		t.Errorf("Unexpected plan nodes: %v", nodes)
+	}
+
+	bad := &EvoPlanNode{CardID: 2, Materials: []*EvoPlanNode{{CardID: 1}, {CardID: 4}}}
+	if err := d.PlanEvolution(bad); err == nil {
+		t.Errorf("A card of another character should not be an evolution material")
+	}
+	bad = &EvoPlanNode{CardID: 3, Materials: []*EvoPlanNode{{CardID: 1}}}
+	if err := d.PlanEvolution(bad); err == nil {
+		t.Errorf("Card 3 does not awaken from card 1")
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 // GuildBattle "mst_guildbattle_schedule"
 type GuildBattle struct {
+	datasetRef
 	ID                 int       `json:"_id"`
 	GuildBattleType    int       `json:"guild_battle_type"`
 	GuildBingoID       int       `json:"guild_bingo_id"`
@@ -26,6 +27,7 @@ type GuildBattle struct {
 
 // GuildBingoBattle "mst_guildbingo"
 type GuildBingoBattle struct {
+	datasetRef
 	ID                            int       `json:"_id"`
 	ExchangeItemID                int       `json:"exchange_item_id"`
 	ExchangeItemRemovalDate       Timestamp `json:"exchange_item_removal_date"`
@@ -112,10 +114,10 @@ func (g *GuildBattle) BingoBattle() *GuildBingoBattle {
 		return nil
 	}
 	if g.GuildBingoID > 0 {
-		l := len(Data.GuildBingoBattles)
-		i := sort.Search(l, func(i int) bool { return Data.GuildBingoBattles[i].ID >= g.GuildBingoID })
-		if i >= 0 && i < l && Data.GuildBingoBattles[i].ID == g.GuildBingoID {
-			return &(Data.GuildBingoBattles[i])
+		l := len(g.ds.Data.GuildBingoBattles)
+		i := sort.Search(l, func(i int) bool { return g.ds.Data.GuildBingoBattles[i].ID >= g.GuildBingoID })
+		if i >= 0 && i < l && g.ds.Data.GuildBingoBattles[i].ID == g.GuildBingoID {
+			return &(g.ds.Data.GuildBingoBattles[i])
 		}
 	}
 	return nil
@@ -128,7 +130,7 @@ func (g *GuildBingoBattle) ExchangeRewards() []GuildBingoExchangeReward {
 	}
 	set := make([]GuildBingoExchangeReward, 0)
 	if g.ExchangeRewardGroupID > 0 {
-		for _, val := range Data.GuildBingoExchangeRewards {
+		for _, val := range g.ds.Data.GuildBingoExchangeRewards {
 			if val.GroupID == g.ExchangeRewardGroupID {
 				set = append(set, val)
 			}
@@ -143,15 +145,22 @@ func (g *GuildBingoBattle) Campaigns() []GuildBingoPointCampaign {
 	if g == nil {
 		return []GuildBingoPointCampaign{}
 	}
-	if g._campaigns == nil {
-		g._campaigns = make([]GuildBingoPointCampaign, 0)
-		for _, a := range Data.GuildBingoPointCampaigns {
+	lock := g.cacheLock()
+	lock.RLock()
+	campaigns := g._campaigns
+	lock.RUnlock()
+	if campaigns == nil {
+		campaigns = make([]GuildBingoPointCampaign, 0)
+		for _, a := range g.ds.Data.GuildBingoPointCampaigns {
 			if g.CampaignID == a.CampaignID {
-				g._campaigns = append(g._campaigns, a)
+				campaigns = append(campaigns, a)
 			}
 		}
+		lock.Lock()
+		g._campaigns = campaigns
+		lock.Unlock()
 	}
-	return g._campaigns
+	return campaigns
 }
 
 // IndividualRewards individual rewards for this event
@@ -159,16 +168,23 @@ func (g *GuildBattle) IndividualRewards() []RankRewardSheet {
 	if g == nil {
 		return []RankRewardSheet{}
 	}
-	if g.individualRewards == nil {
-		g.individualRewards = make([]RankRewardSheet, 0)
+	lock := g.cacheLock()
+	lock.RLock()
+	individualRewards := g.individualRewards
+	lock.RUnlock()
+	if individualRewards == nil {
+		individualRewards = make([]RankRewardSheet, 0)
 		rewards := g.rewards()
-		for _, ipr := range Data.GuildBattleIndividualPoints {
+		for _, ipr := range g.ds.Data.GuildBattleIndividualPoints {
 			if rewards.SheetID == ipr.SheetID {
-				g.individualRewards = append(g.individualRewards, ipr)
+				individualRewards = append(individualRewards, ipr)
 			}
 		}
+		lock.Lock()
+		g.individualRewards = individualRewards
+		lock.Unlock()
 	}
-	return g.individualRewards
+	return individualRewards
 }
 
 // RankRewards for this event
@@ -176,23 +192,30 @@ func (g *GuildBattle) RankRewards() []RankRewardSheet {
 	if g == nil {
 		return []RankRewardSheet{}
 	}
-	if g.rankRewards == nil {
-		g.rankRewards = make([]RankRewardSheet, 0)
+	lock := g.cacheLock()
+	lock.RLock()
+	rankRewards := g.rankRewards
+	lock.RUnlock()
+	if rankRewards == nil {
+		rankRewards = make([]RankRewardSheet, 0)
 		rewards := g.rewards()
-		for _, rr := range Data.GuildBattleRankingRewards {
+		for _, rr := range g.ds.Data.GuildBattleRankingRewards {
 			if rewards.IndividualRankingSheetID == rr.SheetID {
-				g.rankRewards = append(g.rankRewards, rr)
+				rankRewards = append(rankRewards, rr)
 			}
 		}
+		lock.Lock()
+		g.rankRewards = rankRewards
+		lock.Unlock()
 	}
-	return g.rankRewards
+	return rankRewards
 }
 
 func (g *GuildBattle) rewards() *GuildBattleRewardRef {
 	if g == nil {
 		return nil
 	}
-	for _, rref := range Data.GuildBattleRewardRefs {
+	for _, rref := range g.ds.Data.GuildBattleRewardRefs {
 		if g.ID == rref.EventID {
 			return &rref
 		}
@@ -201,14 +224,14 @@ func (g *GuildBattle) rewards() *GuildBattleRewardRef {
 }
 
 // GuildBattleScan searches for a guild battle by ID
-func GuildBattleScan(id int) *GuildBattle {
+func (d *Dataset) GuildBattleScan(id int) *GuildBattle {
 	if id <= 0 {
 		return nil
 	}
-	l := len(Data.GuildBattles)
-	i := sort.Search(l, func(i int) bool { return Data.GuildBattles[i].ID >= id })
-	if i >= 0 && i < l && Data.GuildBattles[i].ID == id {
-		return &(Data.GuildBattles[i])
+	l := len(d.Data.GuildBattles)
+	i := sort.Search(l, func(i int) bool { return d.Data.GuildBattles[i].ID >= id })
+	if i >= 0 && i < l && d.Data.GuildBattles[i].ID == id {
+		return &(d.Data.GuildBattles[i])
 	}
 	return nil
 }
@@ -0,0 +1,122 @@
+package vc
+
+import "vc_file_grouper/util"
+
+// datasetIndex lookups from an ID to the records that reference it, so the record methods do not
+// have to look through whole tables. The index holds pointers into the data, in the data order,
+// so it stays valid as long as the tables are not replaced. It is built after the master data and
+// card names are read.
+type datasetIndex struct {
+	characters map[int]*CardCharacter
+	// cards by character ID
+	characterCards map[int]CardList
+	// cards by the card they are an evo accident of
+	evoAccidentOf map[int]*Card
+
+	// awakenings and rebirths by their base and result card IDs
+	awakeningsByBase   map[int][]*CardAwaken
+	awakeningsByResult map[int][]*CardAwaken
+	rebirthsByBase     map[int][]*CardAwaken
+	rebirthsByResult   map[int][]*CardAwaken
+
+	// positions in the amalgamation table by the card IDs of their materials and results
+	amalgamationsByCard     map[int][]int
+	amalgamationsByMaterial map[int][]int
+	amalgamationsByResult   map[int][]int
+
+	deckBonusConditions map[int][]*DeckBonusCond
+	mapAreas            map[int][]*Area
+	thorKings           map[int][]*ThorKing
+}
+
+// buildIndex indexes the current data
+func (d *Dataset) buildIndex() *datasetIndex {
+	data := d.Data
+	idx := &datasetIndex{
+		characters:              make(map[int]*CardCharacter, len(data.CardCharacters)),
+		characterCards:          make(map[int]CardList, len(data.CardCharacters)),
+		evoAccidentOf:           make(map[int]*Card),
+		awakeningsByBase:        make(map[int][]*CardAwaken, len(data.Awakenings)),
+		awakeningsByResult:      make(map[int][]*CardAwaken, len(data.Awakenings)),
+		rebirthsByBase:          make(map[int][]*CardAwaken, len(data.Rebirths)),
+		rebirthsByResult:        make(map[int][]*CardAwaken, len(data.Rebirths)),
+		amalgamationsByCard:     make(map[int][]int, len(data.Amalgamations)*5),
+		amalgamationsByMaterial: make(map[int][]int, len(data.Amalgamations)*4),
+		amalgamationsByResult:   make(map[int][]int, len(data.Amalgamations)),
+		deckBonusConditions:     make(map[int][]*DeckBonusCond, len(data.DeckBonuses)),
+		mapAreas:                make(map[int][]*Area, len(data.Maps)),
+		thorKings:               make(map[int][]*ThorKing, len(data.ThorEvents)),
+	}
+
+	for i := range data.CardCharacters {
+		ch := &data.CardCharacters[i]
+		// keep the first character with the ID
+		if _, ok := idx.characters[ch.ID]; !ok {
+			idx.characters[ch.ID] = ch
+		}
+	}
+	for _, c := range data.Cards {
+		if c.CardCharaID > 0 {
+			idx.characterCards[c.CardCharaID] = append(idx.characterCards[c.CardCharaID], c)
+		}
+		if _, ok := idx.evoAccidentOf[c.TransCardID]; !ok && c.TransCardID > 0 {
+			idx.evoAccidentOf[c.TransCardID] = c
+		}
+	}
+
+	for i := range data.Awakenings {
+		a := &data.Awakenings[i]
+		idx.awakeningsByBase[a.BaseCardID] = append(idx.awakeningsByBase[a.BaseCardID], a)
+		idx.awakeningsByResult[a.ResultCardID] = append(idx.awakeningsByResult[a.ResultCardID], a)
+	}
+	for i := range data.Rebirths {
+		a := &data.Rebirths[i]
+		idx.rebirthsByBase[a.BaseCardID] = append(idx.rebirthsByBase[a.BaseCardID], a)
+		idx.rebirthsByResult[a.ResultCardID] = append(idx.rebirthsByResult[a.ResultCardID], a)
+	}
+
+	for i, a := range data.Amalgamations {
+		materials := []int{a.Material1, a.Material2, a.Material3, a.Material4}
+		for j, m := range materials {
+			// list the amalgamation once for a card that is used more than once
+			if m > 0 && !util.ContainsInt(materials[:j], m) {
+				idx.amalgamationsByMaterial[m] = append(idx.amalgamationsByMaterial[m], i)
+			}
+		}
+		idx.amalgamationsByResult[a.FusionCardID] = append(idx.amalgamationsByResult[a.FusionCardID], i)
+		cards := append(materials, a.FusionCardID)
+		for j, c := range cards {
+			if c > 0 && !util.ContainsInt(cards[:j], c) {
+				idx.amalgamationsByCard[c] = append(idx.amalgamationsByCard[c], i)
+			}
+		}
+	}
+
+	for i := range data.DeckBonusConditions {
+		c := &data.DeckBonusConditions[i]
+		idx.deckBonusConditions[c.DeckBonusID] = append(idx.deckBonusConditions[c.DeckBonusID], c)
+	}
+	for i := range data.Areas {
+		a := &data.Areas[i]
+		idx.mapAreas[a.MapID] = append(idx.mapAreas[a.MapID], a)
+	}
+	for i := range data.ThorKings {
+		k := &data.ThorKings[i]
+		idx.thorKings[k.ThorhammerID] = append(idx.thorKings[k.ThorhammerID], k)
+	}
+	return idx
+}
+
+// lookups the index of the dataset, building it if the data was not read with Read
+func (d *Dataset) lookups() *datasetIndex {
+	d.cacheLock.RLock()
+	idx := d.index
+	d.cacheLock.RUnlock()
+	if idx == nil {
+		idx = d.buildIndex()
+		d.cacheLock.Lock()
+		d.index = idx
+		d.cacheLock.Unlock()
+	}
+	return idx
+}
@@ -0,0 +1,74 @@
+package vc
+
+import "testing"
+
+func testIndexDataset() *Dataset {
+	d := NewDataset("", "en")
+	d.Data.Cards = CardList{
+		{ID: 1, CardCharaID: 10, EvolutionRank: 1},
+		{ID: 2, CardCharaID: 10, EvolutionRank: 0},
+		{ID: 3, CardCharaID: 11, TransCardID: 4},
+		{ID: 4, CardCharaID: 12, IsClosed: 1},
+		{ID: 5, CardCharaID: 12, IsClosed: 1},
+	}
+	d.Data.CardCharacters = []CardCharacter{{ID: 10}, {ID: 11}, {ID: 12}}
+	d.Data.Awakenings = []CardAwaken{
+		{ID: 1, BaseCardID: 1, ResultCardID: 3, IsClosed: 1},
+		{ID: 2, BaseCardID: 1, ResultCardID: 2},
+		{ID: 3, BaseCardID: 4, ResultCardID: 5, IsClosed: 1},
+		{ID: 4, BaseCardID: 3, ResultCardID: 5, IsClosed: 1},
+	}
+	d.Data.Amalgamations = []Amalgamation{
+		{ID: 1, Material1: 1, Material2: 1, Material3: 2, FusionCardID: 3},
+		{ID: 2, Material1: 3, Material2: 2, FusionCardID: 5},
+	}
+	d.Data.Areas = []Area{{ID: 1, MapID: 2}, {ID: 2, MapID: 1}, {ID: 3, MapID: 2}}
+	d.Data.Maps = []Map{{ID: 2}}
+	d.link()
+	return d
+}
+
+func TestDatasetIndex(t *testing.T) {
+	d := testIndexDataset()
+	cards := d.CardCharacterScan(10).Cards()
+	if len(cards) != 2 || cards[0].ID != 2 || cards[1].ID != 1 {
+		t.Errorf("Unexpected character cards: %v", cards)
+	}
+	if c := d.CardScanCharacter(12); c == nil || c.ID != 4 {
+		t.Errorf("Unexpected first card of character 12: %v", c)
+	}
+	if c := d.CardScan(4).EvoAccidentOf(); c == nil || c.ID != 3 {
+		t.Errorf("Unexpected evo accident source: %v", c)
+	}
+
+	// open awakenings are used before closed ones
+	if c := d.CardScan(1).AwakensTo(); c == nil || c.ID != 2 {
+		t.Errorf("Card 1 should awaken to card 2, got %v", c)
+	}
+	// closed awakenings are only used by closed cards
+	if c := d.CardScan(4).AwakensTo(); c == nil || c.ID != 5 {
+		t.Errorf("Card 4 should awaken to card 5, got %v", c)
+	}
+	if c := d.CardScan(3).AwakensTo(); c != nil {
+		t.Errorf("Card 3 should not awaken, got %v", c)
+	}
+	// the last closed awakening is used
+	if c := d.CardScan(5).AwakensFrom(); c == nil || c.ID != 3 {
+		t.Errorf("Card 5 should awaken from card 3, got %v", c)
+	}
+
+	if amals := d.CardScan(1).Amalgamations(); len(amals) != 1 {
+		t.Errorf("A card used twice in an amalgamation should list it once: %v", amals)
+	}
+	if amals := d.CardScan(3).Amalgamations(); len(amals) != 2 || amals[0].ID != 1 || amals[1].ID != 2 {
+		t.Errorf("Unexpected amalgamations for card 3: %v", amals)
+	}
+	if !d.CardScan(3).IsAmalgamation() || d.CardScan(1).IsAmalgamation() || d.CardScan(5).HasAmalgamation() {
+		t.Errorf("Unexpected amalgamation flags")
+	}
+
+	areas := d.Data.Maps[0].Areas()
+	if len(areas) != 2 || areas[0].ID != 1 || areas[1].ID != 3 {
+		t.Errorf("Unexpected map areas: %v", areas)
+	}
+}
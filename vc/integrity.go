@@ -0,0 +1,295 @@
+package vc
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Integrity report categories, by the kind of record that is referenced
+const (
+	IntegrityCards        = "Cards"
+	IntegrityCharacters   = "Characters"
+	IntegritySkills       = "Skills"
+	IntegrityItems        = "Items"
+	IntegrityEvents       = "Events"
+	IntegrityMaps         = "Maps"
+	IntegrityArchwitches  = "Archwitches"
+	IntegrityWeapons      = "Weapons"
+	IntegrityThor         = "Thor"
+	IntegrityGuildBattles = "Guild Battles"
+)
+
+// IntegrityCategories the report categories in the order they are shown
+var IntegrityCategories = []string{
+	IntegrityCards,
+	IntegrityCharacters,
+	IntegritySkills,
+	IntegrityItems,
+	IntegrityEvents,
+	IntegrityMaps,
+	IntegrityArchwitches,
+	IntegrityWeapons,
+	IntegrityThor,
+	IntegrityGuildBattles,
+}
+
+// IntegrityIssue a reference in the master data that is broken
+type IntegrityIssue struct {
+	Category string `json:"category"`
+	// Table the master data table of the record with the reference
+	Table    string `json:"table"`
+	RecordID int    `json:"recordId"`
+	Field    string `json:"field"`
+	RefID    int    `json:"refId"`
+	Problem  string `json:"problem"`
+}
+
+// IntegrityReport the result of checking the references between the master data tables
+type IntegrityReport struct {
+	// Checked the number of references that were checked
+	Checked int              `json:"checked"`
+	Issues  []IntegrityIssue `json:"issues"`
+}
+
+// IsEmpty true if no broken references were found
+func (r *IntegrityReport) IsEmpty() bool {
+	return len(r.Issues) == 0
+}
+
+// Category the issues found in a category
+func (r *IntegrityReport) Category(category string) []IntegrityIssue {
+	ret := make([]IntegrityIssue, 0)
+	for _, issue := range r.Issues {
+		if issue.Category == category {
+			ret = append(ret, issue)
+		}
+	}
+	return ret
+}
+
+// Categories the categories that have issues, in the order of IntegrityCategories
+func (r *IntegrityReport) Categories() []string {
+	ret := make([]string, 0)
+	for _, category := range IntegrityCategories {
+		for _, issue := range r.Issues {
+			if issue.Category == category {
+				ret = append(ret, category)
+				break
+			}
+		}
+	}
+	return ret
+}
+
+// integrityChecker collects the issues while the references are checked
+type integrityChecker struct {
+	report IntegrityReport
+}
+
+// ref checks a reference to a record. IDs of 0 or less mean there is no reference
+func (c *integrityChecker) ref(category, table string, recordID int, field string, refID int, target string, ids map[int]bool) {
+	if refID <= 0 {
+		return
+	}
+	c.report.Checked++
+	if !ids[refID] {
+		c.issue(category, table, recordID, field, refID, fmt.Sprintf("%s %d does not exist", target, refID))
+	}
+}
+
+func (c *integrityChecker) issue(category, table string, recordID int, field string, refID int, problem string) {
+	c.report.Issues = append(c.report.Issues, IntegrityIssue{
+		Category: category,
+		Table:    table,
+		RecordID: recordID,
+		Field:    field,
+		RefID:    refID,
+		Problem:  problem,
+	})
+}
+
+// idsOf the set of IDs of a table
+func idsOf(n int, id func(i int) int) map[int]bool {
+	ids := make(map[int]bool, n)
+	for i := 0; i < n; i++ {
+		ids[id(i)] = true
+	}
+	return ids
+}
+
+// ValidateIntegrity checks that the references between the master data tables point at records
+// that exist, and that the chain front skills do not loop
+func (d *Dataset) ValidateIntegrity() *IntegrityReport {
+	data := d.Data
+	c := &integrityChecker{report: IntegrityReport{Issues: make([]IntegrityIssue, 0)}}
+
+	cards := idsOf(len(data.Cards), func(i int) int { return data.Cards[i].ID })
+	characters := idsOf(len(data.CardCharacters), func(i int) int { return data.CardCharacters[i].ID })
+	rarities := idsOf(len(data.CardRarities), func(i int) int { return data.CardRarities[i].ID })
+	skills := idsOf(len(data.Skills), func(i int) int { return data.Skills[i].ID })
+	items := idsOf(len(data.Items), func(i int) int { return data.Items[i].ID })
+	events := idsOf(len(data.Events), func(i int) int { return data.Events[i].ID })
+	maps := idsOf(len(data.Maps), func(i int) int { return data.Maps[i].ID })
+	archwitches := idsOf(len(data.Archwitches), func(i int) int { return data.Archwitches[i].ID })
+	series := idsOf(len(data.ArchwitchSeries), func(i int) int { return data.ArchwitchSeries[i].ID })
+	towers := idsOf(len(data.Towers), func(i int) int { return data.Towers[i].ID })
+	dungeons := idsOf(len(data.Dungeons), func(i int) int { return data.Dungeons[i].ID })
+	weaponEvents := idsOf(len(data.WeaponEvents), func(i int) int { return data.WeaponEvents[i].ID })
+	weapons := idsOf(len(data.Weapons), func(i int) int { return data.Weapons[i].ID })
+	thorEvents := idsOf(len(data.ThorEvents), func(i int) int { return data.ThorEvents[i].ID })
+	thorCostGroups := idsOf(len(data.ThorKingCosts), func(i int) int { return data.ThorKingCosts[i].GroupID })
+	guildBattles := idsOf(len(data.GuildBattles), func(i int) int { return data.GuildBattles[i].ID })
+
+	for _, card := range data.Cards {
+		c.ref(IntegrityCharacters, "cards", card.ID, "card_chara_id", card.CardCharaID, "character", characters)
+		c.ref(IntegrityCards, "cards", card.ID, "card_rare_id", card.CardRareID, "rarity", rarities)
+		c.ref(IntegrityCards, "cards", card.ID, "evolution_card_id", card.EvolutionCardID, "card", cards)
+		c.ref(IntegrityCards, "cards", card.ID, "trans_card_id", card.TransCardID, "card", cards)
+		c.ref(IntegritySkills, "cards", card.ID, "skill_id_1", card.SkillID1, "skill", skills)
+		c.ref(IntegritySkills, "cards", card.ID, "skill_id_2", card.SkillID2, "skill", skills)
+		c.ref(IntegritySkills, "cards", card.ID, "skill_id_3", card.SkillID3, "skill", skills)
+		c.ref(IntegritySkills, "cards", card.ID, "special_skill_id_1", card.SpecialSkillID1, "skill", skills)
+		c.ref(IntegritySkills, "cards", card.ID, "thor_skill_id_1", card.ThorSkillID1, "skill", skills)
+	}
+	for _, a := range data.Amalgamations {
+		c.ref(IntegrityCards, "fusion_list", a.ID, "material_1", a.Material1, "card", cards)
+		c.ref(IntegrityCards, "fusion_list", a.ID, "material_2", a.Material2, "card", cards)
+		c.ref(IntegrityCards, "fusion_list", a.ID, "material_3", a.Material3, "card", cards)
+		c.ref(IntegrityCards, "fusion_list", a.ID, "material_4", a.Material4, "card", cards)
+		c.ref(IntegrityCards, "fusion_list", a.ID, "fusion_card_id", a.FusionCardID, "card", cards)
+	}
+	checkAwakenings := func(table string, awakenings []CardAwaken) {
+		for _, a := range awakenings {
+			c.ref(IntegrityCards, table, a.ID, "base_card_id", a.BaseCardID, "card", cards)
+			c.ref(IntegrityCards, table, a.ID, "result_card_id", a.ResultCardID, "card", cards)
+			c.ref(IntegrityItems, table, a.ID, "material_1_item", a.Material1Item, "item", items)
+			c.ref(IntegrityItems, table, a.ID, "material_2_item", a.Material2Item, "item", items)
+			c.ref(IntegrityItems, table, a.ID, "material_3_item", a.Material3Item, "item", items)
+			c.ref(IntegrityItems, table, a.ID, "material_4_item", a.Material4Item, "item", items)
+			c.ref(IntegrityItems, table, a.ID, "material_5_item", a.Material5Item, "item", items)
+		}
+	}
+	checkAwakenings("card_awaken", data.Awakenings)
+	checkAwakenings("card_super_awaken", data.Rebirths)
+
+	for _, s := range data.Skills {
+		for _, id := range s.ChainFrontSkillIDs {
+			c.ref(IntegritySkills, "skills", s.ID, "chain_front_skills", id, "skill", skills)
+		}
+		c.ref(IntegrityItems, "skills", s.ID, "receipt_item_id", s.ReceiptItemID, "item", items)
+	}
+	c.checkSkillChains(data.Skills)
+
+	for _, e := range data.Events {
+		c.ref(IntegrityMaps, "mst_event", e.ID, "map_id", e.MapID, "map", maps)
+		c.ref(IntegrityArchwitches, "mst_event", e.ID, "king_series_id", e.KingSeriesID, "archwitch series", series)
+		c.ref(IntegrityGuildBattles, "mst_event", e.ID, "guild_battle_id", e.GuildBattleID, "guild battle", guildBattles)
+		c.ref(IntegrityEvents, "mst_event", e.ID, "tower_event_id", e.TowerEventID, "tower", towers)
+		c.ref(IntegrityEvents, "mst_event", e.ID, "dungeon_event_id", e.DungeonEventID, "dungeon", dungeons)
+		c.ref(IntegrityWeapons, "mst_event", e.ID, "weapon_event_id", e.WeaponEventID, "weapon event", weaponEvents)
+	}
+	for _, b := range data.EventBooks {
+		c.ref(IntegrityEvents, "mst_event_book", b.ID, "event_id", b.EventID, "event", events)
+	}
+	for _, ec := range data.EventCards {
+		c.ref(IntegrityCards, "mst_event_card", ec.ID, "card_id", ec.CardID, "card", cards)
+	}
+
+	for _, a := range data.Areas {
+		c.ref(IntegrityMaps, "area", a.ID, "map_id", a.MapID, "map", maps)
+	}
+	for _, m := range data.Maps {
+		c.ref(IntegrityArchwitches, "map", m.ID, "king_series_id", m.KingSeriesID, "archwitch series", series)
+		c.ref(IntegrityItems, "map", m.ID, "exchange_item_id", m.ExchangeItemID, "item", items)
+	}
+	for _, dungeon := range data.Dungeons {
+		c.ref(IntegrityItems, "mst_dungeon", dungeon.ID, "exchange_item_id", dungeon.ExchangeItemID, "item", items)
+	}
+
+	for _, aw := range data.Archwitches {
+		c.ref(IntegrityArchwitches, "kings", aw.ID, "king_series_id", aw.KingSeriesID, "archwitch series", series)
+		c.ref(IntegrityCards, "kings", aw.ID, "card_master_id", aw.CardMasterID, "card", cards)
+	}
+	for _, s := range data.ArchwitchSeries {
+		c.ref(IntegrityCards, "king_series", s.ID, "reward_card_id", s.RewardCardID, "card", cards)
+	}
+	for _, f := range data.ArchwitchFriendships {
+		c.ref(IntegrityArchwitches, "king_friendship", f.ID, "king_id", f.KingID, "archwitch", archwitches)
+	}
+
+	for _, we := range data.WeaponEvents {
+		c.ref(IntegrityWeapons, "mst_weapon_event", we.ID, "weapon_id", we.WeaponID, "weapon", weapons)
+	}
+	for _, wm := range data.WeaponMaterials {
+		c.ref(IntegrityWeapons, "mst_weapon_material", wm.ID, "weapon_id", wm.WeaponID, "weapon", weapons)
+		c.ref(IntegrityItems, "mst_weapon_material", wm.ID, "item_id", wm.ItemID, "item", items)
+	}
+	for _, r := range data.WeaponSkillUnlockRanks {
+		c.ref(IntegrityWeapons, "mst_weapon_skill_unlock_rank", r.ID, "weapon_id", r.WeaponID, "weapon", weapons)
+	}
+
+	for _, k := range data.ThorKings {
+		c.ref(IntegrityThor, "mst_thorhammer_king", k.ID, "thorhammer_id", k.ThorhammerID, "thor event", thorEvents)
+		c.ref(IntegrityThor, "mst_thorhammer_king", k.ID, "cost_group_id", k.CostGroupID, "thor king cost group", thorCostGroups)
+	}
+
+	for _, r := range data.GuildBattleRewardRefs {
+		c.ref(IntegrityGuildBattles, "mst_guildbattle_point_reward", r.ID, "event_id", r.EventID, "guild battle", guildBattles)
+	}
+
+	return &c.report
+}
+
+// checkSkillChains reports each loop in the chain front skills once
+func (c *integrityChecker) checkSkillChains(skills []Skill) {
+	chains := make(map[int][]int, len(skills))
+	for _, s := range skills {
+		if len(s.ChainFrontSkillIDs) > 0 {
+			chains[s.ID] = s.ChainFrontSkillIDs
+		}
+	}
+	ids := make([]int, 0, len(chains))
+	for id := range chains {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[int]int, len(chains))
+	path := make([]int, 0)
+	var visit func(id int)
+	visit = func(id int) {
+		state[id] = visiting
+		path = append(path, id)
+		for _, next := range chains[id] {
+			switch state[next] {
+			case visiting:
+				// the loop is the part of the path from the first visit of next
+				start := len(path) - 1
+				for path[start] != next {
+					start--
+				}
+				loop := make([]string, 0, len(path)-start+1)
+				for _, p := range path[start:] {
+					loop = append(loop, strconv.Itoa(p))
+				}
+				loop = append(loop, strconv.Itoa(next))
+				c.issue(IntegritySkills, "skills", id, "chain_front_skills", next, "the chain front skills loop: "+strings.Join(loop, " -> "))
+			case 0:
+				visit(next)
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = done
+	}
+	for _, id := range ids {
+		if state[id] == 0 {
+			visit(id)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package vc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateIntegrity(t *testing.T) {
+	d := NewDataset("", "en")
+	d.Data.Cards = CardList{
+		{ID: 1, CardCharaID: 10, EvolutionCardID: 2, SkillID1: 100},
+		{ID: 2, CardCharaID: 10, EvolutionCardID: -1, SkillID1: 101},
+		{ID: 3, CardCharaID: 11, EvolutionCardID: 9},
+	}
+	d.Data.CardCharacters = []CardCharacter{{ID: 10}, {ID: 11}}
+	d.Data.Skills = []Skill{
+		{ID: 100, ChainFrontSkillIDs: []int{102}},
+		{ID: 102, ChainFrontSkillIDs: []int{103}},
+		{ID: 103, ChainFrontSkillIDs: []int{102}},
+	}
+	d.Data.Items = []Item{{ID: 50}}
+	d.Data.Awakenings = []CardAwaken{{ID: 1, BaseCardID: 1, ResultCardID: 2, Material1Item: 50, Material2Item: 51}}
+	d.link()
+
+	report := d.ValidateIntegrity()
+	expected := map[string]string{
+		"cards evolution_card_id":     "card 9 does not exist",
+		"cards skill_id_1":            "skill 101 does not exist",
+		"card_awaken material_2_item": "item 51 does not exist",
+		"skills chain_front_skills":   "the chain front skills loop: 102 -> 103 -> 102",
+	}
+	if len(report.Issues) != len(expected) {
+		t.Errorf("Expected %d issues, got %v", len(expected), report.Issues)
+	}
+	for _, issue := range report.Issues {
+		key := issue.Table + " " + issue.Field
+		if want, ok := expected[key]; !ok || !strings.Contains(issue.Problem, want) {
+			t.Errorf("Unexpected issue %v", issue)
+		}
+	}
+
+	categories := report.Categories()
+	if len(categories) != 3 || categories[0] != IntegrityCards || categories[1] != IntegritySkills || categories[2] != IntegrityItems {
+		t.Errorf("Unexpected categories: %v", categories)
+	}
+}
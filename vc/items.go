@@ -12,6 +12,7 @@ import (
 
 // Item "items" is the list of Archwitches
 type Item struct {
+	datasetRef
 	ID                int       `json:"_id"`
 	GroupID           int       `json:"group_id"`
 	Name              string    `json:"name"`
@@ -38,15 +39,18 @@ type Item struct {
 	DescriptionSub    string    `json:"-"` // MsgShopItemDescSub_en.strb
 	NameEng           string    `json:"-"` // MsgShopItemName_en.strb
 	MsgUse            string    `json:"-"` // MsgShopItemUseResult_en.strb
+
+	// Translations text from the string files in each loaded language pack
+	Translations Translations `json:"-"`
 }
 
 // ItemScan searches for an item by ID
-func ItemScan(id int) *Item {
+func (d *Dataset) ItemScan(id int) *Item {
 	if id > 0 {
-		l := len(Data.Items)
-		i := sort.Search(l, func(i int) bool { return Data.Items[i].ID >= id })
-		if i >= 0 && i < l && Data.Items[i].ID == id {
-			return &(Data.Items[i])
+		l := len(d.Data.Items)
+		i := sort.Search(l, func(i int) bool { return d.Data.Items[i].ID >= id })
+		if i >= 0 && i < l && d.Data.Items[i].ID == id {
+			return &(d.Data.Items[i])
 		}
 	}
 	return nil
@@ -57,7 +61,7 @@ func (i Item) GetImageData() (imageName string, data []byte, fsInfo fs.FileInfo,
 	if i.ItemNo < 1 || i.NameEng == "" {
 		return
 	}
-	var path string = filepath.Join(FilePath, "item", "shop")
+	var path string = filepath.Join(i.ds.FilePath, "item", "shop")
 	fileName := fmt.Sprintf("%d", i.ItemNo)
 	fullpath := filepath.Join(path, fileName)
 	if fsInfo, err = os.Stat(fullpath); os.IsNotExist(err) {
@@ -0,0 +1,225 @@
+package vc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Resources amounts of the kingdom resources
+type Resources struct {
+	Gold   int `json:"gold"`
+	Iron   int `json:"iron"`
+	Ether  int `json:"ether"`
+	Elixir int `json:"elixir"`
+}
+
+// Add adds another set of resources to this one and returns the result
+func (r Resources) Add(o Resources) Resources {
+	return Resources{
+		Gold:   r.Gold + o.Gold,
+		Iron:   r.Iron + o.Iron,
+		Ether:  r.Ether + o.Ether,
+		Elixir: r.Elixir + o.Elixir,
+	}
+}
+
+// LevelUpFodder a card used as material to level up another card
+type LevelUpFodder struct {
+	CardID int `json:"cardId"`
+	// Level of the material card, 1 if not given
+	Level int `json:"level,omitempty"`
+	// Count how many of the card can be used, 0 for as many as needed
+	Count int `json:"count,omitempty"`
+
+	// filled in by PlanLevelUp
+	Card *Card  `json:"-"`
+	Name string `json:"name,omitempty"`
+	// Exp the EXP one of the card gives
+	Exp int `json:"exp"`
+	// NeededAlone how many of the card would be needed if it was the only material
+	NeededAlone int `json:"neededAlone"`
+	// Used how many of the card are used, in the order the materials are listed
+	Used int `json:"used"`
+}
+
+// LevelUpPlan levelling up a card from its current level to a goal level with a list of materials
+type LevelUpPlan struct {
+	CardID       int `json:"cardId"`
+	CurrentLevel int `json:"currentLevel"`
+	// GoalLevel the level to reach, the max level of the card's rarity if not given
+	GoalLevel int              `json:"goalLevel,omitempty"`
+	Fodder    []*LevelUpFodder `json:"fodder"`
+
+	// filled in by PlanLevelUp
+	Card      *Card  `json:"-"`
+	Name      string `json:"name,omitempty"`
+	ExpNeeded int    `json:"expNeeded"`
+	ExpGained int    `json:"expGained"`
+	// ReachedLevel the level the materials that are used get the card to
+	ReachedLevel int `json:"reachedLevel"`
+	// Cost the resources needed for the levels that are reached
+	Cost Resources `json:"cost"`
+}
+
+// LevelTable the EXP table the card levels up with
+func (c *Card) LevelTable() []CardLevel {
+	switch c.levelKind() {
+	case "XLR":
+		return c.ds.Data.CardLevelsXLR
+	case "X":
+		return c.ds.Data.CardLevelsX
+	case "LR":
+		return c.ds.Data.CardLevelsLR
+	}
+	return c.ds.Data.CardLevels
+}
+
+// LevelResources the resources needed to reach each level. Only LR and X cards need resources to level up
+func (c *Card) LevelResources() []LevelResource {
+	switch c.levelKind() {
+	case "XLR":
+		return c.ds.Data.LevelXLRResources
+	case "X":
+		return c.ds.Data.LevelXResources
+	case "LR":
+		return c.ds.Data.LevelLRResources
+	}
+	return nil
+}
+
+// levelKind which level tables the card uses: "XLR", "X" (XSR and XUR), "LR" or "" for N to GUR
+func (c *Card) levelKind() string {
+	rarity := c.Rarity()
+	main := c.MainRarity()
+	if strings.HasPrefix(rarity, "X") && len(rarity) == 3 {
+		if main == "LR" {
+			return "XLR"
+		}
+		return "X"
+	}
+	if main == "LR" {
+		return "LR"
+	}
+	return ""
+}
+
+// MaxLevel the max level of the card's rarity
+func (c *Card) MaxLevel() int {
+	if r := c.CardRarity(); r != nil {
+		return r.MaxCardLevel
+	}
+	return 0
+}
+
+// LevelExp the total EXP the card needs to be at the level. The level table is scaled by the
+// rarity's card_level_coefficient, where 100 is the table as is
+func (c *Card) LevelExp(level int) int {
+	exp := 0
+	for _, l := range c.LevelTable() {
+		if l.ID == level {
+			exp = l.Exp
+			break
+		}
+	}
+	if r := c.CardRarity(); r != nil && r.CardLevelCoefficient > 0 {
+		exp = exp * r.CardLevelCoefficient / 100
+	}
+	return exp
+}
+
+// MaterialExp the EXP the card gives when it is used as a level up material. It is the card's
+// level times its special compose ratio, or the card_exp_coefficient of its rarity if the card
+// has no ratio of its own
+func (c *Card) MaterialExp(level int) int {
+	if level < 1 {
+		level = 1
+	}
+	ratio := 0
+	for _, sc := range c.ds.Data.CardSpecialComposes {
+		if sc.CardMasterID == c.ID {
+			ratio = sc.Ratio
+			break
+		}
+	}
+	if ratio == 0 {
+		if r := c.CardRarity(); r != nil {
+			ratio = r.CardExpCoefficient
+		}
+	}
+	return ratio * level
+}
+
+// PlanLevelUp calculates the EXP the materials give, how many are needed and the resources
+// needed to level up the card
+func (d *Dataset) PlanLevelUp(p *LevelUpPlan) error {
+	c := d.CardScan(p.CardID)
+	if c == nil {
+		return fmt.Errorf("card %d not found", p.CardID)
+	}
+	p.Card = c
+	p.Name = c.Name
+	if p.CurrentLevel < 1 {
+		p.CurrentLevel = 1
+	}
+	maxLevel := c.MaxLevel()
+	if p.GoalLevel <= 0 {
+		p.GoalLevel = maxLevel
+	}
+	if maxLevel > 0 && p.GoalLevel > maxLevel {
+		return fmt.Errorf("the max level of %s (%d) is %d", c.Name, c.ID, maxLevel)
+	}
+	if p.GoalLevel < p.CurrentLevel {
+		return fmt.Errorf("the goal level %d is below the current level %d", p.GoalLevel, p.CurrentLevel)
+	}
+
+	currentExp := c.LevelExp(p.CurrentLevel)
+	p.ExpNeeded = c.LevelExp(p.GoalLevel) - currentExp
+	p.ExpGained = 0
+	for _, f := range p.Fodder {
+		f.Card = d.CardScan(f.CardID)
+		if f.Card == nil {
+			return fmt.Errorf("material card %d not found", f.CardID)
+		}
+		f.Name = f.Card.Name
+		if f.Level < 1 {
+			f.Level = 1
+		}
+		f.Exp = f.Card.MaterialExp(f.Level)
+		f.NeededAlone = 0
+		f.Used = 0
+		if f.Exp <= 0 {
+			continue
+		}
+		f.NeededAlone = (p.ExpNeeded + f.Exp - 1) / f.Exp
+		remaining := p.ExpNeeded - p.ExpGained
+		if remaining <= 0 {
+			continue
+		}
+		f.Used = (remaining + f.Exp - 1) / f.Exp
+		if f.Count > 0 && f.Used > f.Count {
+			f.Used = f.Count
+		}
+		p.ExpGained += f.Used * f.Exp
+	}
+
+	p.ReachedLevel = p.CurrentLevel
+	for level := p.CurrentLevel + 1; level <= p.GoalLevel; level++ {
+		if c.LevelExp(level) > currentExp+p.ExpGained {
+			break
+		}
+		p.ReachedLevel = level
+	}
+
+	p.Cost = c.LevelCost(p.CurrentLevel, p.ReachedLevel)
+	return nil
+}
+
+// LevelCost the resources needed to level the card up from one level to another
+func (c *Card) LevelCost(from, to int) (cost Resources) {
+	for _, r := range c.LevelResources() {
+		if r.ID > from && r.ID <= to {
+			cost = cost.Add(Resources{Gold: r.Gold, Iron: r.Iron, Ether: r.Ether, Elixir: r.Elixir})
+		}
+	}
+	return
+}
@@ -0,0 +1,53 @@
+package vc
+
+import "testing"
+
+func TestPlanLevelUp(t *testing.T) {
+	d := NewDataset("", "en")
+	d.Rarity = []string{"N", "LR"}
+	d.Data.CardRarities = []CardRarity{
+		{ID: 1, MaxCardLevel: 5, CardExpCoefficient: 100, CardLevelCoefficient: 100},
+		{ID: 2, MaxCardLevel: 5, CardExpCoefficient: 500, CardLevelCoefficient: 200},
+	}
+	d.Data.Cards = CardList{
+		{ID: 1, CardRareID: 1},
+		{ID: 2, CardRareID: 2},
+		{ID: 3, CardRareID: 1},
+	}
+	d.Data.CardSpecialComposes = []CardSpecialCompose{{ID: 1, CardMasterID: 3, Ratio: 1000}}
+	d.Data.CardLevels = []CardLevel{{ID: 1}, {ID: 2, Exp: 100}, {ID: 3, Exp: 300}, {ID: 4, Exp: 600}, {ID: 5, Exp: 1000}}
+	d.Data.CardLevelsLR = d.Data.CardLevels
+	d.Data.LevelLRResources = []LevelResource{{ID: 2, Elixir: 1}, {ID: 3, Elixir: 2}, {ID: 4, Elixir: 3}, {ID: 5, Elixir: 4}}
+	d.link()
+
+	plan := &LevelUpPlan{CardID: 1, Fodder: []*LevelUpFodder{{CardID: 1, Level: 2, Count: 2}, {CardID: 3}}}
+	if err := d.PlanLevelUp(plan); err != nil {
+		t.Fatalf("PlanLevelUp returned an error: %s", err.Error())
+	}
+	if plan.GoalLevel != 5 || plan.ExpNeeded != 1000 {
+		t.Errorf("Expected 1000 EXP to level 5, got %d to level %d", plan.ExpNeeded, plan.GoalLevel)
+	}
+	if f := plan.Fodder[0]; f.Exp != 200 || f.NeededAlone != 5 || f.Used != 2 {
+		t.Errorf("Unexpected first material: %+v", f)
+	}
+	// the special compose ratio is used instead of the rarity coefficient
+	if f := plan.Fodder[1]; f.Exp != 1000 || f.Used != 1 {
+		t.Errorf("Unexpected second material: %+v", f)
+	}
+	if plan.ReachedLevel != 5 || plan.Cost.Elixir != 0 {
+		t.Errorf("Unexpected result: level %d, cost %+v", plan.ReachedLevel, plan.Cost)
+	}
+
+	// LR cards use their own tables, scaled by the level coefficient, and cost elixir
+	plan = &LevelUpPlan{CardID: 2, CurrentLevel: 2, Fodder: []*LevelUpFodder{{CardID: 2, Count: 1}}}
+	if err := d.PlanLevelUp(plan); err != nil {
+		t.Fatalf("PlanLevelUp returned an error: %s", err.Error())
+	}
+	if plan.ExpNeeded != 1800 || plan.ExpGained != 500 || plan.ReachedLevel != 3 || plan.Cost.Elixir != 2 {
+		t.Errorf("Unexpected LR result: %+v", plan)
+	}
+
+	if err := d.PlanLevelUp(&LevelUpPlan{CardID: 1, GoalLevel: 6}); err == nil {
+		t.Errorf("A goal above the max level should not be allowed")
+	}
+}
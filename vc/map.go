@@ -4,6 +4,7 @@ import "strings"
 
 // Map information
 type Map struct {
+	datasetRef
 	ID                  int       `json:"_id"`
 	NameJp              string    `json:"name"`
 	Order               int       `json:"order"`
@@ -26,19 +27,27 @@ type Map struct {
 	Name                string    `json:"name_tl"`   // MsgNPCMapName_en.strb
 	StartMsg            string    `json:"start_msg"` // MsgNPCMapStart_en.strb
 	areas               []Area
+
+	// Translations text from the string files in each loaded language pack
+	Translations Translations `json:"-"`
 }
 
 // Areas of the map
 func (m *Map) Areas() []Area {
-	if m.areas == nil {
-		m.areas = make([]Area, 0)
-		for k, a := range Data.Areas {
-			if a.MapID == m.ID {
-				m.areas = append(m.areas, Data.Areas[k])
-			}
+	lock := m.cacheLock()
+	lock.RLock()
+	areas := m.areas
+	lock.RUnlock()
+	if areas == nil {
+		areas = make([]Area, 0)
+		for _, a := range m.ds.lookups().mapAreas[m.ID] {
+			areas = append(areas, *a)
 		}
+		lock.Lock()
+		m.areas = areas
+		lock.Unlock()
 	}
-	return m.areas
+	return areas
 }
 
 //HasStory HasStory
@@ -85,8 +94,8 @@ func cleanForFileName(n string) string {
 
 //EventName event name
 func (m *Map) Event() *Event {
-	for i := range Data.Events {
-		e := &(Data.Events[i])
+	for i := range m.ds.Data.Events {
+		e := &(m.ds.Data.Events[i])
 		if e.MapID == m.ID {
 			return e
 		}
@@ -106,6 +115,9 @@ type Area struct {
 	Story     string `json:"-"` // MsgNPCAreaStory_en.strb
 	BossStart string `json:"-"` // MsgNPCBossStart_en.strb
 	BossEnd   string `json:"-"` // MsgNPCBossEnd_en.strb
+
+	// Translations text from the string files in each loaded language pack
+	Translations Translations `json:"-"`
 }
 
 // MapScan Searches for a Map by ID
@@ -0,0 +1,373 @@
+package vc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// OldNew old and new value of a changed field
+type OldNew struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// RecordDiff changes to a single master data record
+type RecordDiff struct {
+	ID     int               `json:"_id"`
+	Name   string            `json:"name,omitempty"`
+	Fields map[string]OldNew `json:"fields,omitempty"`
+}
+
+// TableDiff records that were added, removed or changed in a master data table
+type TableDiff struct {
+	Added   []RecordDiff `json:"added"`
+	Removed []RecordDiff `json:"removed"`
+	Changed []RecordDiff `json:"changed"`
+}
+
+// StringDiff a single changed line in a string file
+type StringDiff struct {
+	Line int `json:"line"`
+	OldNew
+}
+
+// StringFileDiff lines that were added, removed or changed in a string file
+type StringFileDiff struct {
+	File    string       `json:"file"`
+	Added   []StringDiff `json:"added"`
+	Removed []StringDiff `json:"removed"`
+	Changed []StringDiff `json:"changed"`
+}
+
+// MasterDiff changes between two versions of the master data
+type MasterDiff struct {
+	OldVersion   int              `json:"oldVersion"`
+	NewVersion   int              `json:"newVersion"`
+	OldTimestamp Timestamp        `json:"oldTimestamp"`
+	NewTimestamp Timestamp        `json:"newTimestamp"`
+	Cards        TableDiff        `json:"cards"`
+	Skills       TableDiff        `json:"skills"`
+	Events       TableDiff        `json:"events"`
+	Items        TableDiff        `json:"items"`
+	Weapons      TableDiff        `json:"weapons"`
+	Strings      []StringFileDiff `json:"strings"`
+}
+
+// IsEmpty true if the table has no changes
+func (t *TableDiff) IsEmpty() bool {
+	return len(t.Added) == 0 && len(t.Removed) == 0 && len(t.Changed) == 0
+}
+
+// LoadVFile loads master data into a new VFile without reading it into a dataset.
+// The path can be a VC data directory, or a master_all(.json) file.
+// When the path is a directory, the card, skill, event and item names are read from
+// its string files for the language pack if they are available.
+func LoadVFile(path, langPack string) (*VFile, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	file := path
+	if info.IsDir() {
+		file = filepath.Join(path, "response", "master_all")
+		if jsonInfo, err := os.Stat(file + ".json"); err == nil {
+			if mdInfo, err := os.Stat(file); err != nil || jsonInfo.ModTime().Unix() >= mdInfo.ModTime().Unix() {
+				file += ".json"
+			}
+		}
+	}
+
+	var data []byte
+	encoded, err := IsFileEncoded(file)
+	if err != nil {
+		return nil, err
+	}
+	if encoded {
+		data, err = Decode(file)
+		if err != nil {
+			return nil, err
+		}
+		data = trimTrailingZeros(data)
+	} else {
+		data, err = ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	v := &VFile{}
+	err = json.Unmarshal(data, v)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		v.readNames(filepath.Join(path, "string"), langPack)
+	}
+	return v, nil
+}
+
+// readNames fills in the names of the main records from the string files. Missing files are ignored.
+func (v *VFile) readNames(strRoot, langPack string) {
+	if names, err := ReadStringFile(filepath.Join(strRoot, "MsgCardName_"+langPack+".strb")); err == nil {
+		for _, card := range v.Cards {
+			if card.ID > 0 && card.ID <= len(names) {
+				card.Name = names[card.ID-1]
+			}
+		}
+	}
+	if names, err := ReadStringFile(filepath.Join(strRoot, "MsgSkillName_"+langPack+".strb")); err == nil {
+		for key := range v.Skills {
+			skill := &v.Skills[key]
+			if skill.ID > 0 && skill.ID <= len(names) {
+				skill.Name = filterSkill(names[skill.ID-1])
+			}
+		}
+	}
+	if names, err := ReadStringFile(filepath.Join(strRoot, "MsgEventName_"+langPack+".strb")); err == nil {
+		for key := range v.Events {
+			evnt := &v.Events[key]
+			if evnt.ID > 0 && evnt.ID <= len(names) {
+				evnt.Name = names[evnt.ID-1]
+			}
+		}
+	}
+	if names, err := ReadStringFile(filepath.Join(strRoot, "MsgShopItemName_"+langPack+".strb")); err == nil {
+		for key := range v.Items {
+			item := &v.Items[key]
+			if item.ID > 0 && item.ID <= len(names) {
+				item.NameEng = filterItemName(names[item.ID-1])
+			}
+		}
+	}
+}
+
+// DiffMasterData loads the master data from both locations and reports what changed between them.
+// String files for the language pack are only compared if both locations are data directories.
+func DiffMasterData(oldPath, newPath, langPack string) (*MasterDiff, error) {
+	oldData, err := LoadVFile(oldPath, langPack)
+	if err != nil {
+		return nil, err
+	}
+	newData, err := LoadVFile(newPath, langPack)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := DiffVFiles(oldData, newData)
+	if err != nil {
+		return nil, err
+	}
+	if isDir(oldPath) && isDir(newPath) {
+		diff.Strings, err = DiffStringFiles(filepath.Join(oldPath, "string"), filepath.Join(newPath, "string"), langPack)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return diff, nil
+}
+
+// DiffVFiles reports the changes to the cards, skills, events, items and weapons between two data files
+func DiffVFiles(oldData, newData *VFile) (diff *MasterDiff, err error) {
+	diff = &MasterDiff{
+		OldVersion:   oldData.Version,
+		NewVersion:   newData.Version,
+		OldTimestamp: oldData.Common.UnixTime,
+		NewTimestamp: newData.Common.UnixTime,
+		Strings:      make([]StringFileDiff, 0),
+	}
+
+	itemNames := func(v *VFile) map[int]string {
+		ret := make(map[int]string)
+		for _, item := range v.Items {
+			ret[item.ID] = item.NameEng
+		}
+		return ret
+	}
+
+	if diff.Cards, err = diffTable(oldData.Cards, newData.Cards, nil, nil); err != nil {
+		return nil, err
+	}
+	if diff.Skills, err = diffTable(oldData.Skills, newData.Skills, nil, nil); err != nil {
+		return nil, err
+	}
+	if diff.Events, err = diffTable(oldData.Events, newData.Events, nil, nil); err != nil {
+		return nil, err
+	}
+	if diff.Items, err = diffTable(oldData.Items, newData.Items, itemNames(oldData), itemNames(newData)); err != nil {
+		return nil, err
+	}
+	if diff.Weapons, err = diffTable(oldData.Weapons, newData.Weapons, nil, nil); err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+// DiffStringFiles compares the string files for the language pack in both folders
+func DiffStringFiles(oldRoot, newRoot, langPack string) ([]StringFileDiff, error) {
+	suffix := "_" + langPack + ".strb"
+	files := make(map[string]bool)
+	for _, root := range []string{oldRoot, newRoot} {
+		infos, err := ioutil.ReadDir(root)
+		if err != nil {
+			return nil, err
+		}
+		for _, info := range infos {
+			if !info.IsDir() && strings.HasSuffix(info.Name(), suffix) {
+				files[info.Name()] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ret := make([]StringFileDiff, 0)
+	for _, name := range names {
+		oldLines, err := readStringFileIfExists(filepath.Join(oldRoot, name))
+		if err != nil {
+			return nil, err
+		}
+		newLines, err := readStringFileIfExists(filepath.Join(newRoot, name))
+		if err != nil {
+			return nil, err
+		}
+		fd := StringFileDiff{
+			File:    name,
+			Added:   make([]StringDiff, 0),
+			Removed: make([]StringDiff, 0),
+			Changed: make([]StringDiff, 0),
+		}
+		for i := 0; i < len(oldLines) || i < len(newLines); i++ {
+			switch {
+			case i >= len(oldLines):
+				fd.Added = append(fd.Added, StringDiff{i + 1, OldNew{"", newLines[i]}})
+			case i >= len(newLines):
+				fd.Removed = append(fd.Removed, StringDiff{i + 1, OldNew{oldLines[i], ""}})
+			case oldLines[i] != newLines[i]:
+				fd.Changed = append(fd.Changed, StringDiff{i + 1, OldNew{oldLines[i], newLines[i]}})
+			}
+		}
+		if len(fd.Added) > 0 || len(fd.Removed) > 0 || len(fd.Changed) > 0 {
+			ret = append(ret, fd)
+		}
+	}
+	return ret, nil
+}
+
+func readStringFileIfExists(file string) ([]string, error) {
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	return ReadStringFileFilter(file, false)
+}
+
+// diffTable compares two lists of master data records field by field using their JSON form.
+// The records are matched by their _id. If a name map is not given, the "name" field is used.
+func diffTable(oldList, newList interface{}, oldNames, newNames map[int]string) (diff TableDiff, err error) {
+	diff = TableDiff{
+		Added:   make([]RecordDiff, 0),
+		Removed: make([]RecordDiff, 0),
+		Changed: make([]RecordDiff, 0),
+	}
+	oldRecords, oldIDs, err := tableRecords(oldList)
+	if err != nil {
+		return
+	}
+	newRecords, newIDs, err := tableRecords(newList)
+	if err != nil {
+		return
+	}
+
+	recordName := func(id int, record map[string]string, names map[int]string) string {
+		if names != nil {
+			return names[id]
+		}
+		return record["name"]
+	}
+
+	for _, id := range oldIDs {
+		oldRecord := oldRecords[id]
+		newRecord, ok := newRecords[id]
+		if !ok {
+			diff.Removed = append(diff.Removed, RecordDiff{ID: id, Name: recordName(id, oldRecord, oldNames)})
+			continue
+		}
+		fields := make(map[string]OldNew)
+		for k, oldv := range oldRecord {
+			if newv := newRecord[k]; oldv != newv {
+				fields[k] = OldNew{oldv, newv}
+			}
+		}
+		for k, newv := range newRecord {
+			if _, ok := oldRecord[k]; !ok {
+				fields[k] = OldNew{"", newv}
+			}
+		}
+		if len(fields) > 0 {
+			diff.Changed = append(diff.Changed, RecordDiff{ID: id, Name: recordName(id, newRecord, newNames), Fields: fields})
+		}
+	}
+	for _, id := range newIDs {
+		if _, ok := oldRecords[id]; !ok {
+			diff.Added = append(diff.Added, RecordDiff{ID: id, Name: recordName(id, newRecords[id], newNames)})
+		}
+	}
+	return
+}
+
+// tableRecords converts a list of records to maps of field values keyed by the record _id
+func tableRecords(list interface{}) (records map[int]map[string]string, ids []int, err error) {
+	b, err := json.Marshal(list)
+	if err != nil {
+		return
+	}
+	raw := make([]map[string]json.RawMessage, 0)
+	err = json.Unmarshal(b, &raw)
+	if err != nil {
+		return
+	}
+	records = make(map[int]map[string]string, len(raw))
+	ids = make([]int, 0, len(raw))
+	for _, r := range raw {
+		var id int
+		if err = json.Unmarshal(r["_id"], &id); err != nil {
+			return nil, nil, errors.New("record without a valid _id: " + err.Error())
+		}
+		record := make(map[string]string, len(r))
+		for k, v := range r {
+			record[k] = rawJSONToString(v)
+		}
+		records[id] = record
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return
+}
+
+// rawJSONToString converts a raw JSON value to a readable string. JSON strings are unquoted.
+func rawJSONToString(v json.RawMessage) string {
+	if len(v) > 0 && v[0] == '"' {
+		var s string
+		if err := json.Unmarshal(v, &s); err == nil {
+			return s
+		}
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, v); err == nil {
+		return buf.String()
+	}
+	return string(v)
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
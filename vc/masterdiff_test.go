@@ -0,0 +1,114 @@
+package vc
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDiffVFiles(t *testing.T) {
+	oldData := &VFile{Version: 1}
+	oldData.Cards = CardList{
+		{ID: 1, Name: "Alpha", DeckCost: 10},
+		{ID: 2, Name: "Beta", DeckCost: 12},
+	}
+	oldData.Items = []Item{
+		{ID: 1, Name: "item_a", NameEng: "Potion", Ratio: 1},
+		{ID: 2, Name: "item_b", NameEng: "Elixir"},
+	}
+	newData := &VFile{Version: 2}
+	newData.Cards = CardList{
+		{ID: 2, Name: "Beta", DeckCost: 15},
+		{ID: 3, Name: "Gamma", DeckCost: 20},
+	}
+	newData.Items = []Item{
+		{ID: 1, Name: "item_a", NameEng: "Potion", Ratio: 2},
+		{ID: 3, Name: "item_c", NameEng: "Ether"},
+	}
+
+	diff, err := DiffVFiles(oldData, newData)
+	if err != nil {
+		t.Fatalf("DiffVFiles returned an error: %s", err.Error())
+	}
+	if diff.OldVersion != 1 || diff.NewVersion != 2 {
+		t.Errorf("Unexpected versions %d and %d", diff.OldVersion, diff.NewVersion)
+	}
+
+	tests := []struct {
+		name  string
+		table TableDiff
+		want  TableDiff
+	}{
+		{"cards", diff.Cards, TableDiff{
+			Added:   []RecordDiff{{ID: 3, Name: "Gamma"}},
+			Removed: []RecordDiff{{ID: 1, Name: "Alpha"}},
+			Changed: []RecordDiff{{ID: 2, Name: "Beta", Fields: map[string]OldNew{"deck_cost": {"12", "15"}}}},
+		}},
+		// items are named from the string file, not the name field of the data
+		{"items", diff.Items, TableDiff{
+			Added:   []RecordDiff{{ID: 3, Name: "Ether"}},
+			Removed: []RecordDiff{{ID: 2, Name: "Elixir"}},
+			Changed: []RecordDiff{{ID: 1, Name: "Potion", Fields: map[string]OldNew{"ratio": {"1", "2"}}}},
+		}},
+	}
+	for _, test := range tests {
+		if !reflect.DeepEqual(test.table, test.want) {
+			t.Errorf("Unexpected %s diff:\n%+v\nexpected\n%+v", test.name, test.table, test.want)
+		}
+	}
+	if !diff.Skills.IsEmpty() || !diff.Events.IsEmpty() || !diff.Weapons.IsEmpty() {
+		t.Errorf("Unexpected changes to tables that are the same: %+v %+v %+v", diff.Skills, diff.Events, diff.Weapons)
+	}
+}
+
+func TestDiffTableKeys(t *testing.T) {
+	// a field only in one of the versions is reported with an empty value on the other side
+	oldList := []map[string]interface{}{{"_id": 1, "name": "Alpha", "gone": "x"}}
+	newList := []map[string]interface{}{{"_id": 1, "name": "Alpha", "added": []int{1, 2}}}
+	diff, err := diffTable(oldList, newList, nil, nil)
+	if err != nil {
+		t.Fatalf("diffTable returned an error: %s", err.Error())
+	}
+	want := []RecordDiff{{ID: 1, Name: "Alpha", Fields: map[string]OldNew{"gone": {"x", ""}, "added": {"", "[1,2]"}}}}
+	if !reflect.DeepEqual(diff.Changed, want) || len(diff.Added) != 0 || len(diff.Removed) != 0 {
+		t.Errorf("Unexpected diff: %+v", diff)
+	}
+
+	if _, err := diffTable([]map[string]string{{"name": "no id"}}, newList, nil, nil); err == nil {
+		t.Errorf("A record without an _id should be an error")
+	}
+}
+
+func TestDiffStringFiles(t *testing.T) {
+	oldRoot := t.TempDir()
+	newRoot := t.TempDir()
+	writeTestFile(t, filepath.Join(oldRoot, "MsgCardName_en.strb"), testStrb([]uint32{5, 11, 17}, "Alpha", "Beta", "Gamma"))
+	writeTestFile(t, filepath.Join(newRoot, "MsgCardName_en.strb"), testStrb([]uint32{5, 11}, "Alpha", "Bravo"))
+	writeTestFile(t, filepath.Join(oldRoot, "MsgSkillName_en.strb"), testStrb([]uint32{5}, "Same"))
+	writeTestFile(t, filepath.Join(newRoot, "MsgSkillName_en.strb"), testStrb([]uint32{5}, "Same"))
+	writeTestFile(t, filepath.Join(newRoot, "MsgEventName_en.strb"), testStrb([]uint32{5}, "New"))
+	// other language packs are not compared
+	writeTestFile(t, filepath.Join(newRoot, "MsgCardName_zhs.strb"), testStrb([]uint32{5}, "阿尔法"))
+
+	diffs, err := DiffStringFiles(oldRoot, newRoot, "en")
+	if err != nil {
+		t.Fatalf("DiffStringFiles returned an error: %s", err.Error())
+	}
+	want := []StringFileDiff{
+		{
+			File:    "MsgCardName_en.strb",
+			Added:   []StringDiff{},
+			Removed: []StringDiff{{3, OldNew{"Gamma", ""}}},
+			Changed: []StringDiff{{2, OldNew{"Beta", "Bravo"}}},
+		},
+		{
+			File:    "MsgEventName_en.strb",
+			Added:   []StringDiff{{1, OldNew{"", "New"}}},
+			Removed: []StringDiff{},
+			Changed: []StringDiff{},
+		},
+	}
+	if !reflect.DeepEqual(diffs, want) {
+		t.Errorf("Unexpected string file diffs:\n%+v\nexpected\n%+v", diffs, want)
+	}
+}
@@ -0,0 +1,243 @@
+package vc
+
+import (
+	_ "embed" // for the built in name overrides
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// NameOverridesFile the name of the file with the card name fixes. It is read from the data location,
+// or from the folder of the program. The built in copy is used if neither has one.
+const NameOverridesFile = "name_overrides.json"
+
+// nameOverridesVersion the newest version of the overrides file that can be read
+const nameOverridesVersion = 1
+
+//go:embed name_overrides.json
+var defaultNameOverrides []byte
+
+// builtinNameOverrides the overrides for records that are not part of a dataset
+var builtinNameOverrides = DefaultNameOverrides()
+
+// CardGroup a list of card IDs with a note on why they are listed
+type CardGroup struct {
+	Cards   []int  `json:"cards"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// CharacterName a fixed name for all the cards of a character
+type CharacterName struct {
+	CharacterID int    `json:"character"`
+	Name        string `json:"name"`
+	Comment     string `json:"comment,omitempty"`
+}
+
+// NameRule text that is replaced in the card names that are not overridden
+type NameRule struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// BeforeCharacter only use the rule for characters with a lower ID. 0 uses it for all characters
+	BeforeCharacter int    `json:"beforeCharacter,omitempty"`
+	Comment         string `json:"comment,omitempty"`
+}
+
+// NameOverrides fixes to the card names so they match the wiki
+type NameOverrides struct {
+	Version int `json:"version"`
+	// RetiredCards old cards that are not available anymore. "(Old)" is added to their names
+	RetiredCards []CardGroup `json:"retiredCards"`
+	// NewCards new cards that are named the same as an old card that is still active. "(New)" is added to their names
+	NewCards []CardGroup `json:"newCards"`
+	// CharacterNames names used instead of the name from the string files
+	CharacterNames []CharacterName `json:"characterNames"`
+	// NameRules replacements made, in order, to the names from the string files
+	NameRules []NameRule `json:"nameRules"`
+
+	// File the overrides were read from. Blank for the built in overrides
+	File string `json:"-"`
+
+	retired  map[int]bool
+	newCards map[int]bool
+	names    map[int]string
+}
+
+// DefaultNameOverrides the overrides that are built into the program
+func DefaultNameOverrides() *NameOverrides {
+	o, err := ParseNameOverrides(defaultNameOverrides)
+	if err != nil {
+		panic("the built in name overrides can not be read: " + err.Error())
+	}
+	return o
+}
+
+// DefaultNameOverridesJSON the contents of the built in overrides file
+func DefaultNameOverridesJSON() []byte {
+	return append([]byte(nil), defaultNameOverrides...)
+}
+
+// ParseNameOverrides reads the contents of an overrides file
+func ParseNameOverrides(data []byte) (*NameOverrides, error) {
+	o := &NameOverrides{}
+	if err := json.Unmarshal(data, o); err != nil {
+		return nil, err
+	}
+	if o.Version < 1 || o.Version > nameOverridesVersion {
+		return nil, fmt.Errorf("version %d of the overrides file is not supported", o.Version)
+	}
+	for _, r := range o.NameRules {
+		if r.From == "" {
+			return nil, errors.New("name rules need the text to replace")
+		}
+	}
+
+	o.retired = make(map[int]bool)
+	for _, g := range o.RetiredCards {
+		for _, id := range g.Cards {
+			o.retired[id] = true
+		}
+	}
+	o.newCards = make(map[int]bool)
+	for _, g := range o.NewCards {
+		for _, id := range g.Cards {
+			o.newCards[id] = true
+		}
+	}
+	o.names = make(map[int]string, len(o.CharacterNames))
+	for _, cn := range o.CharacterNames {
+		o.names[cn.CharacterID] = cn.Name
+	}
+	return o, nil
+}
+
+// NameOverridesPath where the overrides file for a data location is. The file in the data location
+// is used before the one next to the program. Returns "" if neither exists.
+func NameOverridesPath(dataPath string) string {
+	paths := []string{filepath.Join(dataPath, NameOverridesFile)}
+	if exe, err := os.Executable(); err == nil {
+		paths = append(paths, filepath.Join(filepath.Dir(exe), NameOverridesFile))
+	}
+	for _, p := range paths {
+		if info, err := os.Stat(p); err == nil && !info.IsDir() {
+			return p
+		}
+	}
+	return ""
+}
+
+// ReadNameOverrides reads the overrides file for a data location, or the built in overrides if there is none
+func ReadNameOverrides(dataPath string) (*NameOverrides, error) {
+	file := NameOverridesPath(dataPath)
+	if file == "" {
+		return DefaultNameOverrides(), nil
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	o, err := ParseNameOverrides(data)
+	if err != nil {
+		return nil, errors.New(file + ": " + err.Error())
+	}
+	o.File = file
+	return o, nil
+}
+
+// SaveNameOverrides checks the contents of an overrides file and writes it to the file the dataset
+// overrides were read from, or to the data location if the built in overrides are used.
+// Returns the file that was written. The dataset needs to be read again to use the changes.
+func (d *Dataset) SaveNameOverrides(data []byte) (string, error) {
+	if _, err := ParseNameOverrides(data); err != nil {
+		return "", err
+	}
+	file := filepath.Join(d.FilePath, NameOverridesFile)
+	if d.Overrides != nil && d.Overrides.File != "" {
+		file = d.Overrides.File
+	}
+	if err := ioutil.WriteFile(file, data, 0644); err != nil {
+		return "", err
+	}
+	return file, nil
+}
+
+// IsRetired true if the card is listed as an old card that is not available anymore
+func (o *NameOverrides) IsRetired(cardID int) bool {
+	return o.retired[cardID]
+}
+
+// IsNew true if the card is listed as a new card with the same name as an old card
+func (o *NameOverrides) IsNew(cardID int) bool {
+	return o.newCards[cardID]
+}
+
+// CharacterName the name used for the cards of a character instead of the name from the string files
+func (o *NameOverrides) CharacterName(charID int) (string, bool) {
+	name, ok := o.names[charID]
+	return name, ok
+}
+
+// CardName cleans up a card name from the string files to match the wiki
+func (o *NameOverrides) CardName(name string, card *Card) string {
+	ret, ok := o.CharacterName(card.CardCharaID)
+	if !ok {
+		ret = strings.Title(strings.ToLower(name))
+		for _, r := range o.NameRules {
+			if r.BeforeCharacter == 0 || card.CardCharaID < r.BeforeCharacter {
+				ret = strings.ReplaceAll(ret, r.From, r.To)
+			}
+		}
+	}
+	if o.IsRetired(card.ID) {
+		ret += " (Old)"
+	} else if o.IsNew(card.ID) {
+		ret += " (New)"
+	}
+	return ret
+}
+
+// nameOverrides the overrides used by the dataset
+func (d *Dataset) nameOverrides() *NameOverrides {
+	if d == nil || d.Overrides == nil {
+		return builtinNameOverrides
+	}
+	return d.Overrides
+}
+
+// ValidateNameOverrides lists the overrides that do not point at a card or character in the data
+func (d *Dataset) ValidateNameOverrides(o *NameOverrides) []string {
+	ret := make([]string, 0)
+	checkCards := func(kind string, groups []CardGroup) {
+		for _, g := range groups {
+			for _, id := range g.Cards {
+				if c := d.CardScan(id); c == nil || c.CardCharaID == 0 {
+					ret = append(ret, fmt.Sprintf("%s card %d (%s) does not exist", kind, id, g.Comment))
+				}
+			}
+		}
+	}
+	checkCards("Retired", o.RetiredCards)
+	checkCards("New", o.NewCards)
+	for _, g := range o.NewCards {
+		for _, id := range g.Cards {
+			if o.retired[id] {
+				ret = append(ret, fmt.Sprintf("Card %d is listed as both retired and new", id))
+			}
+		}
+	}
+
+	seen := make(map[int]bool, len(o.CharacterNames))
+	for _, cn := range o.CharacterNames {
+		if d.CardCharacterScan(cn.CharacterID) == nil {
+			ret = append(ret, fmt.Sprintf("Character %d for the name '%s' does not exist", cn.CharacterID, cn.Name))
+		}
+		if seen[cn.CharacterID] {
+			ret = append(ret, fmt.Sprintf("Character %d has more than one name", cn.CharacterID))
+		}
+		seen[cn.CharacterID] = true
+	}
+	return ret
+}
@@ -0,0 +1,44 @@
+package vc
+
+import "testing"
+
+func TestDefaultNameOverrides(t *testing.T) {
+	o := DefaultNameOverrides()
+	tests := []struct {
+		name string
+		card Card
+		want string
+	}{
+		{"KNIGHT OF THE WIND'S SWORD", Card{ID: 10, CardCharaID: 100}, "Knight of the Wind's Sword"},
+		{"KNIGHT OF THE WIND", Card{ID: 10, CardCharaID: 1450}, "Knight Of The Wind"},
+		{"GOLD/SILVER (SR)", Card{ID: 10, CardCharaID: 2000}, "Gold Silver (SR)"},
+		{"KNIGHT", Card{ID: 1, CardCharaID: 1}, "Knight (Old)"},
+		{"SPINNER", Card{ID: 4721, CardCharaID: 3000}, "Spinner (New)"},
+		{"KUNG FU MASTER", Card{ID: 10, CardCharaID: 62}, "Kung-Fu Master"},
+	}
+	for _, test := range tests {
+		if got := o.CardName(test.name, &test.card); got != test.want {
+			t.Errorf("CardName(%q) = %q, expected %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestParseNameOverrides(t *testing.T) {
+	o, err := ParseNameOverrides([]byte(`{"version": 1, "characterNames": [{"character": 5, "name": "Five"}], "retiredCards": [{"cards": [7]}]}`))
+	if err != nil {
+		t.Fatalf("ParseNameOverrides returned an error: %s", err.Error())
+	}
+	if name, ok := o.CharacterName(5); !ok || name != "Five" {
+		t.Errorf("Unexpected character name: %q", name)
+	}
+	if !o.IsRetired(7) || o.IsRetired(1) {
+		t.Errorf("Only card 7 should be retired")
+	}
+
+	if _, err := ParseNameOverrides([]byte(`{"version": 2}`)); err == nil {
+		t.Errorf("A newer version should not be read")
+	}
+	if _, err := ParseNameOverrides([]byte(`{"version": 1, "nameRules": [{"to": "x"}]}`)); err == nil {
+		t.Errorf("A rule without the text to replace should not be read")
+	}
+}
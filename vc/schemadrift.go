@@ -0,0 +1,285 @@
+package vc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SchemaDrift the differences between the raw master data and what VFile reads from it
+type SchemaDrift struct {
+	// UnmodelledTables top level keys that are not read into VFile
+	UnmodelledTables []DriftTable `json:"unmodelledTables"`
+	// MissingTables tables that are read into VFile, but are not in the data
+	MissingTables []string `json:"missingTables"`
+	// UnknownFields record fields of the modelled tables that are not read
+	UnknownFields []DriftField `json:"unknownFields"`
+	// MissingFields modelled record fields that none of the records have
+	MissingFields []DriftField `json:"missingFields"`
+}
+
+// DriftTable a top level key of the master data
+type DriftTable struct {
+	Key string `json:"key"`
+	// Records the number of records if the value is a list
+	Records int    `json:"records"`
+	Sample  string `json:"sample"`
+}
+
+// DriftField a field of the records of a table
+type DriftField struct {
+	Table string `json:"table"`
+	Field string `json:"field"`
+	// Records the number of records with the field
+	Records int    `json:"records,omitempty"`
+	Sample  string `json:"sample,omitempty"`
+}
+
+// driftSampleLen how much of a value is kept as a sample
+const driftSampleLen = 80
+
+// IsEmpty true if the data matches the model
+func (s *SchemaDrift) IsEmpty() bool {
+	return len(s.UnmodelledTables) == 0 && len(s.MissingTables) == 0 && len(s.UnknownFields) == 0 && len(s.MissingFields) == 0
+}
+
+// Summary a one line description of the differences
+func (s *SchemaDrift) Summary() string {
+	parts := make([]string, 0, 4)
+	add := func(n int, what string) {
+		if n == 1 {
+			parts = append(parts, "1 "+what)
+		} else if n > 1 {
+			parts = append(parts, strconv.Itoa(n)+" "+what+"s")
+		}
+	}
+	add(len(s.UnmodelledTables), "unmodelled table")
+	add(len(s.MissingTables), "missing table")
+	add(len(s.UnknownFields), "unknown field")
+	add(len(s.MissingFields), "missing field")
+	if len(parts) == 0 {
+		return "the master data matches the data model"
+	}
+	return "the master data has " + strings.Join(parts, ", ")
+}
+
+// SchemaDrift compares the master data the dataset was read from with the data model
+func (d *Dataset) SchemaDrift() (*SchemaDrift, error) {
+	if d.MasterDataStr == "" {
+		return nil, errors.New("the master data has not been read")
+	}
+	return CompareSchema([]byte(d.MasterDataStr))
+}
+
+// CompareSchema compares the keys of the raw master data JSON with the json tags of VFile,
+// both for the top level tables and for the fields of their records
+func CompareSchema(raw []byte) (*SchemaDrift, error) {
+	top := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(raw, &top); err != nil {
+		return nil, err
+	}
+	drift := &SchemaDrift{
+		UnmodelledTables: make([]DriftTable, 0),
+		MissingTables:    make([]string, 0),
+		UnknownFields:    make([]DriftField, 0),
+		MissingFields:    make([]DriftField, 0),
+	}
+
+	tables := jsonFields(reflect.TypeOf(VFile{}))
+	for key, value := range top {
+		t, ok := tables.find(key)
+		if !ok {
+			table := DriftTable{Key: key, Sample: driftSample(value)}
+			list := make([]json.RawMessage, 0)
+			if json.Unmarshal(value, &list) == nil {
+				table.Records = len(list)
+				if len(list) > 0 {
+					table.Sample = driftSample(list[0])
+				}
+			}
+			drift.UnmodelledTables = append(drift.UnmodelledTables, table)
+			continue
+		}
+		compareRecords(drift, key, t, value)
+	}
+	for _, name := range tables.names {
+		if _, ok := findKey(top, name); !ok {
+			drift.MissingTables = append(drift.MissingTables, name)
+		}
+	}
+
+	sort.Slice(drift.UnmodelledTables, func(i, j int) bool { return drift.UnmodelledTables[i].Key < drift.UnmodelledTables[j].Key })
+	sort.Strings(drift.MissingTables)
+	sortFields := func(fields []DriftField) {
+		sort.Slice(fields, func(i, j int) bool {
+			if fields[i].Table != fields[j].Table {
+				return fields[i].Table < fields[j].Table
+			}
+			return fields[i].Field < fields[j].Field
+		})
+	}
+	sortFields(drift.UnknownFields)
+	sortFields(drift.MissingFields)
+	return drift, nil
+}
+
+// compareRecords compares the records of a table, or a single object, with the type they are read into
+func compareRecords(drift *SchemaDrift, table string, t reflect.Type, value json.RawMessage) {
+	recordType := t
+	if t.Kind() == reflect.Slice {
+		recordType = t.Elem()
+	}
+	for recordType.Kind() == reflect.Ptr {
+		recordType = recordType.Elem()
+	}
+	if recordType.Kind() != reflect.Struct || isJSONLeaf(recordType) {
+		return
+	}
+
+	records := make([]map[string]json.RawMessage, 0)
+	if t.Kind() == reflect.Slice {
+		if json.Unmarshal(value, &records) != nil {
+			return
+		}
+	} else {
+		record := make(map[string]json.RawMessage)
+		if json.Unmarshal(value, &record) != nil {
+			return
+		}
+		records = append(records, record)
+	}
+	if len(records) == 0 {
+		return
+	}
+
+	fields := jsonFields(recordType)
+	unknown := make(map[string]*DriftField)
+	present := make(map[string]bool)
+	for _, record := range records {
+		for key, v := range record {
+			if name, ok := fields.name(key); ok {
+				present[name] = true
+				continue
+			}
+			f, ok := unknown[key]
+			if !ok {
+				f = &DriftField{Table: table, Field: key}
+				unknown[key] = f
+			}
+			f.Records++
+			if f.Sample == "" && string(v) != "null" {
+				f.Sample = driftSample(v)
+			}
+		}
+	}
+	for _, f := range unknown {
+		drift.UnknownFields = append(drift.UnknownFields, *f)
+	}
+	for _, name := range fields.names {
+		if !present[name] {
+			drift.MissingFields = append(drift.MissingFields, DriftField{Table: table, Field: name})
+		}
+	}
+}
+
+// jsonFieldSet the JSON keys a struct is read from
+type jsonFieldSet struct {
+	names []string
+	types map[string]reflect.Type
+	// lower maps the lower case keys to the names, since encoding/json matches keys without case
+	lower map[string]string
+}
+
+func (s *jsonFieldSet) name(key string) (string, bool) {
+	if _, ok := s.types[key]; ok {
+		return key, true
+	}
+	name, ok := s.lower[strings.ToLower(key)]
+	return name, ok
+}
+
+func (s *jsonFieldSet) find(key string) (reflect.Type, bool) {
+	name, ok := s.name(key)
+	if !ok {
+		return nil, false
+	}
+	return s.types[name], true
+}
+
+// jsonFields the JSON keys of a struct, following the encoding/json rules for tags and embedded structs
+func jsonFields(t reflect.Type) *jsonFieldSet {
+	set := &jsonFieldSet{types: make(map[string]reflect.Type), lower: make(map[string]string)}
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name := strings.Split(tag, ",")[0]
+			ft := f.Type
+			if f.Anonymous && name == "" {
+				for ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				if ft.Kind() == reflect.Struct {
+					walk(ft)
+					continue
+				}
+			}
+			if f.PkgPath != "" {
+				// unexported
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			if _, ok := set.types[name]; ok {
+				continue
+			}
+			set.names = append(set.names, name)
+			set.types[name] = f.Type
+			set.lower[strings.ToLower(name)] = name
+		}
+	}
+	walk(t)
+	sort.Strings(set.names)
+	return set
+}
+
+// isJSONLeaf true for types that read themselves, like Timestamp
+func isJSONLeaf(t reflect.Type) bool {
+	unmarshaler := reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	return t.Implements(unmarshaler) || reflect.PtrTo(t).Implements(unmarshaler)
+}
+
+func findKey(m map[string]json.RawMessage, name string) (json.RawMessage, bool) {
+	if v, ok := m[name]; ok {
+		return v, true
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// driftSample a short copy of a JSON value
+func driftSample(v json.RawMessage) string {
+	var b bytes.Buffer
+	if json.Compact(&b, v) != nil {
+		b.Reset()
+		b.Write(v)
+	}
+	s := b.String()
+	if len(s) > driftSampleLen {
+		s = s[:driftSampleLen] + "..."
+	}
+	return s
+}
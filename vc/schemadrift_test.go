@@ -0,0 +1,49 @@
+package vc
+
+import "testing"
+
+func TestCompareSchema(t *testing.T) {
+	raw := []byte(`{
+		"version": 1,
+		"cards": [{"_id": 1, "name": "a", "new_field": 5}, {"_id": 2, "NEW_FIELD": null}],
+		"new_table": [{"_id": 1}, {"_id": 2}],
+		"defs_tune": {"unknown_tune": "x"}
+	}`)
+	drift, err := CompareSchema(raw)
+	if err != nil {
+		t.Fatalf("CompareSchema returned an error: %s", err.Error())
+	}
+	if len(drift.UnmodelledTables) != 1 || drift.UnmodelledTables[0].Key != "new_table" || drift.UnmodelledTables[0].Records != 2 {
+		t.Errorf("Unexpected unmodelled tables: %v", drift.UnmodelledTables)
+	}
+
+	found := false
+	for _, f := range drift.UnknownFields {
+		if f.Table == "cards" && f.Field == "new_field" {
+			found = f.Records == 1 && f.Sample == "5"
+		}
+		if f.Table == "cards" && f.Field == "_id" {
+			t.Errorf("A modelled field was reported as unknown")
+		}
+	}
+	if !found {
+		t.Errorf("The unknown card field was not reported: %v", drift.UnknownFields)
+	}
+
+	missingCards, missingCardName := false, false
+	for _, name := range drift.MissingTables {
+		missingCards = missingCards || name == "cards"
+	}
+	for _, f := range drift.MissingFields {
+		missingCardName = missingCardName || (f.Table == "cards" && f.Field == "name")
+		if f.Table == "cards" && f.Field == "_id" {
+			t.Errorf("A field that is in the data was reported as missing")
+		}
+	}
+	if missingCards || missingCardName {
+		t.Errorf("Fields and tables in the data were reported as missing")
+	}
+	if len(drift.MissingTables) == 0 || drift.IsEmpty() {
+		t.Errorf("The tables that are not in the data should be reported")
+	}
+}
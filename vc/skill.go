@@ -13,6 +13,7 @@ import (
 // MsgSkillDesc_en.strb - shown on the card
 // MsgSkillFire_en.strb - used during battle
 type Skill struct {
+	datasetRef
 	ID           int `json:"_id"`            // skill id
 	LevelType    int `json:"level_type"`     // level type for skill upgrade costs
 	Type         int `json:"_type"`          // skill type
@@ -66,6 +67,9 @@ type Skill struct {
 	Fire              string `json:"fire"`        // fire text from strings file
 	_skillLevels      []SkillLevel
 	_chainFrontSkills []Skill
+
+	// Translations text from the string files in each loaded language pack
+	Translations Translations `json:"-"`
 }
 
 // SkillLevel information
@@ -83,7 +87,7 @@ type CustomSkillLevel struct {
 
 // SkillCostIncrementPattern increment pattern for custom skill costs vs card levels
 type SkillCostIncrementPattern struct {
-	ID                int `json:""`
+	ID                int `json:"_id"`
 	CardLevelInterval int `json:"card_level_interval"`
 	Increment         int `json:"increment"`
 	Max               int `json:"max"`
@@ -95,16 +99,23 @@ func (s *Skill) ChainFrontSkills() []Skill {
 		return make([]Skill, 0)
 	}
 
-	if s._chainFrontSkills == nil {
-		s._chainFrontSkills = make([]Skill, 0)
+	lock := s.cacheLock()
+	lock.RLock()
+	skills := s._chainFrontSkills
+	lock.RUnlock()
+	if skills == nil {
+		skills = make([]Skill, 0)
 		for _, cfsid := range s.ChainFrontSkillIDs {
-			sl := SkillScan(cfsid)
+			sl := s.ds.SkillScan(cfsid)
 			if sl != nil {
-				s._chainFrontSkills = append(s._chainFrontSkills, *sl)
+				skills = append(skills, *sl)
 			}
 		}
+		lock.Lock()
+		s._chainFrontSkills = skills
+		lock.Unlock()
 	}
-	return s._chainFrontSkills
+	return skills
 }
 
 //ReceiptItem Receipt Item
@@ -112,7 +123,7 @@ func (s *Skill) ReceiptItem() *Item {
 	if s == nil {
 		return nil
 	}
-	return ItemScan(s.ReceiptItemID)
+	return s.ds.ItemScan(s.ReceiptItemID)
 }
 
 // Expires true if the skill has an expiration date
@@ -207,15 +218,22 @@ func (s *Skill) Levels() []SkillLevel {
 		return nil
 	}
 
-	if s._skillLevels == nil {
-		s._skillLevels = make([]SkillLevel, 0)
-		for _, sl := range Data.SkillLevels {
+	lock := s.cacheLock()
+	lock.RLock()
+	levels := s._skillLevels
+	lock.RUnlock()
+	if levels == nil {
+		levels = make([]SkillLevel, 0)
+		for _, sl := range s.ds.Data.SkillLevels {
 			if sl.LevelType == s.LevelType {
-				s._skillLevels = append(s._skillLevels, sl)
+				levels = append(levels, sl)
 			}
 		}
+		lock.Lock()
+		s._skillLevels = levels
+		lock.Unlock()
 	}
-	return s._skillLevels
+	return levels
 }
 
 // TargetScope scope of the target (enemy or allies)
@@ -247,12 +265,12 @@ func (s *Skill) TargetLogic() string {
 }
 
 // SkillScan searches for a skill by ID
-func SkillScan(id int) *Skill {
+func (d *Dataset) SkillScan(id int) *Skill {
 	if id > 0 {
-		l := len(Data.Skills)
-		i := sort.Search(l, func(i int) bool { return Data.Skills[i].ID >= id })
-		if i >= 0 && i < l && Data.Skills[i].ID == id {
-			return &(Data.Skills[i])
+		l := len(d.Data.Skills)
+		i := sort.Search(l, func(i int) bool { return d.Data.Skills[i].ID >= id })
+		if i >= 0 && i < l && d.Data.Skills[i].ID == id {
+			return &(d.Data.Skills[i])
 		}
 	}
 	return nil
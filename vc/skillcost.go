@@ -0,0 +1,111 @@
+package vc
+
+// MaxSkillLevel the highest level a skill can be raised to with medals
+const MaxSkillLevel = 10
+
+// SkillMedalCost the medals needed to raise one of a card's skills between two levels
+type SkillMedalCost struct {
+	SkillID   int    `json:"skillId"`
+	Name      string `json:"name"`
+	LevelType int    `json:"levelType"`
+	FromLevel int    `json:"fromLevel"`
+	ToLevel   int    `json:"toLevel"`
+	Medals    int    `json:"medals"`
+	// Levels the medals needed at each level on the way
+	Levels []SkillLevel `json:"levels"`
+}
+
+// CustomSkillCostRange the custom skill cost of a card for a range of card levels
+type CustomSkillCostRange struct {
+	FromLevel int `json:"fromLevel"`
+	ToLevel   int `json:"toLevel"`
+	Cost      int `json:"cost"`
+}
+
+// MedalCost the medals needed to raise the skill from one level to another. Each skill_level
+// row is the medals needed to go from that level to the next one
+func (s *Skill) MedalCost(from, to int) (medals int) {
+	for _, l := range s.Levels() {
+		if l.Level >= from && l.Level < to {
+			medals += l.Medal
+		}
+	}
+	return
+}
+
+// SkillMedalCosts the medals needed to raise each of the card's skills from a level to MaxSkillLevel
+func (c *Card) SkillMedalCosts(from int) []SkillMedalCost {
+	if from < 1 {
+		from = 1
+	}
+	ret := make([]SkillMedalCost, 0, 3)
+	for _, s := range []*Skill{c.Skill1(), c.Skill2(), c.Skill3()} {
+		if s == nil {
+			continue
+		}
+		cost := SkillMedalCost{
+			SkillID:   s.ID,
+			Name:      s.Name,
+			LevelType: s.LevelType,
+			FromLevel: from,
+			ToLevel:   MaxSkillLevel,
+			Medals:    s.MedalCost(from, MaxSkillLevel),
+			Levels:    make([]SkillLevel, 0),
+		}
+		for _, l := range s.Levels() {
+			if l.Level >= from && l.Level < MaxSkillLevel {
+				cost.Levels = append(cost.Levels, l)
+			}
+		}
+		ret = append(ret, cost)
+	}
+	return ret
+}
+
+// CustomSkillCostPattern the pattern the card's custom skill cost grows by as the card levels up
+func (c *Card) CustomSkillCostPattern() *SkillCostIncrementPattern {
+	if c.CustomSkillCostIncPattern <= 0 {
+		return nil
+	}
+	for i, p := range c.ds.Data.SkillCostIncrementPatterns {
+		if p.ID == c.CustomSkillCostIncPattern {
+			return &c.ds.Data.SkillCostIncrementPatterns[i]
+		}
+	}
+	return nil
+}
+
+// CustomSkillCostAt the cost of a custom skill on the card at a card level. The cost starts at
+// custom_skill_cost_1 and goes up by the pattern's increment every card_level_interval levels
+// after level 1, up to the pattern's max
+func (c *Card) CustomSkillCostAt(level int) int {
+	cost := c.CustomSkillCost
+	p := c.CustomSkillCostPattern()
+	if p == nil || p.CardLevelInterval <= 0 || level <= 1 {
+		return cost
+	}
+	cost += (level - 1) / p.CardLevelInterval * p.Increment
+	if p.Max > 0 && cost > p.Max {
+		cost = p.Max
+	}
+	return cost
+}
+
+// CustomSkillCostTable the custom skill cost of the card from level 1 to its max level, with the
+// levels that have the same cost grouped together
+func (c *Card) CustomSkillCostTable() []CustomSkillCostRange {
+	ret := make([]CustomSkillCostRange, 0)
+	maxLevel := c.MaxLevel()
+	if maxLevel < 1 {
+		maxLevel = 1
+	}
+	for level := 1; level <= maxLevel; level++ {
+		cost := c.CustomSkillCostAt(level)
+		if l := len(ret); l > 0 && ret[l-1].Cost == cost {
+			ret[l-1].ToLevel = level
+			continue
+		}
+		ret = append(ret, CustomSkillCostRange{FromLevel: level, ToLevel: level, Cost: cost})
+	}
+	return ret
+}
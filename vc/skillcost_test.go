@@ -0,0 +1,45 @@
+package vc
+
+import "testing"
+
+func TestSkillCost(t *testing.T) {
+	d := NewDataset("", "en")
+	d.Data.CardRarities = []CardRarity{{ID: 1, MaxCardLevel: 30}}
+	d.Data.Cards = CardList{
+		{ID: 1, CardRareID: 1, SkillID1: 1, SkillID2: 2, CustomSkillCost: 3, CustomSkillCostIncPattern: 1},
+	}
+	d.Data.Skills = []Skill{{ID: 1, LevelType: 1}, {ID: 2, LevelType: 2}}
+	d.Data.SkillLevels = []SkillLevel{
+		{ID: 1, LevelType: 1, Level: 1, Medal: 100},
+		{ID: 2, LevelType: 1, Level: 2, Medal: 200},
+		{ID: 3, LevelType: 1, Level: 9, Medal: 900},
+		{ID: 4, LevelType: 1, Level: 10, Medal: 1000},
+		{ID: 5, LevelType: 2, Level: 2, Medal: 50},
+	}
+	d.Data.SkillCostIncrementPatterns = []SkillCostIncrementPattern{{ID: 1, CardLevelInterval: 10, Increment: 2, Max: 6}}
+	d.link()
+
+	c := d.CardScan(1)
+	costs := c.SkillMedalCosts(2)
+	if len(costs) != 2 {
+		t.Fatalf("Expected 2 skills, got %d", len(costs))
+	}
+	// level 10 is the max so its row is not counted
+	if costs[0].Medals != 1100 || len(costs[0].Levels) != 2 {
+		t.Errorf("Unexpected medals for the first skill: %v", costs[0])
+	}
+	if costs[1].Medals != 50 {
+		t.Errorf("Unexpected medals for the second skill: %v", costs[1])
+	}
+
+	table := c.CustomSkillCostTable()
+	expected := []CustomSkillCostRange{{1, 10, 3}, {11, 20, 5}, {21, 30, 6}}
+	if len(table) != len(expected) {
+		t.Fatalf("Unexpected custom skill cost table: %v", table)
+	}
+	for i := range expected {
+		if table[i] != expected[i] {
+			t.Errorf("Unexpected custom skill cost range %d: %v, expected %v", i, table[i], expected[i])
+		}
+	}
+}
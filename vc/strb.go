@@ -0,0 +1,240 @@
+package vc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+)
+
+// Layout of a .strb string file:
+// 4 bytes for the signature (STRB)
+// 4 bytes for the number of entries in the index (little endian)
+// the index: one 4 byte offset (little endian) for each entry, pointing at the start of a string
+// the string pool: "null" followed by the strings, each ending with a 0 byte
+//
+// Index offsets are counted from the start of the string pool or from the start of the file.
+// Files with an index that does not match this layout are still read, and the index is written back unchanged
+// as long as the strings are not changed.
+
+// strbNull the first string of the pool, used by index entries that have no text
+var strbNull = []byte("null\000")
+
+// Index offset bases
+const (
+	strbOffsetFromPool = iota
+	strbOffsetFromFile
+)
+
+// StringFile a .strb string file
+type StringFile struct {
+	// Strings the strings in the order they are stored, without the leading "null"
+	Strings []string
+	// Index for each index entry, the position in Strings it points at, or -1 for "null"
+	Index []int
+
+	// offsetBase where index offsets are counted from
+	offsetBase int
+	// count the entry count from the header when the index could not be read
+	count uint32
+	// rawIndex the index as it was read when it could not be matched to the strings
+	rawIndex []byte
+	// rawStrings the strings the raw index points at
+	rawStrings []string
+}
+
+// ReadStrb reads a .strb string file
+func ReadStrb(filename string) (*StringFile, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	sf, err := ParseStrb(data)
+	if err != nil {
+		return nil, errors.New(err.Error() + ": " + filename)
+	}
+	return sf, nil
+}
+
+// ParseStrb parses the contents of a .strb string file
+func ParseStrb(data []byte) (*StringFile, error) {
+	if len(data) < 8 || !bytes.Equal(data[:4], []byte("STRB")) {
+		return nil, errors.New("not a string file")
+	}
+	count := binary.LittleEndian.Uint32(data[4:8])
+
+	// the string pool follows the index. If "null" is not there, look for the seperator between the index and the strings
+	poolStart := -1
+	if pos := 8 + uint64(count)*4; pos+uint64(len(strbNull)) <= uint64(len(data)) && bytes.Equal(data[pos:pos+uint64(len(strbNull))], strbNull) {
+		poolStart = int(pos)
+	}
+	for pos := 8; poolStart < 0 && pos < len(data); {
+		end := bytes.IndexByte(data[pos:], 0)
+		if end < 0 {
+			break
+		}
+		if bytes.Equal(data[pos:pos+end+1], strbNull) {
+			poolStart = pos
+			break
+		}
+		pos += end + 1
+	}
+	if poolStart < 0 {
+		return nil, errors.New("string pool not found")
+	}
+
+	// read the strings, remembering where each one starts in the pool
+	sf := &StringFile{Strings: make([]string, 0)}
+	starts := map[int]int{0: -1}
+	pos := poolStart + len(strbNull)
+	for pos < len(data) {
+		end := bytes.IndexByte(data[pos:], 0)
+		if end < 0 {
+			// the last string has no terminator
+			end = len(data) - pos
+		}
+		starts[pos-poolStart] = len(sf.Strings)
+		sf.Strings = append(sf.Strings, string(data[pos:pos+end]))
+		pos += end + 1
+	}
+
+	rawIndex := data[8:poolStart]
+	if sf.readIndex(rawIndex, count, poolStart, starts) {
+		return sf, nil
+	}
+	sf.count = count
+	sf.rawIndex = append([]byte(nil), rawIndex...)
+	sf.rawStrings = append([]string(nil), sf.Strings...)
+	return sf, nil
+}
+
+// readIndex matches the index offsets to the strings. Returns false if the index does not point at the strings.
+func (sf *StringFile) readIndex(rawIndex []byte, count uint32, poolStart int, starts map[int]int) bool {
+	if uint64(len(rawIndex)) != uint64(count)*4 {
+		return false
+	}
+	offsets := make([]int, count)
+	for i := range offsets {
+		offsets[i] = int(binary.LittleEndian.Uint32(rawIndex[i*4:]))
+	}
+	for _, base := range []int{strbOffsetFromPool, strbOffsetFromFile} {
+		index := make([]int, 0, len(offsets))
+		for _, offset := range offsets {
+			if base == strbOffsetFromFile {
+				offset -= poolStart
+			}
+			s, ok := starts[offset]
+			if !ok {
+				break
+			}
+			index = append(index, s)
+		}
+		if len(index) == len(offsets) {
+			sf.Index = index
+			sf.offsetBase = base
+			return true
+		}
+	}
+	return false
+}
+
+// Offsets the string at each offset of the index, as the offsets are written by WriteTo
+func (sf *StringFile) Offsets() map[uint32]string {
+	ret := make(map[uint32]string, len(sf.Index))
+	offsets := sf.offsets()
+	for i, s := range sf.Index {
+		if s < 0 {
+			ret[offsets[i]] = "null"
+		} else {
+			ret[offsets[i]] = sf.Strings[s]
+		}
+	}
+	return ret
+}
+
+// offsets the offset of each index entry for the current strings
+func (sf *StringFile) offsets() []uint32 {
+	starts := make([]uint32, len(sf.Strings))
+	pos := uint32(len(strbNull))
+	for i, s := range sf.Strings {
+		starts[i] = pos
+		pos += uint32(len(s)) + 1
+	}
+	base := uint32(0)
+	if sf.offsetBase == strbOffsetFromFile {
+		base = 8 + uint32(len(sf.Index))*4
+	}
+	ret := make([]uint32, len(sf.Index))
+	for i, s := range sf.Index {
+		if s < 0 {
+			ret[i] = base
+		} else {
+			ret[i] = base + starts[s]
+		}
+	}
+	return ret
+}
+
+// WriteTo writes the string file. The index offsets are updated to match the strings.
+// An index that could not be read is written unchanged, so the strings it points at can not be changed.
+func (sf *StringFile) WriteTo(w io.Writer) (int64, error) {
+	b, err := sf.encode()
+	if err != nil {
+		return 0, err
+	}
+	return b.WriteTo(w)
+}
+
+// encode the contents of the string file
+func (sf *StringFile) encode() (*bytes.Buffer, error) {
+	for _, s := range sf.Index {
+		if s >= len(sf.Strings) {
+			return nil, errors.New("the index points past the last string")
+		}
+	}
+	for _, s := range sf.Strings {
+		if bytes.IndexByte([]byte(s), 0) >= 0 {
+			return nil, errors.New("strings can not contain a 0 byte")
+		}
+	}
+	if sf.rawIndex != nil && !stringsEqual(sf.Strings, sf.rawStrings) {
+		return nil, errors.New("the strings can not be changed, the index of the file could not be read")
+	}
+	var b bytes.Buffer
+	b.WriteString("STRB")
+	if sf.rawIndex != nil {
+		binary.Write(&b, binary.LittleEndian, sf.count)
+		b.Write(sf.rawIndex)
+	} else {
+		binary.Write(&b, binary.LittleEndian, uint32(len(sf.Index)))
+		binary.Write(&b, binary.LittleEndian, sf.offsets())
+	}
+	b.Write(strbNull)
+	for _, s := range sf.Strings {
+		b.WriteString(s)
+		b.WriteByte(0)
+	}
+	return &b, nil
+}
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Save writes the string file. The file is not touched if the contents can not be written.
+func (sf *StringFile) Save(filename string) error {
+	b, err := sf.encode()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, b.Bytes(), 0644)
+}
@@ -0,0 +1,130 @@
+package vc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// testStrb builds a string file with an index counted from the start of the string pool
+func testStrb(offsets []uint32, strs ...string) []byte {
+	var b bytes.Buffer
+	b.WriteString("STRB")
+	binary.Write(&b, binary.LittleEndian, uint32(len(offsets)))
+	binary.Write(&b, binary.LittleEndian, offsets)
+	b.WriteString("null\000")
+	for _, s := range strs {
+		b.WriteString(s)
+		b.WriteByte(0)
+	}
+	return b.Bytes()
+}
+
+func TestParseStrb(t *testing.T) {
+	// "null" is at 0, "Alpha" at 5, "Beta" at 11
+	data := testStrb([]uint32{5, 0, 11, 5}, "Alpha", "Beta")
+	sf, err := ParseStrb(data)
+	if err != nil {
+		t.Fatalf("ParseStrb returned an error: %s", err.Error())
+	}
+	if len(sf.Strings) != 2 || sf.Strings[0] != "Alpha" || sf.Strings[1] != "Beta" {
+		t.Errorf("Unexpected strings: %v", sf.Strings)
+	}
+	if len(sf.Index) != 4 || sf.Index[0] != 0 || sf.Index[1] != -1 || sf.Index[2] != 1 || sf.Index[3] != 0 {
+		t.Errorf("Unexpected index: %v", sf.Index)
+	}
+	offsets := sf.Offsets()
+	if offsets[5] != "Alpha" || offsets[11] != "Beta" || offsets[0] != "null" {
+		t.Errorf("Unexpected offsets: %v", offsets)
+	}
+
+	var out bytes.Buffer
+	if _, err := sf.WriteTo(&out); err != nil {
+		t.Fatalf("WriteTo returned an error: %s", err.Error())
+	}
+	if !bytes.Equal(out.Bytes(), data) {
+		t.Errorf("The file changed when written back:\n%q\n%q", out.Bytes(), data)
+	}
+
+	// changing a string moves the offsets of the strings after it
+	sf.Strings[0] = "Alphabet"
+	out.Reset()
+	sf.WriteTo(&out)
+	if want := testStrb([]uint32{5, 0, 14, 5}, "Alphabet", "Beta"); !bytes.Equal(out.Bytes(), want) {
+		t.Errorf("Unexpected file after a change:\n%q\n%q", out.Bytes(), want)
+	}
+}
+
+func TestParseStrbOffsetsFromFile(t *testing.T) {
+	// the pool starts after the 8 byte header and 2 index entries
+	data := testStrb([]uint32{16 + 5, 16 + 11}, "Alpha", "Beta")
+	sf, err := ParseStrb(data)
+	if err != nil {
+		t.Fatalf("ParseStrb returned an error: %s", err.Error())
+	}
+	if len(sf.Index) != 2 || sf.Index[0] != 0 || sf.Index[1] != 1 {
+		t.Errorf("Unexpected index: %v", sf.Index)
+	}
+	var out bytes.Buffer
+	sf.WriteTo(&out)
+	if !bytes.Equal(out.Bytes(), data) {
+		t.Errorf("The file changed when written back:\n%q\n%q", out.Bytes(), data)
+	}
+}
+
+func TestParseStrbUnknownIndex(t *testing.T) {
+	data := []byte("STRB\000\000\000\000idx\000null\000Alpha\000Beta\000")
+	sf, err := ParseStrb(data)
+	if err != nil {
+		t.Fatalf("ParseStrb returned an error: %s", err.Error())
+	}
+	if len(sf.Strings) != 2 || sf.Strings[1] != "Beta" {
+		t.Errorf("Unexpected strings: %v", sf.Strings)
+	}
+	var out bytes.Buffer
+	sf.WriteTo(&out)
+	if !bytes.Equal(out.Bytes(), data) {
+		t.Errorf("The index was not kept:\n%q\n%q", out.Bytes(), data)
+	}
+
+	if _, err := ParseStrb([]byte("STRB\000\000\000\000Alpha\000")); err == nil {
+		t.Errorf("A file without the string pool should not be read")
+	}
+}
+
+func TestParseStrbPoolAfterIndex(t *testing.T) {
+	// the index bytes look like "null", the pool is found from the entry count instead
+	data := []byte("STRB\002\000\000\000null\000\000\000\000null\000Alpha\000")
+	sf, err := ParseStrb(data)
+	if err != nil {
+		t.Fatalf("ParseStrb returned an error: %s", err.Error())
+	}
+	if len(sf.Strings) != 1 || sf.Strings[0] != "Alpha" {
+		t.Errorf("Unexpected strings: %q", sf.Strings)
+	}
+}
+
+func TestWriteStrbUnknownIndexChanged(t *testing.T) {
+	data := []byte("STRB\000\000\000\000idx\000null\000Alpha\000Beta\000")
+	sf, err := ParseStrb(data)
+	if err != nil {
+		t.Fatalf("ParseStrb returned an error: %s", err.Error())
+	}
+	// the kept index would point at the wrong strings
+	sf.Strings[0] = "Alphabet"
+	var out bytes.Buffer
+	if _, err := sf.WriteTo(&out); err == nil {
+		t.Errorf("Changed strings were written with an index that could not be read")
+	}
+
+	file := filepath.Join(t.TempDir(), "MsgCardName_en.strb")
+	writeTestFile(t, file, data)
+	if err := sf.Save(file); err == nil {
+		t.Errorf("Save did not return an error")
+	}
+	if saved, _ := ioutil.ReadFile(file); !bytes.Equal(saved, data) {
+		t.Errorf("The file was changed by a failed save: %q", saved)
+	}
+}
@@ -40,6 +40,7 @@ type GardenDebris struct { // garden_debris
 // Structure "structures" gives information about availability of for buildinds.
 // The names of the structions in this list match those in the MsgBuildingName_en.strb file
 type Structure struct { // structures
+	datasetRef
 	ID              int    `json:"_id"`
 	StructureTypeID int    `json:"structure_type_id"`
 	MaxLv           int    `json:"max_lv"`
@@ -65,6 +66,9 @@ type Structure struct { // structures
 	_levels         []StructureLevel
 	_numCosts       []StructureCost
 	_castleBonus    []CastleLevel
+
+	// Translations text from the string files in each loaded language pack
+	Translations Translations `json:"-"`
 }
 
 // TextureIDs gets the texture IDs for this structure
@@ -110,7 +114,7 @@ func (s *Structure) TextureIDs() []int {
 
 //GetImageData gets the images for the structure
 func (s *Structure) GetImageData() ([]BinImage, error) {
-	gardenBin := filepath.Join(FilePath, "garden", "map_01.bin")
+	gardenBin := filepath.Join(s.ds.FilePath, "garden", "map_01.bin")
 	texIds := s.TextureIDs()
 	if len(texIds) == 0 {
 		return make([]BinImage, 0), nil
@@ -129,6 +133,7 @@ func (s *Structure) GetImageData() ([]BinImage, error) {
 
 // StructureLevel structure_level lists the level for the available structures
 type StructureLevel struct { // structure_level
+	datasetRef
 	ID            int            `json:"_id"`
 	StructureID   int            `json:"structure_id"`
 	Level         int            `json:"level"`
@@ -246,31 +251,45 @@ type SpecialEffect struct { // special_effect
 
 // Levels of a structure
 func (s *Structure) Levels() []StructureLevel {
-	if s._levels == nil {
-		s._levels = make([]StructureLevel, 0)
-		for _, l := range Data.StructureLevels {
+	lock := s.cacheLock()
+	lock.RLock()
+	levels := s._levels
+	lock.RUnlock()
+	if levels == nil {
+		levels = make([]StructureLevel, 0)
+		for _, l := range s.ds.Data.StructureLevels {
 			if l.StructureID == s.ID {
 				l.cacheResource()      // cache off the resource level for later
 				l.cacheBank()          // cache off the bank level for later
 				l.cacheSpecialEffect() // cache off the bank level for later
-				s._levels = append(s._levels, l)
+				levels = append(levels, l)
 			}
 		}
+		lock.Lock()
+		s._levels = levels
+		lock.Unlock()
 	}
-	return s._levels
+	return levels
 }
 
 // PurchaseCosts costs of purchasing the structure
 func (s *Structure) PurchaseCosts() []StructureCost {
-	if s._numCosts == nil {
-		s._numCosts = make([]StructureCost, 0)
-		for _, p := range Data.StructureNumCosts {
+	lock := s.cacheLock()
+	lock.RLock()
+	costs := s._numCosts
+	lock.RUnlock()
+	if costs == nil {
+		costs = make([]StructureCost, 0)
+		for _, p := range s.ds.Data.StructureNumCosts {
 			if p.StructureID == s.ID {
-				s._numCosts = append(s._numCosts, p)
+				costs = append(costs, p)
 			}
 		}
+		lock.Lock()
+		s._numCosts = costs
+		lock.Unlock()
 	}
-	return s._numCosts
+	return costs
 }
 
 // IsResource returns true if the building is a resource building
@@ -295,15 +314,22 @@ func (s *Structure) IsBank() bool {
 
 // CastleBonuses bonuses obtained by leveling up the castle
 func (s *Structure) CastleBonuses() []CastleLevel {
-	if s._castleBonus == nil {
-		s._castleBonus = make([]CastleLevel, 0)
-		for _, cl := range Data.CastleLevels {
+	lock := s.cacheLock()
+	lock.RLock()
+	bonuses := s._castleBonus
+	lock.RUnlock()
+	if bonuses == nil {
+		bonuses = make([]CastleLevel, 0)
+		for _, cl := range s.ds.Data.CastleLevels {
 			if cl.StructureID == s.ID {
-				s._castleBonus = append(s._castleBonus, cl)
+				bonuses = append(bonuses, cl)
 			}
 		}
+		lock.Lock()
+		s._castleBonus = bonuses
+		lock.Unlock()
 	}
-	return s._castleBonus
+	return bonuses
 }
 
 // MaxQty Number a player can own
@@ -323,9 +349,9 @@ func (s *Structure) MaxQty() int {
 
 func (l *StructureLevel) cacheResource() {
 	if l.Resource == nil {
-		for i, sr := range Data.ResourceLevels {
+		for i, sr := range l.ds.Data.ResourceLevels {
 			if sr.StructureID == l.StructureID && sr.Level == l.Level {
-				l.Resource = &(Data.ResourceLevels[i])
+				l.Resource = &(l.ds.Data.ResourceLevels[i])
 				break
 			}
 		}
@@ -333,9 +359,9 @@ func (l *StructureLevel) cacheResource() {
 }
 func (l *StructureLevel) cacheBank() {
 	if l.Bank == nil {
-		for i, br := range Data.BankLevels {
+		for i, br := range l.ds.Data.BankLevels {
 			if br.StructureID == l.StructureID && br.Level == l.Level {
-				l.Bank = &(Data.BankLevels[i])
+				l.Bank = &(l.ds.Data.BankLevels[i])
 				break
 			}
 		}
@@ -343,9 +369,9 @@ func (l *StructureLevel) cacheBank() {
 }
 func (l *StructureLevel) cacheSpecialEffect() {
 	if l.SpecialEffect == nil {
-		for i, br := range Data.SpecialEffects {
+		for i, br := range l.ds.Data.SpecialEffects {
 			if br.StructureID == l.StructureID && br.Level == l.Level {
-				l.SpecialEffect = &(Data.SpecialEffects[i])
+				l.SpecialEffect = &(l.ds.Data.SpecialEffects[i])
 				break
 			}
 		}
@@ -363,14 +389,14 @@ func (sr *ResourceLevel) FillTime() time.Duration {
 }
 
 // StructureScan searches for a structure by ID
-func StructureScan(id int) *Structure {
+func (d *Dataset) StructureScan(id int) *Structure {
 	if id > 0 {
-		if id < len(Data.Structures) && Data.Structures[id-1].ID == id {
-			return &Data.Structures[id-1]
+		if id < len(d.Data.Structures) && d.Data.Structures[id-1].ID == id {
+			return &d.Data.Structures[id-1]
 		}
-		for k, val := range Data.Structures {
+		for k, val := range d.Data.Structures {
 			if val.ID == id {
-				return &(Data.Structures[k])
+				return &(d.Data.Structures[k])
 			}
 		}
 	}
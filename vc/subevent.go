@@ -8,6 +8,7 @@ import (
 
 //SubEvent fields on all new sub-event types
 type SubEvent struct {
+	datasetRef
 	ID                             int       `json:"_id"`
 	ScenarioID                     int       `json:"scenario_id"`
 	RankingRewardGroupID           int       `json:"ranking_reward_group_id"`
@@ -28,7 +29,7 @@ func (se *SubEvent) GetURL() string {
 	if se == nil {
 		return ""
 	}
-	url := URLSchemeScan(se.URLSchemeID)
+	url := se.ds.URLSchemeScan(se.URLSchemeID)
 	if url == nil {
 		return ""
 	}
@@ -40,7 +41,7 @@ func (se *SubEvent) GetScenarioHtml(eventTitle, eventType string) (ret string, e
 	}
 	// string file location vcRoot/scenario/MsgScenarioString_<lang>.strb
 	var lines []string
-	lines, err = ReadStringFileFilter(filepath.Join(FilePath, "scenario", eventType, "MsgScenarioString_"+LangPack+".strb"), false)
+	lines, err = ReadStringFileFilter(filepath.Join(se.ds.FilePath, "scenario", eventType, "MsgScenarioString_"+se.ds.LangPack+".strb"), false)
 	if err != nil {
 		return
 	}
@@ -6,6 +6,7 @@ import (
 
 // ThorEvent mst_thorhammer
 type ThorEvent struct {
+	datasetRef
 	ID                                     int       `json:"_id"`
 	PublicStartDatetime                    Timestamp `json:"public_start_datetime"`
 	PublicEndDatetime                      Timestamp `json:"public_end_datetime"`
@@ -16,6 +17,9 @@ type ThorEvent struct {
 	PointRewardGroupID                     int       `json:"jump_button"`
 	Title                                  string    `json:""` // MsgThorhammerTitle_en.strb
 	_archwitches                           []ThorKing
+
+	// Translations text from the string files in each loaded language pack
+	Translations Translations `json:"-"`
 }
 
 // ThorKing mst_thorhammer_king
@@ -61,21 +65,75 @@ type ThorReward struct {
 
 // Archwitches for the event
 func (e *ThorEvent) Archwitches() []ThorKing {
-	if e._archwitches == nil {
+	lock := e.cacheLock()
+	lock.RLock()
+	aws := e._archwitches
+	lock.RUnlock()
+	if aws == nil {
 		// picks only unique Cards for the event
 		set := make(map[int]ThorKing)
-		for _, a := range Data.ThorKings {
-			if e.ID == a.ThorhammerID {
-				set[a.ID] = a
-			}
+		for _, a := range e.ds.lookups().thorKings[e.ID] {
+			set[a.ID] = *a
 		}
 
-		e._archwitches = make([]ThorKing, 0)
+		aws = make([]ThorKing, 0)
 		for _, a := range set {
-			e._archwitches = append(e._archwitches, a)
+			aws = append(aws, a)
+		}
+		sort.Slice(aws, func(i, j int) bool { return aws[i].ID < aws[j].ID })
+		lock.Lock()
+		e._archwitches = aws
+		lock.Unlock()
+	}
+	return aws
+}
+
+// Event the event the Thor event is held during
+func (e *ThorEvent) Event() *Event {
+	for k, ev := range e.ds.Data.Events {
+		if ev.StartDatetime == e.PublicStartDatetime && ev.EndDatetime == e.PublicEndDatetime {
+			return &(e.ds.Data.Events[k])
+		}
+	}
+	return nil
+}
+
+// RankRewards ranking rewards for the event, ordered by rank
+func (e *ThorEvent) RankRewards() []ThorReward {
+	return thorRewards(e.ds.Data.ThorRankRewards, e.RankingRewardGroupID)
+}
+
+// PointRewards point rewards for the event, ordered by the points needed
+func (e *ThorEvent) PointRewards() []ThorReward {
+	return thorRewards(e.ds.Data.ThorPointRewards, e.PointRewardGroupID)
+}
+
+func thorRewards(rewards []ThorReward, groupID int) []ThorReward {
+	ret := make([]ThorReward, 0)
+	for _, r := range rewards {
+		if r.GroupID == groupID {
+			ret = append(ret, r)
+		}
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		if ret[i].RankFrom == ret[j].RankFrom {
+			return ret[i].ID < ret[j].ID
+		}
+		return ret[i].RankFrom < ret[j].RankFrom
+	})
+	return ret
+}
+
+// ThorKingCosts the ATK and DEF ratios for each cost a king in the group can be attacked with, lowest cost first
+func (d *Dataset) ThorKingCosts(groupID int) []ThorKingCost {
+	ret := make([]ThorKingCost, 0)
+	for _, c := range d.Data.ThorKingCosts {
+		if c.GroupID == groupID {
+			ret = append(ret, c)
 		}
 	}
-	return e._archwitches
+	sort.SliceStable(ret, func(i, j int) bool { return ret[i].Cost < ret[j].Cost })
+	return ret
 }
 
 // MaxThorEventID Max thor event ID
@@ -90,12 +148,12 @@ func MaxThorEventID(events []ThorEvent) (max int) {
 }
 
 // ThorEventScan searches for a thor event by ID
-func ThorEventScan(id int) *ThorEvent {
+func (d *Dataset) ThorEventScan(id int) *ThorEvent {
 	if id > 0 {
-		l := len(Data.ThorEvents)
-		i := sort.Search(l, func(i int) bool { return Data.ThorEvents[i].ID >= id })
-		if i >= 0 && i < l && Data.ThorEvents[i].ID == id {
-			return &(Data.ThorEvents[i])
+		l := len(d.Data.ThorEvents)
+		i := sort.Search(l, func(i int) bool { return d.Data.ThorEvents[i].ID >= id })
+		if i >= 0 && i < l && d.Data.ThorEvents[i].ID == id {
+			return &(d.Data.ThorEvents[i])
 		}
 	}
 	return nil
@@ -16,7 +16,7 @@ type Tower struct {
 func (t *Tower) RankRewards() []RankRewardSheet {
 	set := make([]RankRewardSheet, 0)
 	if t.RankingRewardGroupID > 0 {
-		for _, val := range Data.TowerRewards {
+		for _, val := range t.ds.Data.TowerRewards {
 			if val.SheetID == t.RankingRewardGroupID {
 				set = append(set, val)
 			}
@@ -29,7 +29,7 @@ func (t *Tower) RankRewards() []RankRewardSheet {
 func (t *Tower) ArrivalRewards() []RankRewardSheet {
 	set := make([]RankRewardSheet, 0)
 	if t.ArrivalRewardGroupID > 0 {
-		for _, val := range Data.TowerArrivalRewards {
+		for _, val := range t.ds.Data.TowerArrivalRewards {
 			if val.SheetID == t.ArrivalRewardGroupID {
 				set = append(set, val)
 			}
@@ -39,12 +39,12 @@ func (t *Tower) ArrivalRewards() []RankRewardSheet {
 }
 
 // TowerScan search for a tower by ID
-func TowerScan(id int) *Tower {
+func (d *Dataset) TowerScan(id int) *Tower {
 	if id > 0 {
-		l := len(Data.Towers)
-		i := sort.Search(l, func(i int) bool { return Data.Towers[i].ID >= id })
-		if i >= 0 && i < l && Data.Towers[i].ID == id {
-			return &(Data.Towers[i])
+		l := len(d.Data.Towers)
+		i := sort.Search(l, func(i int) bool { return d.Data.Towers[i].ID >= id })
+		if i >= 0 && i < l && d.Data.Towers[i].ID == id {
+			return &(d.Data.Towers[i])
 		}
 	}
 	return nil
@@ -60,7 +60,7 @@ func (t *Tower) EventName() string {
 	if t == nil {
 		return ""
 	}
-	for _, evt := range Data.Events {
+	for _, evt := range t.ds.Data.Events {
 		if evt.TowerEventID == t.ID {
 			return evt.Name
 		}
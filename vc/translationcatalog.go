@@ -0,0 +1,453 @@
+package vc
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TranslationUnit a line of a string file in the source language paired with the same line in the target language
+type TranslationUnit struct {
+	// File name of the string file without the language, e.g. MsgCardName
+	File string
+	// Line 1-based line in the string file
+	Line int
+	// Context the records the line belongs to, e.g. "card 12: Alpha"
+	Context []string
+	Source  string
+	Target  string
+}
+
+// Key identifies the unit in PO and XLIFF files, e.g. MsgCardName:12
+func (u *TranslationUnit) Key() string {
+	return u.File + ":" + strconv.Itoa(u.Line)
+}
+
+// TranslationCatalog the lines of one or more string files for translators
+type TranslationCatalog struct {
+	SourceLang string
+	TargetLang string
+	Units      []TranslationUnit
+}
+
+// StringFileNames the names of the Msg*_<lang>.strb string files in the data location, without the language
+func (d *Dataset) StringFileNames(lang string) ([]string, error) {
+	suffix := "_" + lang + ".strb"
+	matches, err := filepath.Glob(filepath.Join(d.FilePath, "string", "Msg*"+suffix))
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]string, 0, len(matches))
+	for _, m := range matches {
+		ret = append(ret, strings.TrimSuffix(filepath.Base(m), suffix))
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
+// stringFilePath path of a string file in a language
+func (d *Dataset) stringFilePath(name, lang string) string {
+	return filepath.Join(d.FilePath, "string", name+"_"+lang+".strb")
+}
+
+// translationContext the records each line of the string files belongs to, keyed by file name and line
+func (d *Dataset) translationContext() map[string]map[int][]string {
+	ret := make(map[string]map[int][]string)
+	if d.Data == nil {
+		return ret
+	}
+	for _, table := range langTables {
+		records := table.records(d)
+		sort.SliceStable(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+		for _, lf := range table.files {
+			name := strings.TrimSuffix(lf.file, "_en.strb")
+			if ret[name] == nil {
+				ret[name] = make(map[int][]string)
+			}
+			for _, rec := range records {
+				ctx := fmt.Sprintf("%s %d", table.entity, rec.ID)
+				if rec.Label != "" {
+					ctx += ": " + rec.Label
+				}
+				ret[name][rec.ID] = append(ret[name][rec.ID], ctx)
+			}
+		}
+	}
+	return ret
+}
+
+// TranslationCatalog pairs the lines of the string files in the source and target languages.
+// All the Msg*.strb files in the source language are used if no names are given.
+// Lines are left untranslated when the target language does not have the file.
+func (d *Dataset) TranslationCatalog(sourceLang, targetLang string, names ...string) (*TranslationCatalog, error) {
+	if len(names) == 0 {
+		var err error
+		if names, err = d.StringFileNames(sourceLang); err != nil {
+			return nil, err
+		}
+	}
+	context := d.translationContext()
+	cat := &TranslationCatalog{SourceLang: sourceLang, TargetLang: targetLang, Units: make([]TranslationUnit, 0)}
+	for _, name := range names {
+		source, err := ReadStrb(d.stringFilePath(name, sourceLang))
+		if err != nil {
+			return nil, err
+		}
+		var target []string
+		if tf, err := ReadStrb(d.stringFilePath(name, targetLang)); err == nil {
+			target = tf.Strings
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+		for i, s := range source.Strings {
+			u := TranslationUnit{
+				File:    name,
+				Line:    i + 1,
+				Context: context[name][i+1],
+				Source:  s,
+			}
+			if i < len(target) {
+				u.Target = target[i]
+			}
+			cat.Units = append(cat.Units, u)
+		}
+	}
+	return cat, nil
+}
+
+// ImportTranslations writes a <file>_<target lang>.strb string file into outDir for each file in the catalog.
+// The source language file is used as the base, so lines without a translation keep the text of an
+// existing target language file, or the source text if there is none.
+// Units whose source text no longer matches the source language file were exported from an older
+// version of the file. They are skipped and returned so they can be translated again.
+func (d *Dataset) ImportTranslations(cat *TranslationCatalog, outDir string) (written []string, skipped []TranslationUnit, err error) {
+	if cat.SourceLang == "" || cat.TargetLang == "" {
+		return nil, nil, errors.New("the source and target languages are required")
+	}
+	if cat.SourceLang == cat.TargetLang {
+		return nil, nil, errors.New("the source and target languages are the same")
+	}
+	byFile := make(map[string][]TranslationUnit)
+	names := make([]string, 0)
+	for _, u := range cat.Units {
+		if _, ok := byFile[u.File]; !ok {
+			names = append(names, u.File)
+		}
+		byFile[u.File] = append(byFile[u.File], u)
+	}
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return nil, nil, err
+	}
+
+	written = make([]string, 0, len(names))
+	skipped = make([]TranslationUnit, 0)
+	for _, name := range names {
+		if strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+			return written, skipped, fmt.Errorf("invalid string file name: %s", name)
+		}
+		sf, err := ReadStrb(d.stringFilePath(name, cat.SourceLang))
+		if err != nil {
+			return written, skipped, err
+		}
+		source := append([]string(nil), sf.Strings...)
+		if existing, err := ReadStrb(d.stringFilePath(name, cat.TargetLang)); err == nil {
+			for i := range sf.Strings {
+				if i < len(existing.Strings) {
+					sf.Strings[i] = existing.Strings[i]
+				}
+			}
+		}
+		for _, u := range byFile[name] {
+			if u.Line < 1 || u.Line > len(sf.Strings) {
+				return written, skipped, fmt.Errorf("%s: line %d is not in the string file", u.Key(), u.Line)
+			}
+			if u.Source != source[u.Line-1] {
+				skipped = append(skipped, u)
+				continue
+			}
+			if u.Target != "" {
+				sf.Strings[u.Line-1] = u.Target
+			}
+		}
+		fileName := filepath.Join(outDir, name+"_"+cat.TargetLang+".strb")
+		if err := sf.Save(fileName); err != nil {
+			return written, skipped, err
+		}
+		written = append(written, fileName)
+	}
+	return written, skipped, nil
+}
+
+// WritePO writes the catalog as a gettext PO file. Each line is kept apart by its file and line in msgctxt.
+func WritePO(w io.Writer, cat *TranslationCatalog) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "msgid \"\"\nmsgstr \"\"\n\"Content-Type: text/plain; charset=UTF-8\\n\"\n\"Language: %s\\n\"\n\"X-Source-Language: %s\\n\"\n",
+		poEscape(cat.TargetLang),
+		poEscape(cat.SourceLang),
+	)
+	for _, u := range cat.Units {
+		bw.WriteString("\n")
+		for _, ctx := range u.Context {
+			fmt.Fprintf(bw, "#. %s\n", strings.ReplaceAll(ctx, "\n", " "))
+		}
+		fmt.Fprintf(bw, "#: %s_%s.strb:%d\n", u.File, cat.SourceLang, u.Line)
+		fmt.Fprintf(bw, "msgctxt \"%s\"\n", poEscape(u.Key()))
+		fmt.Fprintf(bw, "msgid \"%s\"\n", poEscape(u.Source))
+		fmt.Fprintf(bw, "msgstr \"%s\"\n", poEscape(u.Target))
+	}
+	return bw.Flush()
+}
+
+func poEscape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+	return r.Replace(s)
+}
+
+func poUnescape(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		if i >= len(s) {
+			return "", errors.New("string ends with a \\")
+		}
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case '"', '\\':
+			b.WriteByte(s[i])
+		default:
+			return "", fmt.Errorf("unknown escape \\%c", s[i])
+		}
+	}
+	return b.String(), nil
+}
+
+// ReadPO reads a PO file written by WritePO. Entries marked fuzzy are left untranslated.
+func ReadPO(r io.Reader) (*TranslationCatalog, error) {
+	cat := &TranslationCatalog{Units: make([]TranslationUnit, 0)}
+	type entry struct {
+		fuzzy                  bool
+		context                []string
+		msgctxt, msgid, msgstr string
+		hasID                  bool
+	}
+	var e entry
+	var current *string
+	lineNo := 0
+
+	finish := func() error {
+		defer func() { e = entry{}; current = nil }()
+		if !e.hasID {
+			return nil
+		}
+		if e.msgid == "" && e.msgctxt == "" {
+			// the header
+			for _, h := range strings.Split(e.msgstr, "\n") {
+				if v := strings.TrimPrefix(h, "Language:"); v != h {
+					cat.TargetLang = strings.TrimSpace(v)
+				} else if v := strings.TrimPrefix(h, "X-Source-Language:"); v != h {
+					cat.SourceLang = strings.TrimSpace(v)
+				}
+			}
+			return nil
+		}
+		sep := strings.LastIndex(e.msgctxt, ":")
+		if sep < 0 {
+			return fmt.Errorf("line %d: msgctxt should be <file>:<line>, found '%s'", lineNo, e.msgctxt)
+		}
+		line, err := strconv.Atoi(e.msgctxt[sep+1:])
+		if err != nil {
+			return fmt.Errorf("line %d: msgctxt should be <file>:<line>, found '%s'", lineNo, e.msgctxt)
+		}
+		u := TranslationUnit{File: e.msgctxt[:sep], Line: line, Context: e.context, Source: e.msgid}
+		if !e.fuzzy {
+			u.Target = e.msgstr
+		}
+		cat.Units = append(cat.Units, u)
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		var keyword, value string
+		switch {
+		case line == "":
+			if err := finish(); err != nil {
+				return nil, err
+			}
+			continue
+		case strings.HasPrefix(line, "#,"):
+			if e.hasID {
+				if err := finish(); err != nil {
+					return nil, err
+				}
+			}
+			e.fuzzy = strings.Contains(line, "fuzzy")
+			continue
+		case strings.HasPrefix(line, "#."):
+			if e.hasID {
+				if err := finish(); err != nil {
+					return nil, err
+				}
+			}
+			e.context = append(e.context, strings.TrimSpace(line[2:]))
+			continue
+		case strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, `"`):
+			value = line
+		default:
+			sp := strings.IndexByte(line, ' ')
+			if sp < 0 {
+				return nil, fmt.Errorf("line %d: unexpected text '%s'", lineNo, line)
+			}
+			keyword, value = line[:sp], strings.TrimSpace(line[sp+1:])
+		}
+		if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+			return nil, fmt.Errorf("line %d: expected a quoted string", lineNo)
+		}
+		text, err := poUnescape(value[1 : len(value)-1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", lineNo, err.Error())
+		}
+		switch keyword {
+		case "":
+			if current == nil {
+				return nil, fmt.Errorf("line %d: string without a keyword", lineNo)
+			}
+			*current += text
+			continue
+		case "msgctxt":
+			if e.hasID {
+				if err := finish(); err != nil {
+					return nil, err
+				}
+			}
+			current = &e.msgctxt
+		case "msgid":
+			e.hasID = true
+			current = &e.msgid
+		case "msgstr":
+			current = &e.msgstr
+		default:
+			return nil, fmt.Errorf("line %d: unsupported keyword '%s'", lineNo, keyword)
+		}
+		*current = text
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := finish(); err != nil {
+		return nil, err
+	}
+	return cat, nil
+}
+
+// xliff XLIFF 1.2 document
+type xliff struct {
+	XMLName xml.Name    `xml:"urn:oasis:names:tc:xliff:document:1.2 xliff"`
+	Version string      `xml:"version,attr"`
+	Files   []xliffFile `xml:"file"`
+}
+
+type xliffFile struct {
+	Original       string      `xml:"original,attr"`
+	SourceLanguage string      `xml:"source-language,attr"`
+	TargetLanguage string      `xml:"target-language,attr"`
+	Datatype       string      `xml:"datatype,attr"`
+	Units          []xliffUnit `xml:"body>trans-unit"`
+}
+
+type xliffUnit struct {
+	ID     string       `xml:"id,attr"`
+	Space  string       `xml:"http://www.w3.org/XML/1998/namespace space,attr,omitempty"`
+	Source string       `xml:"source"`
+	Target *xliffTarget `xml:"target"`
+	Notes  []string     `xml:"note"`
+}
+
+type xliffTarget struct {
+	State string `xml:"state,attr,omitempty"`
+	Text  string `xml:",chardata"`
+}
+
+// WriteXLIFF writes the catalog as an XLIFF 1.2 document with a file element for each string file
+func WriteXLIFF(w io.Writer, cat *TranslationCatalog) error {
+	doc := xliff{Version: "1.2"}
+	for _, u := range cat.Units {
+		if len(doc.Files) == 0 || doc.Files[len(doc.Files)-1].Original != u.File {
+			doc.Files = append(doc.Files, xliffFile{
+				Original:       u.File,
+				SourceLanguage: cat.SourceLang,
+				TargetLanguage: cat.TargetLang,
+				Datatype:       "plaintext",
+			})
+		}
+		xu := xliffUnit{
+			ID:     u.Key(),
+			Space:  "preserve",
+			Source: u.Source,
+			Target: &xliffTarget{Text: u.Target},
+			Notes:  u.Context,
+		}
+		if u.Target == "" {
+			xu.Target.State = "needs-translation"
+		}
+		f := &doc.Files[len(doc.Files)-1]
+		f.Units = append(f.Units, xu)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// ReadXLIFF reads an XLIFF 1.2 document written by WriteXLIFF
+func ReadXLIFF(r io.Reader) (*TranslationCatalog, error) {
+	doc := xliff{}
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	cat := &TranslationCatalog{Units: make([]TranslationUnit, 0)}
+	for _, f := range doc.Files {
+		cat.SourceLang, cat.TargetLang = f.SourceLanguage, f.TargetLanguage
+		for _, xu := range f.Units {
+			sep := strings.LastIndex(xu.ID, ":")
+			line, err := strconv.Atoi(xu.ID[sep+1:])
+			if sep < 0 || err != nil {
+				return nil, fmt.Errorf("trans-unit id should be <file>:<line>, found '%s'", xu.ID)
+			}
+			u := TranslationUnit{File: xu.ID[:sep], Line: line, Source: xu.Source, Context: xu.Notes}
+			if xu.Target != nil {
+				u.Target = xu.Target.Text
+			}
+			cat.Units = append(cat.Units, u)
+		}
+	}
+	return cat, nil
+}
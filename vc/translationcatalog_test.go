@@ -0,0 +1,97 @@
+package vc
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func testCatalog() *TranslationCatalog {
+	return &TranslationCatalog{
+		SourceLang: "en",
+		TargetLang: "zhs",
+		Units: []TranslationUnit{
+			{File: "MsgCardName", Line: 1, Context: []string{"card 1: Alpha"}, Source: "Alpha", Target: "阿尔法"},
+			{File: "MsgCardName", Line: 2, Context: []string{"card 2: Beta", "card 3: Beta"}, Source: "Beta"},
+			{File: "MsgCardName", Line: 3, Source: "Say \"hi\"\n\tback\\", Target: "说\"你好\"\n\t回\\"},
+		},
+	}
+}
+
+func TestTranslationCatalogPO(t *testing.T) {
+	cat := testCatalog()
+	var b bytes.Buffer
+	if err := WritePO(&b, cat); err != nil {
+		t.Fatalf("WritePO returned an error: %s", err.Error())
+	}
+	read, err := ReadPO(&b)
+	if err != nil {
+		t.Fatalf("ReadPO returned an error: %s", err.Error())
+	}
+	if !reflect.DeepEqual(read, cat) {
+		t.Errorf("The catalog changed when read back:\n%+v\n%+v", read, cat)
+	}
+}
+
+func TestTranslationCatalogXLIFF(t *testing.T) {
+	cat := testCatalog()
+	var b bytes.Buffer
+	if err := WriteXLIFF(&b, cat); err != nil {
+		t.Fatalf("WriteXLIFF returned an error: %s", err.Error())
+	}
+	read, err := ReadXLIFF(&b)
+	if err != nil {
+		t.Fatalf("ReadXLIFF returned an error: %s", err.Error())
+	}
+	if !reflect.DeepEqual(read, cat) {
+		t.Errorf("The catalog changed when read back:\n%+v\n%+v", read, cat)
+	}
+}
+
+func TestImportTranslations(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vctranslations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	strDir := filepath.Join(dir, "string")
+	os.Mkdir(strDir, 0755)
+	ioutil.WriteFile(filepath.Join(strDir, "MsgCardName_en.strb"), testStrb([]uint32{5, 11, 16}, "Alpha", "Beta", "Gamma"), 0644)
+	ioutil.WriteFile(filepath.Join(strDir, "MsgCardName_zhs.strb"), testStrb([]uint32{5, 9}, "阿", "贝"), 0644)
+
+	ds := NewDataset(dir, "en")
+	cat, err := ds.TranslationCatalog("en", "zhs")
+	if err != nil {
+		t.Fatalf("TranslationCatalog returned an error: %s", err.Error())
+	}
+	if len(cat.Units) != 3 || cat.Units[1].Target != "贝" || cat.Units[2].Target != "" {
+		t.Errorf("Unexpected units: %+v", cat.Units)
+	}
+
+	cat.Units[0].Target = "阿尔法"
+	// the English text of this line changed after the export, so its translation is out of date
+	cat.Units[1].Source = "Bet"
+	cat.Units[1].Target = "贝塔"
+	outDir := filepath.Join(dir, "out")
+	files, skipped, err := ds.ImportTranslations(cat, outDir)
+	if err != nil {
+		t.Fatalf("ImportTranslations returned an error: %s", err.Error())
+	}
+	if len(skipped) != 1 || skipped[0].Key() != "MsgCardName:2" {
+		t.Errorf("Expected only MsgCardName:2 to be skipped, got %+v", skipped)
+	}
+	if len(files) != 1 {
+		t.Fatalf("Unexpected files: %v", files)
+	}
+	sf, err := ReadStrb(files[0])
+	if err != nil {
+		t.Fatalf("The imported file could not be read: %s", err.Error())
+	}
+	// untranslated lines fall back to the source text
+	if want := []string{"阿尔法", "贝", "Gamma"}; !reflect.DeepEqual(sf.Strings, want) {
+		t.Errorf("Unexpected strings: %v, expected %v", sf.Strings, want)
+	}
+}
@@ -0,0 +1,455 @@
+package vc
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Translations text for a record in every loaded language pack.
+// The map is keyed by the language pack and then by the field name, e.g. t["zhs"]["Name"]
+type Translations map[string]map[string]string
+
+// Get the text of a field in a language. Returns "" if it was not loaded.
+func (t Translations) Get(lang, field string) string {
+	if t == nil {
+		return ""
+	}
+	return t[lang][field]
+}
+
+// Name of the record in a language
+func (t Translations) Name(lang string) string {
+	return t.Get(lang, "Name")
+}
+
+// Description of the record in a language
+func (t Translations) Description(lang string) string {
+	return t.Get(lang, "Description")
+}
+
+func (t *Translations) set(lang, field, value string) {
+	if *t == nil {
+		*t = make(Translations)
+	}
+	if (*t)[lang] == nil {
+		(*t)[lang] = make(map[string]string)
+	}
+	(*t)[lang][field] = value
+}
+
+// langRecord a record that has text in the string files. ID is the 1-based line in the files.
+type langRecord struct {
+	ID    int
+	Label string
+	Text  *Translations
+	// Loaded the text Read loaded from the main language pack, keyed by field name
+	Loaded map[string]string
+}
+
+// langFile a string file that is loaded for every language pack
+type langFile struct {
+	// file name with the "_en.strb" suffix
+	file string
+	// field name the text is stored under
+	field string
+	// optional filter for the text
+	filter func(string) string
+}
+
+// langTable a master data table with text in the string files
+type langTable struct {
+	kind string
+	// entity name of a single record, used in translator comments
+	entity  string
+	files   []langFile
+	records func(d *Dataset) []langRecord
+}
+
+// langTables lists the text that is loaded for each language pack. Weapons are not listed: their
+// string files have a line for each rarity of a weapon, not a line for each record ID.
+var langTables = []langTable{
+	{
+		kind:   "cards",
+		entity: "card",
+		files:  []langFile{{"MsgCardName_en.strb", "Name", nil}},
+		records: func(d *Dataset) []langRecord {
+			ret := make([]langRecord, 0, len(d.Data.Cards))
+			for _, c := range d.Data.Cards {
+				ret = append(ret, langRecord{c.ID, c.Name, &c.Translations, map[string]string{"Name": c.Name}})
+			}
+			return ret
+		},
+	},
+	{
+		kind:   "characters",
+		entity: "character",
+		files: []langFile{
+			{"MsgCharaDesc_en.strb", "Description", func(s string) string { return strings.ReplaceAll(s, "\n", " ") }},
+			{"MsgCharaFriendship_en.strb", "Friendship", nil},
+			{"MsgCharaWelcome_en.strb", "Login", nil},
+			{"MsgCharaMeet_en.strb", "Meet", nil},
+			{"MsgCharaBtlStart_en.strb", "BattleStart", nil},
+			{"MsgCharaBtlEnd_en.strb", "BattleEnd", nil},
+			{"MsgCharaFriendshipMax_en.strb", "FriendshipMax", nil},
+			{"MsgCharaBonds_en.strb", "FriendshipEvent", nil},
+			{"MsgCharaSuperAwaken_en.strb", "Rebirth", nil},
+		},
+		records: func(d *Dataset) []langRecord {
+			ret := make([]langRecord, 0, len(d.Data.CardCharacters))
+			for i := range d.Data.CardCharacters {
+				ch := &d.Data.CardCharacters[i]
+				label := ""
+				if c := ch.FirstEvoCard(); c != nil {
+					label = c.Name
+				}
+				ret = append(ret, langRecord{ch.ID, label, &ch.Translations, map[string]string{
+					"Description":     ch.Description,
+					"Friendship":      ch.Friendship,
+					"Login":           ch.Login,
+					"Meet":            ch.Meet,
+					"BattleStart":     ch.BattleStart,
+					"BattleEnd":       ch.BattleEnd,
+					"FriendshipMax":   ch.FriendshipMax,
+					"FriendshipEvent": ch.FriendshipEvent,
+					"Rebirth":         ch.Rebirth,
+				}})
+			}
+			return ret
+		},
+	},
+	{
+		kind:   "skills",
+		entity: "skill",
+		files: []langFile{
+			{"MsgSkillName_en.strb", "Name", filterSkill},
+			{"MsgSkillDesc_en.strb", "Description", filterSkill},
+			{"MsgSkillFire_en.strb", "Fire", filterSkill},
+		},
+		records: func(d *Dataset) []langRecord {
+			ret := make([]langRecord, 0, len(d.Data.Skills))
+			for i := range d.Data.Skills {
+				s := &d.Data.Skills[i]
+				ret = append(ret, langRecord{s.ID, s.Name, &s.Translations, map[string]string{
+					"Name":        s.Name,
+					"Description": s.Description,
+					"Fire":        s.Fire,
+				}})
+			}
+			return ret
+		},
+	},
+	{
+		kind:   "events",
+		entity: "event",
+		files: []langFile{
+			{"MsgEventName_en.strb", "Name", filter},
+			{"MsgEventDesc_en.strb", "Description", func(s string) string { return filterElementImages(filter(filterColors(s))) }},
+		},
+		records: func(d *Dataset) []langRecord {
+			ret := make([]langRecord, 0, len(d.Data.Events))
+			for i := range d.Data.Events {
+				e := &d.Data.Events[i]
+				ret = append(ret, langRecord{e.ID, e.Name, &e.Translations, map[string]string{"Name": e.Name, "Description": e.Description}})
+			}
+			return ret
+		},
+	},
+	{
+		kind:   "items",
+		entity: "item",
+		files: []langFile{
+			{"MsgShopItemName_en.strb", "Name", func(s string) string { return filterItemName(filter(s)) }},
+			{"MsgShopItemDesc_en.strb", "Description", filter},
+			{"MsgShopItemDescInShop_en.strb", "DescriptionInShop", filter},
+			{"MsgShopItemDescSub_en.strb", "DescriptionSub", filter},
+			{"MsgShopItemUseResult_en.strb", "MsgUse", filter},
+		},
+		records: func(d *Dataset) []langRecord {
+			ret := make([]langRecord, 0, len(d.Data.Items))
+			for i := range d.Data.Items {
+				item := &d.Data.Items[i]
+				ret = append(ret, langRecord{item.ID, item.NameEng, &item.Translations, map[string]string{
+					"Name":              item.NameEng,
+					"Description":       item.Description,
+					"DescriptionInShop": item.DescriptionInShop,
+					"DescriptionSub":    item.DescriptionSub,
+					"MsgUse":            item.MsgUse,
+				}})
+			}
+			return ret
+		},
+	},
+	{
+		kind:   "deckbonuses",
+		entity: "deck bonus",
+		files: []langFile{
+			{"MsgDeckBonusName_en.strb", "Name", filter},
+			{"MsgDeckBonusDesc_en.strb", "Description", filter},
+		},
+		records: func(d *Dataset) []langRecord {
+			ret := make([]langRecord, 0, len(d.Data.DeckBonuses))
+			for i := range d.Data.DeckBonuses {
+				db := &d.Data.DeckBonuses[i]
+				ret = append(ret, langRecord{db.ID, db.Name, &db.Translations, map[string]string{"Name": db.Name, "Description": db.Description}})
+			}
+			return ret
+		},
+	},
+	{
+		kind:   "structures",
+		entity: "structure",
+		files: []langFile{
+			{"MsgBuildingName_en.strb", "Name", filter},
+			{"MsgBuildingDesc_en.strb", "Description", filter},
+		},
+		records: func(d *Dataset) []langRecord {
+			ret := make([]langRecord, 0, len(d.Data.Structures))
+			for i := range d.Data.Structures {
+				s := &d.Data.Structures[i]
+				ret = append(ret, langRecord{s.ID, s.Name, &s.Translations, map[string]string{"Name": s.Name, "Description": s.Description}})
+			}
+			return ret
+		},
+	},
+	{
+		kind:   "maps",
+		entity: "map",
+		files: []langFile{
+			{"MsgNPCMapName_en.strb", "Name", nil},
+			{"MsgNPCMapStart_en.strb", "StartMsg", func(s string) string { return filter(filterColors(s)) }},
+		},
+		records: func(d *Dataset) []langRecord {
+			ret := make([]langRecord, 0, len(d.Data.Maps))
+			for i := range d.Data.Maps {
+				m := &d.Data.Maps[i]
+				ret = append(ret, langRecord{m.ID, m.Name, &m.Translations, map[string]string{"Name": m.Name, "StartMsg": m.StartMsg}})
+			}
+			return ret
+		},
+	},
+	{
+		kind:   "areas",
+		entity: "area",
+		files: []langFile{
+			{"MsgNPCAreaName_en.strb", "Name", filterColors},
+			{"MsgNPCAreaLongName_en.strb", "LongName", filterColors},
+			{"MsgNPCAreaStart_en.strb", "Start", filterColors},
+			{"MsgNPCAreaEnd_en.strb", "End", filterColors},
+			{"MsgNPCAreaStory_en.strb", "Story", filterColors},
+			// Read puts the boss end text in BossStart and the boss start text in BossEnd
+			{"MsgNPCBossEnd_en.strb", "BossStart", filterColors},
+			{"MsgNPCBossStart_en.strb", "BossEnd", filterColors},
+		},
+		records: func(d *Dataset) []langRecord {
+			ret := make([]langRecord, 0, len(d.Data.Areas))
+			for i := range d.Data.Areas {
+				a := &d.Data.Areas[i]
+				ret = append(ret, langRecord{a.ID, a.Name, &a.Translations, map[string]string{
+					"Name":      a.Name,
+					"LongName":  a.LongName,
+					"Start":     a.Start,
+					"End":       a.End,
+					"Story":     a.Story,
+					"BossStart": a.BossStart,
+					"BossEnd":   a.BossEnd,
+				}})
+			}
+			return ret
+		},
+	},
+	{
+		kind:   "archwitchseries",
+		entity: "archwitch series",
+		files:  []langFile{{"MsgKingTitle_en.strb", "Description", filter}},
+		records: func(d *Dataset) []langRecord {
+			ret := make([]langRecord, 0, len(d.Data.ArchwitchSeries))
+			for i := range d.Data.ArchwitchSeries {
+				aws := &d.Data.ArchwitchSeries[i]
+				ret = append(ret, langRecord{aws.ID, aws.Description, &aws.Translations, map[string]string{"Description": aws.Description}})
+			}
+			return ret
+		},
+	},
+	{
+		kind:   "archwitchfriendships",
+		entity: "archwitch friendship",
+		files:  []langFile{{"MsgKingFriendshipDesc_en.strb", "Likability", filter}},
+		records: func(d *Dataset) []langRecord {
+			ret := make([]langRecord, 0, len(d.Data.ArchwitchFriendships))
+			for i := range d.Data.ArchwitchFriendships {
+				awf := &d.Data.ArchwitchFriendships[i]
+				ret = append(ret, langRecord{awf.ID, awf.Likability, &awf.Translations, map[string]string{"Likability": awf.Likability}})
+			}
+			return ret
+		},
+	},
+	{
+		kind:   "thor",
+		entity: "thor event",
+		files:  []langFile{{"MsgThorhammerTitle_en.strb", "Title", filter}},
+		records: func(d *Dataset) []langRecord {
+			ret := make([]langRecord, 0, len(d.Data.ThorEvents))
+			for i := range d.Data.ThorEvents {
+				te := &d.Data.ThorEvents[i]
+				ret = append(ret, langRecord{te.ID, te.Title, &te.Translations, map[string]string{"Title": te.Title}})
+			}
+			return ret
+		},
+	},
+	{
+		kind:   "weaponskills",
+		entity: "weapon skill",
+		files:  []langFile{{"MsgWeaponSkillDesc_en.strb", "Description", filter}},
+		records: func(d *Dataset) []langRecord {
+			ret := make([]langRecord, 0, len(d.Data.WeaponSkills))
+			for i := range d.Data.WeaponSkills {
+				ws := &d.Data.WeaponSkills[i]
+				ret = append(ret, langRecord{ws.ID, ws.Description, &ws.Translations, map[string]string{"Description": ws.Description}})
+			}
+			return ret
+		},
+	},
+}
+
+// TranslationKinds the kinds of records that have translations, in display order
+func TranslationKinds() []string {
+	ret := make([]string, 0, len(langTables))
+	for _, t := range langTables {
+		ret = append(ret, t.kind)
+	}
+	return ret
+}
+
+// AllLanguages the main language pack followed by any additional language packs
+func (d *Dataset) AllLanguages() []string {
+	ret := []string{d.LangPack}
+	for _, lang := range d.Languages {
+		if lang != "" && !containsString(ret, lang) {
+			ret = append(ret, lang)
+		}
+	}
+	return ret
+}
+
+// readLanguages loads the translations for all the language packs. The main language pack is
+// taken from the text Read already loaded. String files that do not exist for another language
+// pack are skipped and reported as missing text.
+func (d *Dataset) readLanguages() error {
+	strRoot := filepath.Join(d.FilePath, "string")
+	for _, table := range langTables {
+		records := table.records(d)
+		for _, lf := range table.files {
+			for _, rec := range records {
+				rec.Text.set(d.LangPack, lf.field, rec.Loaded[lf.field])
+			}
+			for _, lang := range d.AllLanguages()[1:] {
+				fname := filepath.Join(strRoot, strings.Replace(lf.file, "_en.strb", "_"+lang+".strb", 1))
+				if _, err := os.Stat(fname); os.IsNotExist(err) {
+					continue
+				}
+				strs, err := ReadStringFile(fname)
+				if err != nil {
+					return err
+				}
+				for _, rec := range records {
+					if rec.ID < 1 || rec.ID > len(strs) {
+						continue
+					}
+					s := strs[rec.ID-1]
+					if lf.filter != nil {
+						s = lf.filter(s)
+					}
+					rec.Text.set(lang, lf.field, s)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// TranslationRow the text of a single field in each loaded language pack
+type TranslationRow struct {
+	ID     int               `json:"_id"`
+	Label  string            `json:"label"`
+	Field  string            `json:"field"`
+	Values map[string]string `json:"values"`
+	// Missing languages that have no text when another language has some
+	Missing []string `json:"missing,omitempty"`
+	// Untranslated languages that have the same text as the main language pack
+	Untranslated []string `json:"untranslated,omitempty"`
+}
+
+// IsFlagged true if the text is missing or untranslated in any language
+func (r *TranslationRow) IsFlagged() bool {
+	return len(r.Missing) > 0 || len(r.Untranslated) > 0
+}
+
+// CompareTranslations lists the text of a kind of record side by side in every loaded language pack.
+// Text that is missing in a language, or that is the same as the main language pack is flagged.
+// If flaggedOnly is true, only the flagged rows are returned.
+func (d *Dataset) CompareTranslations(kind string, flaggedOnly bool) []TranslationRow {
+	ret := make([]TranslationRow, 0)
+	langs := d.AllLanguages()
+	for _, table := range langTables {
+		if table.kind != kind {
+			continue
+		}
+		records := table.records(d)
+		sort.SliceStable(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+		for _, rec := range records {
+			for _, lf := range table.files {
+				row := TranslationRow{
+					ID:     rec.ID,
+					Label:  rec.Label,
+					Field:  lf.field,
+					Values: make(map[string]string, len(langs)),
+				}
+				hasText := false
+				for _, lang := range langs {
+					v := rec.Text.Get(lang, lf.field)
+					row.Values[lang] = v
+					if strings.TrimSpace(v) != "" {
+						hasText = true
+					}
+				}
+				if !hasText {
+					continue
+				}
+				base := row.Values[d.LangPack]
+				for _, lang := range langs {
+					v := row.Values[lang]
+					if strings.TrimSpace(v) == "" {
+						row.Missing = append(row.Missing, lang)
+					} else if lang != d.LangPack && v == base && hasLetters(v) {
+						row.Untranslated = append(row.Untranslated, lang)
+					}
+				}
+				if !flaggedOnly || row.IsFlagged() {
+					ret = append(ret, row)
+				}
+			}
+		}
+	}
+	return ret
+}
+
+func hasLetters(s string) bool {
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package vc
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestReadLanguages(t *testing.T) {
+	root := t.TempDir()
+	// only the other language pack has string files, the main one comes from what Read loaded
+	writeTestFile(t, filepath.Join(root, "string", "MsgCardName_zhs.strb"), testStrb([]uint32{5, 15}, "阿尔法", "贝塔"))
+	writeTestFile(t, filepath.Join(root, "string", "MsgNPCAreaName_zhs.strb"), testStrb([]uint32{5}, "森林"))
+
+	d := NewDataset(root, "en", "zhs")
+	d.Data.Cards = CardList{{ID: 1, Name: "Alpha"}, {ID: 2, Name: "Beta"}, {ID: 3, Name: "Gamma"}}
+	d.Data.Areas = []Area{{ID: 1, Name: "Forest"}}
+	d.link()
+	if err := d.readLanguages(); err != nil {
+		t.Fatalf("readLanguages returned an error: %s", err.Error())
+	}
+
+	for i, want := range []struct{ en, zhs string }{{"Alpha", "阿尔法"}, {"Beta", "贝塔"}, {"Gamma", ""}} {
+		c := d.Data.Cards[i]
+		if got := c.Translations.Name("en"); got != want.en {
+			t.Errorf("Card %d: expected the English name %s, got %s", c.ID, want.en, got)
+		}
+		if got := c.Translations.Name("zhs"); got != want.zhs {
+			t.Errorf("Card %d: expected the Chinese name %s, got %s", c.ID, want.zhs, got)
+		}
+	}
+	if a := d.Data.Areas[0]; a.Translations.Name("en") != "Forest" || a.Translations.Name("zhs") != "森林" {
+		t.Errorf("Unexpected area translations: %v", a.Translations)
+	}
+
+	rows := d.CompareTranslations("cards", true)
+	if len(rows) != 1 || rows[0].ID != 3 || len(rows[0].Missing) != 1 || rows[0].Missing[0] != "zhs" {
+		t.Errorf("Expected only card 3 to be flagged as missing, got %+v", rows)
+	}
+}
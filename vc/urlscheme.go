@@ -10,12 +10,12 @@ type URLScheme struct {
 }
 
 // URLSchemeScan search for a URLScheme by ID
-func URLSchemeScan(id int) *URLScheme {
+func (d *Dataset) URLSchemeScan(id int) *URLScheme {
 	if id > 0 {
-		l := len(Data.URLSchemes)
-		i := sort.Search(l, func(i int) bool { return Data.URLSchemes[i].ID >= id })
-		if i >= 0 && i < l && Data.URLSchemes[i].ID == id {
-			return &(Data.URLSchemes[i])
+		l := len(d.Data.URLSchemes)
+		i := sort.Search(l, func(i int) bool { return d.Data.URLSchemes[i].ID >= id })
+		if i >= 0 && i < l && d.Data.URLSchemes[i].ID == id {
+			return &(d.Data.URLSchemes[i])
 		}
 	}
 	return nil
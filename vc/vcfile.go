@@ -1,37 +1,23 @@
 package vc
 
 import (
-	"bufio"
 	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
 	"regexp"
 	"runtime/debug"
-	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"vc_file_grouper/util"
 )
 
-// FilePath path to the main VC file
-var FilePath string
-
-// Data Main data file
-var Data *VFile
-
-// MasterDataStr master data as read from the file as a string.
-var MasterDataStr string
-
-// LangPack language pack to use
-var LangPack string
-
 // Timestamp in the JSON file
 type Timestamp struct {
 	time.Time
@@ -44,20 +30,6 @@ type BinImage struct {
 	Data []byte
 }
 
-// ReadMasterData Reads the master data from the file location
-func ReadMasterData(file string) error {
-	if Data == nil {
-		Data = &(VFile{})
-	}
-	b, err := Read(file)
-	if err != nil {
-		os.Stderr.WriteString(err.Error() + "\n")
-		return err
-	}
-	MasterDataStr = string(b)
-	return nil
-}
-
 // MarshalJSON converts a JSON timestamp to a GO time
 func (t *Timestamp) MarshalJSON() ([]byte, error) {
 	if t.Time.IsZero() {
@@ -72,99 +44,6 @@ func (t *Timestamp) MarshalJSON() ([]byte, error) {
 
 var location = time.FixedZone("JST", 32400) //time.LoadLocation("Asia/Tokyo")
 
-// old cards that are not available anymore
-var retiredCards = []int{
-	61, 62, // bandit
-	55, 56, // beastmaster
-	102,      // cutthroat
-	323, 324, // cyborg
-	121, 122, // dancer
-	38, 39, // dark knight
-	123, 124, // detective
-	163, 164, // doll master
-	41, 42, // dragon knight
-	43,       // dragon slayer
-	225, 226, // dragonewt
-	119, 120, // druid
-	174, 175, // empress
-	157, 158, // farmer
-	201, 202, // fox spirit
-	229, 230, // gnome
-	242, 243, // harpy
-	86, 87, // hunter
-	63,            // idol
-	1, 2, 3, 4, 5, // knight
-	90, 91, // kung-fu master
-	195, 196, // lycaon
-	88, 89, // martial artist
-	321, 322, // mechanic
-	348, 349, // mythic knight
-	265, 266, // oni
-	40,     // paladin
-	94, 95, // rune knight
-	73, 74, // sage
-	84, 85, // strategist
-	100, 101, // swordsman
-	183, 184, // sylph
-	304, 305, // trickster
-	133, 134, // vampire hunter
-}
-
-// new cards that are named the same as an old card that is still active
-var newCards = []int{
-	4721, 4722, 4734, // Spinner
-	4752, 4753, 4785, // Sparky
-	4711, 4712, // Diana
-	8969, 8970, 8971, 8972, // Lapis Lazuli
-}
-
-// ID is the character ID, not the card ID
-var characterNameOverride = map[int]string{
-	62:   "Kung-Fu Master",            // Kung Fu Master
-	181:  "Ariel (Light)",             // Ariel
-	254:  "Duckling Look-a-Like",      // Duckling Look-A-Like -> H = Swan Look-a-Like
-	352:  "Ariel (Dark)",              // Ariel
-	452:  "Joker",                     // Joker
-	465:  "Joker (Cane)",              // Joker
-	466:  "Joker (Sickle)",            // Joker
-	495:  "Snowman MK II",             // Snowman MKⅡ
-	1319: "Jack-O'-Sisters",           // Jack-o'-Sisters (older card with newer name format)
-	1173: "Al-mi'raj",                 // Al-Mi'Raj
-	1536: "Valiant Bellona (Bronze)",  // Valiant Bellona
-	1537: "Valiant Bellona (Silver)",  // Valiant Bellona
-	1538: "Valiant Bellona (Gold)",    // Valiant Bellona
-	1816: "Gold Girl (SR)",            // Gold Girl
-	1846: "Medal Girl (SR)",           // Medal Girl
-	1869: "Super Chimry (Passion)",    // Super Chimry
-	1870: "Super Chimry (Cool)",       // Super Chimry
-	1871: "Super Chimry (Light)",      // Super Chimry
-	1872: "Super Chimry (Dark)",       // Super Chimry
-	1874: "Hyper Chimry (Passion)",    // Hyper Chimry
-	1875: "Hyper Chimry (Cool)",       // Hyper Chimry
-	1876: "Hyper Chimry (Light)",      // Hyper Chimry
-	1877: "Hyper Chimry (Dark)",       // Hyper Chimry
-	2024: "Playful Hades (Red)",       // Playful Hades
-	2025: "Playful Hades (Green)",     // Playful Hades
-	2026: "Playful Hades (Blue)",      // Playful Hades
-	2080: "Eunice",                    // X Eunice = Yunice? Why Mynet...
-	2357: "Empress Slime",             // G Empress Slime = Queen Slime...
-	2397: "PM Demise",                 // Pm Demise
-	2468: "One-piece Swimsuit",        // fix case of Piece
-	2479: "DIY Ninja",                 // Diy Ninja
-	2549: "Thunder Stone Shard (L)",   // Thunderstone Shard (L)
-	2550: "Thunder Stone Shard (D)",   // Thunderstone Shard (D)
-	2554: "Lightning Stone Shard (L)", // Lightning Shard (L)
-	2555: "Lightning Stone Shard (D)", // Lightning Shard (D)
-	2978: "Etna & Flonne",             // fix spacing
-	3167: "Kiyohime (collab)",         // new Kiyo from a collab
-	3408: "Holy Oracle (New)",         // new Holy Oracle
-}
-
-func init() {
-	sort.Ints(retiredCards)
-	sort.Ints(newCards)
-}
-
 // UnmarshalJSON converts a GO time to a JSON timestamp
 func (t *Timestamp) UnmarshalJSON(b []byte) error {
 	ts, err := strconv.Atoi(string(b))
@@ -282,8 +161,10 @@ type VFile struct {
 }
 
 // Read This reads the main data file and all associated files for strings
-// the data is inserted directly into the struct.
-func Read(root string) ([]byte, error) {
+// the data is inserted directly into the dataset.
+func (d *Dataset) Read() ([]byte, error) {
+	clearBinImageCache()
+	root := d.FilePath
 	filename := filepath.Join(root, "response", "master_all")
 
 	var data []byte
@@ -313,31 +194,38 @@ func Read(root string) ([]byte, error) {
 		}
 	}
 
+	d.Overrides, err = ReadNameOverrides(root)
+	if err != nil {
+		return nil, err
+	}
+
 	// decode the main file
-	err = json.Unmarshal(data[:], Data)
+	err = json.Unmarshal(data[:], d.Data)
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
+	d.link()
+	d.index = nil
 
 	// get card rarities
-	Rarity = make([]string, 0)
-	for _, cr := range Data.CardRarities {
-		Rarity = append(Rarity, strings.ToUpper(cr.Signature))
+	d.Rarity = make([]string, 0)
+	for _, cr := range d.Data.CardRarities {
+		d.Rarity = append(d.Rarity, strings.ToUpper(cr.Signature))
 	}
 
 	strRoot := filepath.Join(root, "string")
 
 	// symbol names
-	names, err := ReadStringFile(filepath.Join(strRoot, "MsgCardSymbol_en.strb"))
+	names, err := d.ReadStringFile(filepath.Join(strRoot, "MsgCardSymbol_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
-	Data.SymbolNames = append([]string{"No Symbol"}, names...)
+	d.Data.SymbolNames = append([]string{"No Symbol"}, names...)
 
 	// card names
-	names, err = ReadStringFile(filepath.Join(strRoot, "MsgCardName_en.strb"))
+	names, err = d.ReadStringFile(filepath.Join(strRoot, "MsgCardName_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
@@ -346,88 +234,91 @@ func Read(root string) ([]byte, error) {
 	lenNames := len(names)
 
 	// pad out the card list for cards we have text for, but no data
-	maxCardID := Data.Cards.MaxID()
+	maxCardID := d.Data.Cards.MaxID()
 	if lenNames > maxCardID {
 		for i := maxCardID; i < lenNames; i++ {
 			c := &Card{
 				ID:       i + 1,
 				IsClosed: 1,
 			}
-			Data.Cards = append(Data.Cards, c)
+			c.setDataset(d)
+			d.Data.Cards = append(d.Data.Cards, c)
 		}
 	}
 
-	for key := range Data.Cards {
-		card := Data.Cards[key]
+	d.index = d.buildIndex()
+
+	for key := range d.Data.Cards {
+		card := d.Data.Cards[key]
 		if card.ID <= lenNames {
-			card.Name = cleanCardName(names[card.ID-1], card)
+			card.Name = d.Overrides.CardName(names[card.ID-1], card)
 		}
 	}
 
-	renameSpecialAmalCardsWithDupNames()
+	d.renameSpecialAmalCardsWithDupNames()
 
 	// initialize the evolutions
-	for key := range Data.Cards {
-		card := Data.Cards[key]
+	for key := range d.Data.Cards {
+		card := d.Data.Cards[key]
 		card.GetEvolutions()
 	}
 
-	description, err := ReadStringFile(filepath.Join(strRoot, "MsgCharaDesc_en.strb"))
+	description, err := d.ReadStringFile(filepath.Join(strRoot, "MsgCharaDesc_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
 	lenDescriptions := len(description)
 
-	friendship, err := ReadStringFile(filepath.Join(strRoot, "MsgCharaFriendship_en.strb"))
+	friendship, err := d.ReadStringFile(filepath.Join(strRoot, "MsgCharaFriendship_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
 
-	login, err := ReadStringFile(filepath.Join(strRoot, "MsgCharaWelcome_en.strb"))
+	login, err := d.ReadStringFile(filepath.Join(strRoot, "MsgCharaWelcome_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
 
-	meet, err := ReadStringFile(filepath.Join(strRoot, "MsgCharaMeet_en.strb"))
+	meet, err := d.ReadStringFile(filepath.Join(strRoot, "MsgCharaMeet_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
 
-	battleStart, err := ReadStringFile(filepath.Join(strRoot, "MsgCharaBtlStart_en.strb"))
+	battleStart, err := d.ReadStringFile(filepath.Join(strRoot, "MsgCharaBtlStart_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
 
-	battleEnd, err := ReadStringFile(filepath.Join(strRoot, "MsgCharaBtlEnd_en.strb"))
+	battleEnd, err := d.ReadStringFile(filepath.Join(strRoot, "MsgCharaBtlEnd_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
 
-	friendshipMax, err := ReadStringFile(filepath.Join(strRoot, "MsgCharaFriendshipMax_en.strb"))
+	friendshipMax, err := d.ReadStringFile(filepath.Join(strRoot, "MsgCharaFriendshipMax_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
 
-	friendshipEvent, err := ReadStringFile(filepath.Join(strRoot, "MsgCharaBonds_en.strb"))
+	friendshipEvent, err := d.ReadStringFile(filepath.Join(strRoot, "MsgCharaBonds_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
 
-	rebirthEvent, err := ReadStringFile(filepath.Join(strRoot, "MsgCharaSuperAwaken_en.strb"))
+	rebirthEvent, err := d.ReadStringFile(filepath.Join(strRoot, "MsgCharaSuperAwaken_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
-	for key := range Data.CardCharacters {
-		chara := &Data.CardCharacters[key]
+	for key := range d.Data.CardCharacters {
+		chara := &d.Data.CardCharacters[key]
 		if chara.ID <= lenDescriptions {
 			chara.Description = strings.ReplaceAll(description[chara.ID-1], "\n", " ")
 		}
@@ -458,21 +349,21 @@ func Read(root string) ([]byte, error) {
 	}
 
 	//Read Skill strings
-	names, err = ReadStringFile(filepath.Join(strRoot, "MsgSkillName_en.strb"))
+	names, err = d.ReadStringFile(filepath.Join(strRoot, "MsgSkillName_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
 	lenNames = len(names)
 
-	description, err = ReadStringFile(filepath.Join(strRoot, "MsgSkillDesc_en.strb"))
+	description, err = d.ReadStringFile(filepath.Join(strRoot, "MsgSkillDesc_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
 	lenDescriptions = len(description)
 
-	fire, err := ReadStringFile(filepath.Join(strRoot, "MsgSkillFire_en.strb"))
+	fire, err := d.ReadStringFile(filepath.Join(strRoot, "MsgSkillFire_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
@@ -480,18 +371,19 @@ func Read(root string) ([]byte, error) {
 	lenFire := len(fire)
 
 	// pad out the skill list for skills we have text for, but no data
-	maxSkillID := MaxSkillID(Data.Skills)
+	maxSkillID := MaxSkillID(d.Data.Skills)
 	if lenNames == lenDescriptions && lenNames == lenFire && lenNames > maxSkillID {
 		for i := maxSkillID; i < lenNames; i++ {
 			s := Skill{
 				ID: i + 1,
 			}
-			Data.Skills = append(Data.Skills, s)
+			s.setDataset(d)
+			d.Data.Skills = append(d.Data.Skills, s)
 		}
 	}
 
-	for key := range Data.Skills {
-		skill := &Data.Skills[key]
+	for key := range d.Data.Skills {
+		skill := &d.Data.Skills[key]
 		if skill.ID <= lenNames {
 			skill.Name = filterSkill(names[skill.ID-1])
 		}
@@ -504,14 +396,14 @@ func Read(root string) ([]byte, error) {
 	}
 
 	// event strings
-	names, err = ReadStringFile(filepath.Join(strRoot, "MsgEventName_en.strb"))
+	names, err = d.ReadStringFile(filepath.Join(strRoot, "MsgEventName_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
 	lenNames = len(names)
 
-	description, err = ReadStringFile(filepath.Join(strRoot, "MsgEventDesc_en.strb"))
+	description, err = d.ReadStringFile(filepath.Join(strRoot, "MsgEventDesc_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
@@ -519,18 +411,19 @@ func Read(root string) ([]byte, error) {
 	lenDescriptions = len(description)
 
 	// pad out the event list for events we have text for, but no data
-	maxEventID := MaxEventID(Data.Events)
+	maxEventID := MaxEventID(d.Data.Events)
 	if lenNames == lenDescriptions && lenNames > maxEventID {
 		for i := maxEventID; i < lenNames; i++ {
 			e := Event{
 				ID: i + 1,
 			}
-			Data.Events = append(Data.Events, e)
+			e.setDataset(d)
+			d.Data.Events = append(d.Data.Events, e)
 		}
 	}
 
-	for key := range Data.Events {
-		evnt := &Data.Events[key]
+	for key := range d.Data.Events {
+		evnt := &d.Data.Events[key]
 		if evnt.ID <= lenNames {
 			evnt.Name = filter(names[evnt.ID-1])
 		}
@@ -540,20 +433,20 @@ func Read(root string) ([]byte, error) {
 	}
 
 	// map strings
-	mapNames, err := ReadStringFile(filepath.Join(strRoot, "MsgNPCMapName_en.strb"))
+	mapNames, err := d.ReadStringFile(filepath.Join(strRoot, "MsgNPCMapName_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
 
-	mapStart, err := ReadStringFile(filepath.Join(strRoot, "MsgNPCMapStart_en.strb"))
+	mapStart, err := d.ReadStringFile(filepath.Join(strRoot, "MsgNPCMapStart_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
 
-	for key := range Data.Maps {
-		m := &Data.Maps[key]
+	for key := range d.Data.Maps {
+		m := &d.Data.Maps[key]
 		if m.ID <= len(mapNames) {
 			m.Name = mapNames[m.ID-1]
 		}
@@ -562,44 +455,44 @@ func Read(root string) ([]byte, error) {
 		}
 	}
 
-	areaName, err := ReadStringFile(filepath.Join(strRoot, "MsgNPCAreaName_en.strb"))
+	areaName, err := d.ReadStringFile(filepath.Join(strRoot, "MsgNPCAreaName_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
-	areaLongName, err := ReadStringFile(filepath.Join(strRoot, "MsgNPCAreaLongName_en.strb"))
+	areaLongName, err := d.ReadStringFile(filepath.Join(strRoot, "MsgNPCAreaLongName_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
-	areaStart, err := ReadStringFile(filepath.Join(strRoot, "MsgNPCAreaStart_en.strb"))
+	areaStart, err := d.ReadStringFile(filepath.Join(strRoot, "MsgNPCAreaStart_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
-	areaEnd, err := ReadStringFile(filepath.Join(strRoot, "MsgNPCAreaEnd_en.strb"))
+	areaEnd, err := d.ReadStringFile(filepath.Join(strRoot, "MsgNPCAreaEnd_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
-	areaStory, err := ReadStringFile(filepath.Join(strRoot, "MsgNPCAreaStory_en.strb"))
+	areaStory, err := d.ReadStringFile(filepath.Join(strRoot, "MsgNPCAreaStory_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
-	bossStart, err := ReadStringFile(filepath.Join(strRoot, "MsgNPCBossEnd_en.strb"))
+	bossStart, err := d.ReadStringFile(filepath.Join(strRoot, "MsgNPCBossEnd_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
-	bossEnd, err := ReadStringFile(filepath.Join(strRoot, "MsgNPCBossStart_en.strb"))
+	bossEnd, err := d.ReadStringFile(filepath.Join(strRoot, "MsgNPCBossStart_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
 
-	for key := range Data.Areas {
-		area := &Data.Areas[key]
+	for key := range d.Data.Areas {
+		area := &d.Data.Areas[key]
 		if area.ID <= len(bossStart) {
 			area.BossStart = filterColors(bossStart[area.ID-1])
 		}
@@ -623,47 +516,47 @@ func Read(root string) ([]byte, error) {
 		}
 	}
 
-	awlikeability, err := ReadStringFile(filepath.Join(strRoot, "MsgKingFriendshipDesc_en.strb"))
+	awlikeability, err := d.ReadStringFile(filepath.Join(strRoot, "MsgKingFriendshipDesc_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
 
 	// Archwitch Likeability
-	for key := range Data.ArchwitchFriendships {
-		awf := &Data.ArchwitchFriendships[key]
+	for key := range d.Data.ArchwitchFriendships {
+		awf := &d.Data.ArchwitchFriendships[key]
 		if awf.ID <= len(awlikeability) {
 			awf.Likability = filter(awlikeability[awf.ID-1])
 		}
 	}
 
-	kingDescription, err := ReadStringFile(filepath.Join(strRoot, "MsgKingTitle_en.strb"))
+	kingDescription, err := d.ReadStringFile(filepath.Join(strRoot, "MsgKingTitle_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
 	// king series descriptions
-	for key := range Data.ArchwitchSeries {
-		aws := &Data.ArchwitchSeries[key]
+	for key := range d.Data.ArchwitchSeries {
+		aws := &d.Data.ArchwitchSeries[key]
 		if aws.ID <= len(kingDescription) {
 			aws.Description = filter(kingDescription[aws.ID-1])
 		}
 	}
 
-	dbonusName, err := ReadStringFile(filepath.Join(strRoot, "MsgDeckBonusName_en.strb"))
+	dbonusName, err := d.ReadStringFile(filepath.Join(strRoot, "MsgDeckBonusName_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
-	dbonusDesc, err := ReadStringFile(filepath.Join(strRoot, "MsgDeckBonusDesc_en.strb"))
+	dbonusDesc, err := d.ReadStringFile(filepath.Join(strRoot, "MsgDeckBonusDesc_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
 
 	// Deck Bonuses
-	for key := range Data.DeckBonuses {
-		db := &Data.DeckBonuses[key]
+	for key := range d.Data.DeckBonuses {
+		db := &d.Data.DeckBonuses[key]
 		if db.ID <= len(dbonusName) {
 			db.Name = filter(dbonusName[db.ID-1])
 		}
@@ -673,34 +566,34 @@ func Read(root string) ([]byte, error) {
 	}
 
 	//Items
-	itemdsc, err := ReadStringFile(filepath.Join(strRoot, "MsgShopItemDesc_en.strb"))
+	itemdsc, err := d.ReadStringFile(filepath.Join(strRoot, "MsgShopItemDesc_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
-	itemdscshp, err := ReadStringFile(filepath.Join(strRoot, "MsgShopItemDescInShop_en.strb"))
+	itemdscshp, err := d.ReadStringFile(filepath.Join(strRoot, "MsgShopItemDescInShop_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
-	itemdscsub, err := ReadStringFile(filepath.Join(strRoot, "MsgShopItemDescSub_en.strb"))
+	itemdscsub, err := d.ReadStringFile(filepath.Join(strRoot, "MsgShopItemDescSub_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
-	itemname, err := ReadStringFile(filepath.Join(strRoot, "MsgShopItemName_en.strb"))
+	itemname, err := d.ReadStringFile(filepath.Join(strRoot, "MsgShopItemName_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
-	itemuse, err := ReadStringFile(filepath.Join(strRoot, "MsgShopItemUseResult_en.strb"))
+	itemuse, err := d.ReadStringFile(filepath.Join(strRoot, "MsgShopItemUseResult_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
 
-	for key := range Data.Items {
-		item := &Data.Items[key]
+	for key := range d.Data.Items {
+		item := &d.Data.Items[key]
 		if item.ID <= len(itemdsc) {
 			item.Description = filter(itemdsc[item.ID-1])
 		}
@@ -718,19 +611,19 @@ func Read(root string) ([]byte, error) {
 		}
 	}
 
-	buildname, err := ReadStringFile(filepath.Join(strRoot, "MsgBuildingName_en.strb"))
+	buildname, err := d.ReadStringFile(filepath.Join(strRoot, "MsgBuildingName_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
-	builddesc, err := ReadStringFile(filepath.Join(strRoot, "MsgBuildingDesc_en.strb"))
+	builddesc, err := d.ReadStringFile(filepath.Join(strRoot, "MsgBuildingDesc_en.strb"))
 	if err != nil {
 		debug.PrintStack()
 		return nil, err
 	}
 
-	for key := range Data.Structures {
-		s := &Data.Structures[key]
+	for key := range d.Data.Structures {
+		s := &d.Data.Structures[key]
 		if s.ID <= len(buildname) {
 			s.Name = filter(buildname[s.ID-1])
 		}
@@ -739,27 +632,27 @@ func Read(root string) ([]byte, error) {
 		}
 	}
 
-	if Data.ThorEvents != nil {
-		thorTitle, err := ReadStringFile(filepath.Join(strRoot, "MsgThorhammerTitle_en.strb"))
+	if d.Data.ThorEvents != nil {
+		thorTitle, err := d.ReadStringFile(filepath.Join(strRoot, "MsgThorhammerTitle_en.strb"))
 		if err != nil {
 			debug.PrintStack()
 			return data, err
 		}
-		for key := range Data.ThorEvents {
-			te := &Data.ThorEvents[key]
+		for key := range d.Data.ThorEvents {
+			te := &d.Data.ThorEvents[key]
 			if te.ID <= len(thorTitle) {
 				te.Title = filter(thorTitle[te.ID-1])
 			}
 		}
 	}
 
-	if Data.Weapons != nil {
-		weaponName, err := ReadStringFile(filepath.Join(strRoot, "MsgWeaponName_en.strb"))
+	if d.Data.Weapons != nil {
+		weaponName, err := d.ReadStringFile(filepath.Join(strRoot, "MsgWeaponName_en.strb"))
 		if err != nil {
 			debug.PrintStack()
 			return data, err
 		}
-		weaponDesc, err := ReadStringFile(filepath.Join(strRoot, "MsgWeaponDesc_en.strb"))
+		weaponDesc, err := d.ReadStringFile(filepath.Join(strRoot, "MsgWeaponDesc_en.strb"))
 		if err != nil {
 			debug.PrintStack()
 			return data, err
@@ -767,8 +660,8 @@ func Read(root string) ([]byte, error) {
 		lwn := len(weaponName)
 		lwd := len(weaponDesc)
 		ridx := 0
-		for key := range Data.Weapons {
-			weap := &Data.Weapons[key]
+		for key := range d.Data.Weapons {
+			weap := &d.Data.Weapons[key]
 			lastridx := ridx + weap.MaxRarity()
 			for i := ridx; i < lastridx; i++ {
 				if i < lwn {
@@ -782,34 +675,39 @@ func Read(root string) ([]byte, error) {
 		}
 	}
 
-	if Data.WeaponSkills != nil {
-		weaponSkill, err := ReadStringFile(filepath.Join(strRoot, "MsgWeaponSkillDesc_en.strb"))
+	if d.Data.WeaponSkills != nil {
+		weaponSkill, err := d.ReadStringFile(filepath.Join(strRoot, "MsgWeaponSkillDesc_en.strb"))
 		if err != nil {
 			debug.PrintStack()
 			return data, err
 		}
-		for key := range Data.WeaponSkills {
-			wskill := &Data.WeaponSkills[key]
+		for key := range d.Data.WeaponSkills {
+			wskill := &d.Data.WeaponSkills[key]
 			if wskill.ID <= len(weaponSkill) {
 				wskill.Description = filter(weaponSkill[wskill.ID-1])
 			}
 		}
 	}
 
-	if Data.WeaponEvents != nil {
-		weaponEvent, err := ReadStringFile(filepath.Join(strRoot, "MsgWeaponEventTitle_en.strb"))
+	if d.Data.WeaponEvents != nil {
+		weaponEvent, err := d.ReadStringFile(filepath.Join(strRoot, "MsgWeaponEventTitle_en.strb"))
 		if err != nil {
 			debug.PrintStack()
 			return data, err
 		}
-		for key := range Data.WeaponEvents {
-			wevent := &Data.WeaponEvents[key]
+		for key := range d.Data.WeaponEvents {
+			wevent := &d.Data.WeaponEvents[key]
 			if wevent.ID <= len(weaponEvent) {
 				wevent.Title = filter(weaponEvent[wevent.ID-1])
 			}
 		}
 	}
 
+	if err = d.readLanguages(); err != nil {
+		debug.PrintStack()
+		return data, err
+	}
+
 	return data, nil
 }
 
@@ -819,66 +717,43 @@ func ReadStringFile(fname string) ([]string, error) {
 }
 
 //ReadStringFileFilter Reads a binary string file with the strings optionally filtered
-func ReadStringFileFilter(fname string, filtered bool) ([]string, error) {
-	filename := strings.Replace(fname, "_en.strb", "_"+LangPack+".strb", 1)
+func ReadStringFileFilter(filename string, filtered bool) ([]string, error) {
 	if _, err := os.Stat(filename); os.IsNotExist(err) {
 		debug.PrintStack()
 		return nil, errors.New("no such file or directory: " + filename)
 	}
-	f, err := os.Open(filename)
+	sf, err := ReadStrb(filename)
 	if err != nil {
 		debug.PrintStack()
-		return nil, errors.New("Error opening: " + filename)
+		return nil, err
 	}
-	defer f.Close()
-
-	r := bufio.NewReader(f)
 
-	//skip the 8 byte header
-	_, err = r.Discard(8)
-	if err != nil {
-		debug.PrintStack()
-		return nil, errors.New("Error skipping the file header for file " + filename)
+	if !filtered {
+		return sf.Strings, nil
 	}
-
-	// find the "null" seperator between the binary info and the strings
-	null := []byte("null\000")
-	var line []byte
-	for {
-		if line, err = r.ReadBytes('\000'); err != nil {
-			debug.PrintStack()
-			return nil, errors.New("Error reading the file " + filename)
-		}
-		if bytes.Equal(line, null) {
-			break
-		}
-	}
-
-	//read the strings
-	ret := make([]string, 0)
-	for {
-		if line, err = r.ReadBytes('\000'); err == io.EOF {
-			break
-		}
-		if err != nil {
-			debug.PrintStack()
-			return nil, errors.New("Error reading the file " + filename)
-		}
-		// remove the null terminator
-		if filtered {
-			ret = append(ret, filter(string(line[:len(line)-1])))
-		} else {
-			ret = append(ret, string(line[:len(line)-1]))
-		}
+	ret := make([]string, 0, len(sf.Strings))
+	for _, line := range sf.Strings {
+		ret = append(ret, filter(line))
 	}
 	return ret, nil
 }
 
 var binImageCache = make(map[string][]BinImage)
+var binImageCacheLock sync.RWMutex
+
+// clearBinImageCache forgets the images read so far so updated files are read again
+func clearBinImageCache() {
+	binImageCacheLock.Lock()
+	binImageCache = make(map[string][]BinImage)
+	binImageCacheLock.Unlock()
+}
 
 //ReadBinFileImages reads a binary file and returns the image data (PNG only)
 func ReadBinFileImages(filename string) ([]BinImage, error) {
-	if cache, ok := binImageCache[filename]; ok {
+	binImageCacheLock.RLock()
+	cache, ok := binImageCache[filename]
+	binImageCacheLock.RUnlock()
+	if ok {
 		return cache, nil
 	}
 	data, err := ioutil.ReadFile(filename)
@@ -1018,47 +893,12 @@ func ReadBinFileImages(filename string) ([]BinImage, error) {
 		start = end
 	}
 	//log.Printf("found %d image names and %d images\n", lnames, len(ret))
+	binImageCacheLock.Lock()
 	binImageCache[filename] = ret
+	binImageCacheLock.Unlock()
 	return ret, nil
 }
 
-func cleanCardName(name string, card *Card) string {
-	ret := ""
-	if newName, ok := characterNameOverride[card.CardCharaID]; ok {
-		// use an overridden hard-coded name
-		ret = newName
-	} else {
-		ret = strings.ReplaceAll(strings.Title(strings.ToLower(name)), "'S", "'s")
-		ret = strings.ReplaceAll(ret, "(Sr)", "(SR)")
-		ret = strings.ReplaceAll(ret, "(Ur)", "(UR)")
-		ret = strings.ReplaceAll(ret, "(Lr)", "(LR)")
-		ret = strings.ReplaceAll(ret, "/", " ")
-		if card.CardCharaID < 1450 {
-			// use lowecase prepositions and articles as these are cards in the wiki before this program.
-			ret = strings.ReplaceAll(ret, " Of ", " of ")
-			ret = strings.ReplaceAll(ret, "-Of-", "-of-")
-			ret = strings.ReplaceAll(ret, " The ", " the ")
-			ret = strings.ReplaceAll(ret, "-The-", "-the-")
-			ret = strings.ReplaceAll(ret, " In ", " in ")
-			ret = strings.ReplaceAll(ret, "-In-", "-in-")
-			ret = strings.ReplaceAll(ret, " O'", " o'")
-			ret = strings.ReplaceAll(ret, "-O'", "-o'")
-			ret = strings.ReplaceAll(ret, " Du ", " du ") // french "of"
-		}
-	}
-	// old cards
-	if card.IsRetired() {
-		ret += " (Old)"
-	} else {
-		// new cards that are named the same as an old card that is still active
-		newIDx := sort.SearchInts(newCards, card.ID)
-		if newIDx >= 0 && newIDx < len(newCards) && newCards[newIDx] == card.ID {
-			ret += " (New)"
-		}
-	}
-	return ret
-}
-
 func cleanWeaponName(name string) string {
 	return strings.ReplaceAll(strings.Title(strings.ToLower(name)), "'S", "'s")
 }
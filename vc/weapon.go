@@ -21,6 +21,7 @@ type WeaponEvent struct {
 
 // Weapon mst_weapon_character
 type Weapon struct {
+	datasetRef
 	ID            int      `json:"_id"`
 	RarityGroupID int      `json:"rarity_group_id"`
 	RankGroupID   int      `json:"rank_group_id"`
@@ -36,10 +37,14 @@ type WeaponSkill struct {
 	Level       int    `json:"lv"`
 	Value       int    `json:"value"`
 	Description string `json:"-"` // MsgWeaponSkillDesc_en.strb
+
+	// Translations text from the string files in each loaded language pack
+	Translations Translations `json:"-"`
 }
 
 // WeaponSkillUnlockRank mst_weapon_skill_unlock_rank
 type WeaponSkillUnlockRank struct {
+	datasetRef
 	ID         int `json:"_id"`
 	WeaponID   int `json:"weapon_id"`
 	UnlockRank int `json:"unlock_rank"`
@@ -84,6 +89,7 @@ type WeaponRarity struct {
 // WeaponMaterial mst_weapon_material
 // Material item that can be applied to a weapon of a certain rank, and how much Exp the item provides
 type WeaponMaterial struct {
+	datasetRef
 	ID       int `json:"_id"`
 	WeaponID int `json:"weapon_id"`
 	Rarity   int `json:"rarity"`
@@ -104,7 +110,7 @@ type WeaponKiller struct {
 func (w *Weapon) SkillUnlocks() WeaponSkillUnlockRankList {
 	set := make(WeaponSkillUnlockRankList, 0)
 	if w != nil {
-		for _, val := range Data.WeaponSkillUnlockRanks {
+		for _, val := range w.ds.Data.WeaponSkillUnlockRanks {
 			if val.WeaponID == w.ID {
 				set = append(set, val)
 			}
@@ -117,7 +123,7 @@ func (w *Weapon) SkillUnlocks() WeaponSkillUnlockRankList {
 func (w *Weapon) UpgradeMaterials() []WeaponMaterial {
 	set := make([]WeaponMaterial, 0)
 	if w != nil {
-		for _, val := range Data.WeaponMaterials {
+		for _, val := range w.ds.Data.WeaponMaterials {
 			if val.WeaponID == w.ID {
 				set = append(set, val)
 			}
@@ -129,9 +135,9 @@ func (w *Weapon) UpgradeMaterials() []WeaponMaterial {
 // Status of the weapon
 func (w *Weapon) Status() *WeaponStatus {
 	if w != nil {
-		for i, val := range Data.WeaponStatuses {
+		for i, val := range w.ds.Data.WeaponStatuses {
 			if val.ID == w.StatusID {
-				return &Data.WeaponStatuses[i]
+				return &w.ds.Data.WeaponStatuses[i]
 			}
 		}
 	}
@@ -155,7 +161,7 @@ func (w *Weapon) StatusDescription() string {
 func (w *Weapon) Events() []WeaponEvent {
 	set := make([]WeaponEvent, 0)
 	if w != nil {
-		for _, val := range Data.WeaponEvents {
+		for _, val := range w.ds.Data.WeaponEvents {
 			if val.WeaponID == w.ID {
 				set = append(set, val)
 			}
@@ -179,7 +185,7 @@ func (w *Weapon) EventNames() []string {
 func (w *Weapon) Ranks() []WeaponRank {
 	set := make([]WeaponRank, 0)
 	if w != nil {
-		for _, val := range Data.WeaponRanks {
+		for _, val := range w.ds.Data.WeaponRanks {
 			if val.GroupID == w.RankGroupID {
 				set = append(set, val)
 			}
@@ -197,7 +203,7 @@ func (w *Weapon) MaxRank() int {
 func (w *Weapon) Rarities() []WeaponRarity {
 	set := make([]WeaponRarity, 0)
 	if w != nil {
-		for _, val := range Data.WeaponRarities {
+		for _, val := range w.ds.Data.WeaponRarities {
 			if val.GroupID == w.RarityGroupID {
 				set = append(set, val)
 			}
@@ -226,7 +232,7 @@ func (w *Weapon) MaxRarityName() string {
 		return "N/A"
 	}
 	name := w.Names[lNames-1]
-	if firstCardWithName(name) != nil {
+	if w.ds.firstCardWithName(name) != nil {
 		return name + " (Weapon)"
 	}
 	return name
@@ -235,9 +241,9 @@ func (w *Weapon) MaxRarityName() string {
 // Skill that is unlocked for a weapon's rank
 func (w *WeaponSkillUnlockRank) Skill() *WeaponSkill {
 	if w != nil {
-		for i, val := range Data.WeaponSkills {
+		for i, val := range w.ds.Data.WeaponSkills {
 			if w.SkillType == val.SkillType && w.SkillLevel == val.Level {
-				return &Data.WeaponSkills[i]
+				return &w.ds.Data.WeaponSkills[i]
 			}
 		}
 	}
@@ -290,7 +296,7 @@ func (wm *WeaponMaterial) Item() *Item {
 	if wm == nil {
 		return nil
 	}
-	return ItemScan(wm.ItemID)
+	return wm.ds.ItemScan(wm.ItemID)
 }
 
 //ArrivalRewards Arrival rewards for soul weapon events
@@ -299,9 +305,9 @@ func (we *WeaponEvent) ArrivalRewards() []RankRewardSheet {
 	if we == nil {
 		return rewards
 	}
-	for i, v := range Data.WeaponArrivalRewards {
+	for i, v := range we.ds.Data.WeaponArrivalRewards {
 		if v.GroupID == we.ArrivalRewardGroupID {
-			rewards = append(rewards, Data.WeaponArrivalRewards[i])
+			rewards = append(rewards, we.ds.Data.WeaponArrivalRewards[i])
 		}
 	}
 	return rewards
@@ -313,9 +319,9 @@ func (we *WeaponEvent) RankRewards() []RankRewardSheet {
 	if we == nil {
 		return rewards
 	}
-	for i, v := range Data.WeaponRewards {
+	for i, v := range we.ds.Data.WeaponRewards {
 		if v.GroupID == we.RankingRewardGroupID {
-			rewards = append(rewards, Data.WeaponRewards[i])
+			rewards = append(rewards, we.ds.Data.WeaponRewards[i])
 		}
 	}
 	return rewards
@@ -331,7 +337,7 @@ func (we *WeaponEvent) EventName() string {
 	if we == nil {
 		return ""
 	}
-	for _, evt := range Data.Events {
+	for _, evt := range we.ds.Data.Events {
 		if evt.WeaponEventID == we.ID {
 			return evt.Name
 		}
@@ -348,24 +354,24 @@ func (we *WeaponEvent) ScenarioHtml() (string, error) {
 }
 
 // WeaponScan searches for a weapon by ID
-func WeaponScan(id int) *Weapon {
+func (d *Dataset) WeaponScan(id int) *Weapon {
 	if id > 0 {
-		l := len(Data.Weapons)
-		i := sort.Search(l, func(i int) bool { return Data.Weapons[i].ID >= id })
-		if i >= 0 && i < l && Data.Weapons[i].ID == id {
-			return &(Data.Weapons[i])
+		l := len(d.Data.Weapons)
+		i := sort.Search(l, func(i int) bool { return d.Data.Weapons[i].ID >= id })
+		if i >= 0 && i < l && d.Data.Weapons[i].ID == id {
+			return &(d.Data.Weapons[i])
 		}
 	}
 	return nil
 }
 
 // WeaponEventScan searches for a weapon event by ID
-func WeaponEventScan(id int) *WeaponEvent {
+func (d *Dataset) WeaponEventScan(id int) *WeaponEvent {
 	if id > 0 {
-		l := len(Data.WeaponEvents)
-		i := sort.Search(l, func(i int) bool { return Data.WeaponEvents[i].ID >= id })
-		if i >= 0 && i < l && Data.WeaponEvents[i].ID == id {
-			return &(Data.WeaponEvents[i])
+		l := len(d.Data.WeaponEvents)
+		i := sort.Search(l, func(i int) bool { return d.Data.WeaponEvents[i].ID >= id })
+		if i >= 0 && i < l && d.Data.WeaponEvents[i].ID == id {
+			return &(d.Data.WeaponEvents[i])
 		}
 	}
 	return nil
@@ -387,10 +393,10 @@ func (w Weapon) ImageName(rarity int, isThumb bool) string {
 //GetImageData Gets the image data for weapons by rank
 func (w Weapon) GetImageData(isThumb bool) map[string][]byte {
 	ret := make(map[string][]byte)
-	var sdPath string = filepath.Join(FilePath, "weapon", "sd")
-	var mdPath string = filepath.Join(FilePath, "weapon", "md")
-	var hdPath string = filepath.Join(FilePath, "weapon", "hd")
-	var thumbPath string = filepath.Join(FilePath, "weapon", "thumb")
+	var sdPath string = filepath.Join(w.ds.FilePath, "weapon", "sd")
+	var mdPath string = filepath.Join(w.ds.FilePath, "weapon", "md")
+	var hdPath string = filepath.Join(w.ds.FilePath, "weapon", "hd")
+	var thumbPath string = filepath.Join(w.ds.FilePath, "weapon", "thumb")
 
 	for i := 1; i <= w.MaxRarity(); i++ {
 		fileName := w.Image(i)
@@ -1,27 +1,99 @@
 package api
 
 import (
-	"net/http"
-	"net/http/cookiejar"
+	"errors"
 	"net/url"
 	"strings"
+	"sync"
+	"time"
 )
 
 //URL root of the wiki
 var URL string = "https://valkyriecrusade.fandom.com"
 
-var client *http.Client = &http.Client{
-	Jar: getCookieJar(),
+// Wiki a wiki site the bot can work with. Page titles are given in the
+// format returned by CardNameToWiki after being unescaped, i.e. `Dark_Succubus`.
+type Wiki interface {
+	// Login logs in with the bot credentials. Any tokens are recorded in the credentials.
+	Login(creds *Credentials) error
+	// Logout forgets the login
+	Logout()
+	// LoggedIn true if Login was successful
+	LoggedIn() bool
+	// GetPage gets the latest revision of a page
+	GetPage(title string) (*Revision, error)
+	// EditPage replaces the text of a page that already exists. If base is not nil
+	// ErrEditConflict is returned when the page was changed after base was fetched.
+	EditPage(title, text, summary string, base *Revision) error
+	// CreatePage creates a page that does not exist yet. Returns ErrPageExists if it does.
+	CreatePage(title, text, summary string) error
+	// UploadFile uploads a file, replacing any existing file with the same name
+	UploadFile(name string, data []byte) error
+	// MissingPages checks which of the pages do not exist
+	MissingPages(titles []string) (map[string]bool, error)
+	// PageURL link to view a page in a browser
+	PageURL(title string) string
 }
 
-func getCookieJar() (j *cookiejar.Jar) {
-	j, _ = cookiejar.New(&cookiejar.Options{
-		PublicSuffixList: nil,
-	})
-	return
+// Site the wiki the bot works with. Defaults to the fandom wiki.
+var Site Wiki = NewMediaWiki(URL)
+
+// ErrPageNotFound returned when a page does not exist
+var ErrPageNotFound = errors.New("page not found")
+
+// ErrEditConflict returned when a page was changed on the wiki after it was fetched
+var ErrEditConflict = errors.New("edit conflict: the page was changed on the wiki after it was fetched")
+
+// ErrPageExists returned when creating a page that already exists
+var ErrPageExists = errors.New("the page already exists")
+
+// Revision a version of a page on the wiki
+type Revision struct {
+	Text string
+	// Timestamp time the revision was saved
+	Timestamp string
+	// StartTimestamp time the revision was fetched
+	StartTimestamp string
+}
+
+// EditInterval least amount of time to wait between edits and uploads
+var EditInterval time.Duration
+
+var lastEdit struct {
+	lock sync.Mutex
+	time time.Time
+}
+
+// throttleEdit waits until EditInterval has passed since the last edit
+func throttleEdit() {
+	lastEdit.lock.Lock()
+	defer lastEdit.lock.Unlock()
+	if wait := EditInterval - time.Since(lastEdit.time); wait > 0 {
+		time.Sleep(wait)
+	}
+	lastEdit.time = time.Now()
 }
 
 //CardNameToWiki Converts a card name to a wiki name. The result is safe to use in URL paths
 func CardNameToWiki(name string) string {
 	return url.QueryEscape(strings.ReplaceAll(name, " ", "_"))
 }
+
+// pageTitle converts a page name from CardNameToWiki back to a title
+func pageTitle(pageName string) string {
+	title, _ := url.QueryUnescape(pageName)
+	return title
+}
+
+// PageURL link to view a page from CardNameToWiki in a browser
+func PageURL(pageName string) string {
+	return Site.PageURL(pageTitle(pageName))
+}
+
+// ensureLogin logs into the wiki if that has not been done yet
+func ensureLogin() error {
+	if Site.LoggedIn() {
+		return nil
+	}
+	return Login()
+}
@@ -3,12 +3,12 @@ package api
 import (
 	"encoding/json"
 	"errors"
-	"io/ioutil"
 	"net/url"
 	"vc_file_grouper/wiki"
 )
 
-//EditCardPage Edits a card page
+//EditCardPage Edits a card page. If the page was fetched with GetCardPage, ErrEditConflict
+//is returned when someone else changed the page on the wiki since then.
 func EditCardPage(cp *wiki.CardPage, editSummary string) (err error) {
 	// verify basic page information is available
 	if cp == nil {
@@ -21,42 +21,68 @@ func EditCardPage(cp *wiki.CardPage, editSummary string) (err error) {
 	}
 
 	// verify we are logged into the wiki API
-	if MyCreds.LoginToken == "" {
-		err = Login()
-		if err != nil {
-			return
+	if err = ensureLogin(); err != nil {
+		return
+	}
+
+	var base *Revision
+	if cp.BaseTimestamp != "" {
+		base = &Revision{
+			Timestamp:      cp.BaseTimestamp,
+			StartTimestamp: cp.StartTimestamp,
 		}
 	}
+	throttleEdit()
+	return Site.EditPage(pageTitle(cp.PageName), cp.String(), editSummary, base)
+}
 
-	//
-	pageName, _ := url.QueryUnescape(cp.PageName)
+//EditPage replaces the text of a page that already exists
+func (m *MediaWiki) EditPage(title, text, summary string, base *Revision) error {
 	formVals := url.Values{}
-	formVals.Add("token", MyCreds.CSRFToken)
-	formVals.Add("bot", "true")
 	formVals.Add("nocreate", "true")
-	formVals.Add("title", pageName)
-	formVals.Add("summary", editSummary)
-	formVals.Add("text", cp.String())
-	resp, err := client.PostForm(URL+"/api.php?action=edit&format=json", formVals)
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return
-	}
-	er := editResponse{}
-	err = json.Unmarshal(body, &er)
-	if err != nil {
-		return
+	if base != nil {
+		formVals.Add("basetimestamp", base.Timestamp)
+		if base.StartTimestamp != "" {
+			formVals.Add("starttimestamp", base.StartTimestamp)
+		}
 	}
-	if er.Error != nil {
-		data, err := er.Error.MarshalJSON()
+	return m.edit(title, text, summary, formVals)
+}
+
+//CreatePage creates a page that does not exist yet. Returns ErrPageExists if it does.
+func (m *MediaWiki) CreatePage(title, text, summary string) error {
+	formVals := url.Values{}
+	formVals.Add("createonly", "true")
+	formVals.Add("recreate", "true")
+	return m.edit(title, text, summary, formVals)
+}
+
+func (m *MediaWiki) edit(title, text, summary string, formVals url.Values) error {
+	formVals.Set("bot", "true")
+	formVals.Set("title", title)
+	formVals.Set("summary", summary)
+	formVals.Set("text", text)
+	return m.withLogin(func(token string) (err error) {
+		// the token changes when the bot logs in again
+		formVals.Set("token", token)
+		_, body, err := m.postForm(m.apiURL("action=edit&format=json"), formVals)
 		if err != nil {
-			return err
+			return
 		}
-		return errors.New(string(data))
-	}
-	return nil
+		er := editResponse{}
+		err = json.Unmarshal(body, &er)
+		if err != nil {
+			return
+		}
+		if er.Error != nil {
+			switch er.Error.Code {
+			case "editconflict":
+				return ErrEditConflict
+			case "articleexists":
+				return ErrPageExists
+			}
+			return er.Error
+		}
+		return nil
+	})
 }
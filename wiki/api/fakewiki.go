@@ -0,0 +1,317 @@
+package api
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FakeWiki an in-process stand in for a MediaWiki site so the bot can be run and tested offline.
+// Pages and files are kept in memory, or in a folder when Dir is set so they can be looked at after a run.
+type FakeWiki struct {
+	// Dir folder to keep the pages and files in. Leave blank to only keep them in memory.
+	Dir string
+	// Users bot user names and their passwords that can log in. Any login is accepted if this is empty.
+	Users map[string]string
+
+	lock     sync.Mutex
+	loggedIn bool
+	// expired the edit token no longer works, as if the wiki session timed out
+	expired bool
+	creds   *Credentials
+	logins  int
+	pages   map[string]fakePage
+	files   map[string][]byte
+	edits   []FakeEdit
+}
+
+// fakePage a page kept in memory
+type fakePage struct {
+	text string
+	time time.Time
+}
+
+// FakeEdit a change made to the fake wiki
+type FakeEdit struct {
+	// Action "create", "edit" or "upload"
+	Action  string
+	Title   string
+	Summary string
+	Time    time.Time
+}
+
+// NewFakeWiki creates a fake wiki. If dir is not blank the pages and files are stored there.
+func NewFakeWiki(dir string) *FakeWiki {
+	return &FakeWiki{
+		Dir:   dir,
+		pages: make(map[string]fakePage),
+		files: make(map[string][]byte),
+	}
+}
+
+// normalizeTitle formats a title the way MediaWiki stores it
+func normalizeTitle(title string) string {
+	title = strings.TrimSpace(strings.ReplaceAll(title, "_", " "))
+	r, size := utf8.DecodeRuneInString(title)
+	if r == utf8.RuneError {
+		return title
+	}
+	return string(unicode.ToUpper(r)) + title[size:]
+}
+
+// Login accepts any credentials that are set, or only the configured Users
+func (f *FakeWiki) Login(creds *Credentials) error {
+	if creds.Username == "" || creds.Password == "" {
+		return errors.New("user information not setup")
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if len(f.Users) > 0 && f.Users[creds.Username] != creds.Password {
+		f.loggedIn = false
+		return errors.New("the supplied credentials could not be authenticated")
+	}
+	creds.LoginToken = "fake-login-token"
+	creds.CSRFToken = "fake-csrf-token"
+	f.loggedIn = true
+	f.expired = false
+	f.creds = creds
+	f.logins++
+	return nil
+}
+
+// ExpireSession makes the edit token stop working while the bot still thinks it is logged in,
+// the way a wiki session times out. The next edit or upload logs in again.
+func (f *FakeWiki) ExpireSession() {
+	f.lock.Lock()
+	f.expired = true
+	f.lock.Unlock()
+}
+
+// Logins the number of successful logins
+func (f *FakeWiki) Logins() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.logins
+}
+
+// checkLogin the error the wiki returns for an edit without a working login
+func (f *FakeWiki) checkLogin(info string) error {
+	if !f.loggedIn {
+		return &apiError{Code: "notloggedin", Info: info}
+	}
+	if f.expired {
+		return &apiError{Code: "badtoken", Info: "Invalid CSRF token."}
+	}
+	return nil
+}
+
+// withLogin runs an edit. If the token expired, the bot logs in again and the edit is tried once more.
+func (f *FakeWiki) withLogin(action func() error) error {
+	err := action()
+	if isBadLogin(err) {
+		f.lock.Lock()
+		creds := f.creds
+		f.lock.Unlock()
+		if creds != nil {
+			if lerr := f.Login(creds); lerr != nil {
+				return lerr
+			}
+			err = action()
+		}
+	}
+	return err
+}
+
+// Logout forgets the login
+func (f *FakeWiki) Logout() {
+	f.lock.Lock()
+	f.loggedIn = false
+	f.creds = nil
+	f.lock.Unlock()
+}
+
+// LoggedIn true if Login was successful
+func (f *FakeWiki) LoggedIn() bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.loggedIn
+}
+
+// GetPage gets the latest revision of a page. Returns ErrPageNotFound if the page does not exist.
+func (f *FakeWiki) GetPage(title string) (*Revision, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	page, ok := f.readPage(normalizeTitle(title))
+	if !ok {
+		return nil, ErrPageNotFound
+	}
+	return &Revision{
+		Text:           page.text,
+		Timestamp:      fakeTimestamp(page.time),
+		StartTimestamp: fakeTimestamp(time.Now()),
+	}, nil
+}
+
+// fakeTimestamp formats a revision time. Nanoseconds are kept so quick edits still get different timestamps.
+func fakeTimestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// SetPage stores a page without logging in or recording an edit. Used to set up the wiki content.
+func (f *FakeWiki) SetPage(title, text string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.writePage(normalizeTitle(title), text)
+}
+
+// EditPage replaces the text of a page that already exists.
+// Returns ErrEditConflict if base is not the latest revision of the page.
+func (f *FakeWiki) EditPage(title, text, summary string, base *Revision) error {
+	return f.withLogin(func() error {
+		return f.save("edit", title, text, summary, base)
+	})
+}
+
+// CreatePage creates a page that does not exist yet. Returns ErrPageExists if it does.
+func (f *FakeWiki) CreatePage(title, text, summary string) error {
+	return f.withLogin(func() error {
+		return f.save("create", title, text, summary, nil)
+	})
+}
+
+func (f *FakeWiki) save(action, title, text, summary string, base *Revision) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.checkLogin("You must be logged in to edit pages."); err != nil {
+		return err
+	}
+	title = normalizeTitle(title)
+	page, exists := f.readPage(title)
+	if action == "edit" && !exists {
+		return &apiError{Code: "missingtitle", Info: "The page you specified doesn't exist."}
+	}
+	if exists && base != nil && base.Timestamp != fakeTimestamp(page.time) {
+		return ErrEditConflict
+	}
+	if action == "create" && exists {
+		// MediaWiki.CreatePage turns the articleexists error into ErrPageExists
+		return ErrPageExists
+	}
+	if err := f.writePage(title, text); err != nil {
+		return err
+	}
+	f.edits = append(f.edits, FakeEdit{Action: action, Title: title, Summary: summary, Time: time.Now()})
+	return nil
+}
+
+// UploadFile stores a file, replacing any existing file with the same name
+func (f *FakeWiki) UploadFile(name string, data []byte) error {
+	return f.withLogin(func() error {
+		return f.upload(name, data)
+	})
+}
+
+func (f *FakeWiki) upload(name string, data []byte) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.checkLogin("You must be logged in to upload files."); err != nil {
+		return err
+	}
+	name = normalizeTitle(name)
+	if f.Dir != "" {
+		if err := writeFakeFile(filepath.Join(f.Dir, "files", url.PathEscape(name)), data); err != nil {
+			return err
+		}
+	} else {
+		f.files[name] = data
+	}
+	f.edits = append(f.edits, FakeEdit{Action: "upload", Title: name, Time: time.Now()})
+	return nil
+}
+
+// File gets an uploaded file
+func (f *FakeWiki) File(name string) ([]byte, bool) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	name = normalizeTitle(name)
+	if f.Dir != "" {
+		data, err := ioutil.ReadFile(filepath.Join(f.Dir, "files", url.PathEscape(name)))
+		return data, err == nil
+	}
+	data, ok := f.files[name]
+	return data, ok
+}
+
+// MissingPages checks which of the pages do not exist
+func (f *FakeWiki) MissingPages(titles []string) (map[string]bool, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	missing := make(map[string]bool)
+	for _, title := range titles {
+		if _, ok := f.readPage(normalizeTitle(title)); !ok {
+			missing[title] = true
+		}
+	}
+	return missing, nil
+}
+
+// PageURL link to the page. Pages kept in a folder link to the file, otherwise a fake URL is used.
+func (f *FakeWiki) PageURL(title string) string {
+	if f.Dir != "" {
+		if abs, err := filepath.Abs(f.pagePath(normalizeTitle(title))); err == nil {
+			return "file://" + filepath.ToSlash(abs)
+		}
+	}
+	return "fake:" + url.PathEscape(normalizeTitle(title))
+}
+
+// Edits the changes made to the wiki since it was created
+func (f *FakeWiki) Edits() []FakeEdit {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return append([]FakeEdit(nil), f.edits...)
+}
+
+func (f *FakeWiki) pagePath(title string) string {
+	return filepath.Join(f.Dir, "pages", url.PathEscape(title)+".wiki")
+}
+
+// readPage gets a page. Pages kept in a folder use the file time as the revision time.
+func (f *FakeWiki) readPage(title string) (fakePage, bool) {
+	if f.Dir != "" {
+		path := f.pagePath(title)
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return fakePage{}, false
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			return fakePage{}, false
+		}
+		return fakePage{text: string(data), time: fi.ModTime()}, true
+	}
+	page, ok := f.pages[title]
+	return page, ok
+}
+
+func (f *FakeWiki) writePage(title, text string) error {
+	if f.Dir != "" {
+		return writeFakeFile(f.pagePath(title), []byte(text))
+	}
+	f.pages[title] = fakePage{text: text, time: time.Now()}
+	return nil
+}
+
+func writeFakeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"vc_file_grouper/vc"
+	"vc_file_grouper/wiki"
+)
+
+const testCardPage = `{{Card
+|element = cool
+|rarity = SR
+|description = old description
+}}
+`
+
+// useFakeWiki points the package at a fake wiki for the duration of a test
+func useFakeWiki(t *testing.T, f *FakeWiki) {
+	oldSite, oldCreds := Site, MyCreds
+	Site = f
+	MyCreds = Credentials{Username: "bot", Password: "secret"}
+	t.Cleanup(func() {
+		Site, MyCreds = oldSite, oldCreds
+	})
+}
+
+func TestFakeWikiLogin(t *testing.T) {
+	f := NewFakeWiki("")
+	f.Users = map[string]string{"bot": "other"}
+	useFakeWiki(t, f)
+
+	if err := Login(); err == nil {
+		t.Errorf("Login with the wrong password should fail")
+	}
+	if Site.LoggedIn() {
+		t.Errorf("Should not be logged in after a failed login")
+	}
+
+	f.Users["bot"] = "secret"
+	if err := Login(); err != nil {
+		t.Errorf("Login returned an error: %s", err.Error())
+	}
+	if !Site.LoggedIn() || MyCreds.CSRFToken == "" {
+		t.Errorf("Login did not record the login")
+	}
+
+	Logout()
+	if Site.LoggedIn() {
+		t.Errorf("Should not be logged in after logging out")
+	}
+}
+
+func TestFakeWikiEditCardPage(t *testing.T) {
+	f := NewFakeWiki("")
+	useFakeWiki(t, f)
+	f.SetPage("Test_Card", testCardPage)
+	card := &vc.Card{Name: "Test Card"}
+
+	cardPage, raw, err := GetCardPage(card)
+	if err != nil {
+		t.Fatalf("GetCardPage returned an error: %s", err.Error())
+	}
+	if raw != testCardPage {
+		t.Errorf("Unexpected raw page: `%s`", raw)
+	}
+	if cardPage.CardInfo.Description != "old description" {
+		t.Errorf("Invalid value for Description found: `%s`", cardPage.CardInfo.Description)
+	}
+
+	cardPage.CardInfo.Description = "new description"
+	// not logged in yet, so the edit will log in first
+	if err := EditCardPage(cardPage, "update"); err != nil {
+		t.Fatalf("EditCardPage returned an error: %s", err.Error())
+	}
+	rev, _ := f.GetPage("Test Card")
+	if !strings.Contains(rev.Text, "|description = new description") {
+		t.Errorf("The page was not updated: `%s`", rev.Text)
+	}
+	edits := f.Edits()
+	if len(edits) != 1 || edits[0].Action != "edit" || edits[0].Title != "Test Card" || edits[0].Summary != "update" {
+		t.Errorf("Unexpected edits recorded: %v", edits)
+	}
+
+	missing := &vc.Card{Name: "missing card"}
+	if _, _, err := GetCardPage(missing); err != ErrPageNotFound {
+		t.Errorf("Expected ErrPageNotFound, got %v", err)
+	}
+	if err := EditCardPage(&wiki.CardPage{PageName: CardNameToWiki(missing.Name)}, ""); err == nil {
+		t.Errorf("Editing a page that does not exist should fail")
+	}
+}
+
+func TestFakeWikiEditConflict(t *testing.T) {
+	f := NewFakeWiki("")
+	useFakeWiki(t, f)
+	f.SetPage("Test Card", testCardPage)
+	card := &vc.Card{Name: "Test Card"}
+
+	cardPage, _, err := GetCardPage(card)
+	if err != nil {
+		t.Fatalf("GetCardPage returned an error: %s", err.Error())
+	}
+	if cardPage.BaseTimestamp == "" || cardPage.StartTimestamp == "" {
+		t.Errorf("GetCardPage did not record the revision timestamps")
+	}
+
+	// someone else edits the page after the bot fetched it
+	time.Sleep(time.Millisecond)
+	f.SetPage("Test Card", testCardPage+"\n[[Category:Changed]]")
+
+	cardPage.CardInfo.Description = "new description"
+	if err := EditCardPage(cardPage, "update"); err != ErrEditConflict {
+		t.Errorf("Expected ErrEditConflict, got %v", err)
+	}
+	if len(f.Edits()) != 0 {
+		t.Errorf("The conflicting edit was saved: %v", f.Edits())
+	}
+
+	// fetching the page again picks up the new revision
+	cardPage, _, _ = GetCardPage(card)
+	cardPage.CardInfo.Description = "new description"
+	if err := EditCardPage(cardPage, "update"); err != nil {
+		t.Errorf("EditCardPage returned an error: %s", err.Error())
+	}
+}
+
+func TestFakeWikiMissingPages(t *testing.T) {
+	f := NewFakeWiki(t.TempDir())
+	useFakeWiki(t, f)
+	f.SetPage("Test Card", testCardPage)
+
+	names := []string{CardNameToWiki("Test Card"), CardNameToWiki("New (Card)")}
+	missing, err := MissingPages(names)
+	if err != nil {
+		t.Fatalf("MissingPages returned an error: %s", err.Error())
+	}
+	if missing[names[0]] || !missing[names[1]] || len(missing) != 1 {
+		t.Errorf("Unexpected missing pages: %v", missing)
+	}
+
+	if err := Login(); err != nil {
+		t.Fatalf("Login returned an error: %s", err.Error())
+	}
+	if err := Site.CreatePage("Test_Card", "new text", ""); err == nil {
+		t.Errorf("Creating a page that already exists should fail")
+	}
+	if err := Site.CreatePage("New_(Card)", "new text", "create"); err != nil {
+		t.Errorf("CreatePage returned an error: %s", err.Error())
+	}
+
+	// pages kept in a folder can be read by another fake wiki
+	other := NewFakeWiki(f.Dir)
+	if rev, err := other.GetPage("New (Card)"); err != nil || rev.Text != "new text" {
+		t.Errorf("The created page was not stored on disk: %v %v", rev, err)
+	}
+}
+
+func TestFakeWikiExpiredSession(t *testing.T) {
+	f := NewFakeWiki("")
+	useFakeWiki(t, f)
+	f.SetPage("Test Card", testCardPage)
+
+	if err := Site.CreatePage("New Card", "text", ""); !isBadLogin(err) {
+		t.Errorf("Expected a notloggedin error before logging in, got %v", err)
+	}
+	if err := Login(); err != nil {
+		t.Fatalf("Login returned an error: %s", err.Error())
+	}
+	if err := Site.CreatePage("Test Card", "text", ""); err != ErrPageExists {
+		t.Errorf("Expected ErrPageExists, got %v", err)
+	}
+	if e, ok := Site.EditPage("Missing Card", "text", "", nil).(*apiError); !ok || e.Code != "missingtitle" {
+		t.Errorf("Expected a missingtitle error, got %v", e)
+	}
+
+	// the token expires while the bot still thinks it is logged in
+	f.ExpireSession()
+	if !Site.LoggedIn() {
+		t.Errorf("The bot should still think it is logged in")
+	}
+	if err := Site.EditPage("Test Card", "new text", "update", nil); err != nil {
+		t.Fatalf("EditPage did not log in again: %s", err.Error())
+	}
+	if f.Logins() != 2 {
+		t.Errorf("Expected 2 logins, got %d", f.Logins())
+	}
+	if rev, _ := f.GetPage("Test Card"); rev.Text != "new text" {
+		t.Errorf("The page was not updated after logging in again: `%s`", rev.Text)
+	}
+
+	f.ExpireSession()
+	if err := Site.UploadFile("Test.png", []byte("png")); err != nil {
+		t.Errorf("UploadFile did not log in again: %s", err.Error())
+	}
+}
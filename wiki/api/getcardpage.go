@@ -1,38 +1,63 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"net/url"
 	"vc_file_grouper/vc"
 	"vc_file_grouper/wiki"
 )
 
-//GetCardPage Gets a card page
+//GetCardPage Gets a card page. The revision timestamps are kept on the page so
+//EditCardPage can detect edits made on the wiki in the meantime.
 func GetCardPage(card *vc.Card) (ret *wiki.CardPage, raw string, err error) {
 	if card == nil || card.Name == "" {
 		return
 	}
 
 	pageName := CardNameToWiki(card.Name)
-	resp, err := client.Get(URL + "/index.php?action=raw&title=" + pageName)
+	rev, err := Site.GetPage(pageTitle(pageName))
+	if err != nil {
+		return
+	}
+	raw = rev.Text
+
+	ret = &wiki.CardPage{
+		PageName:       pageName,
+		BaseTimestamp:  rev.Timestamp,
+		StartTimestamp: rev.StartTimestamp,
+	}
+	err = ret.Parse(raw)
+	return
+}
 
+//GetPage Gets the latest revision of a page
+func (m *MediaWiki) GetPage(title string) (rev *Revision, err error) {
+	status, body, err := m.get(m.apiURL("action=query&format=json&formatversion=2&prop=revisions&rvprop=content%7Ctimestamp&rvslots=main&curtimestamp=1&titles=" + url.QueryEscape(title)))
 	if err != nil {
 		return
 	}
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		err = fmt.Errorf("invalid HTTP Status returned - %d: %s", resp.StatusCode, resp.Status)
+	if status < 200 || status >= 300 {
+		err = fmt.Errorf("invalid HTTP Status returned - %d", status)
 		return
 	}
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
+	rr := revisionsResponse{}
+	if err = json.Unmarshal(body, &rr); err != nil {
 		return
 	}
-
-	ret = &wiki.CardPage{
-		PageName: pageName,
+	if rr.Error != nil {
+		err = rr.Error
+		return
+	}
+	if len(rr.Query.Pages) == 0 || rr.Query.Pages[0].Missing || len(rr.Query.Pages[0].Revisions) == 0 {
+		err = ErrPageNotFound
+		return
+	}
+	r := rr.Query.Pages[0].Revisions[0]
+	rev = &Revision{
+		Text:           r.Slots.Main.Content,
+		Timestamp:      r.Timestamp,
+		StartTimestamp: rr.CurTimestamp,
 	}
-	raw = string(body)
-	err = ret.Parse(raw)
 	return
 }
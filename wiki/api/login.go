@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"net/url"
 	"strings"
@@ -12,73 +11,73 @@ import (
 
 //Login uses the MyCreds to perform a login.
 func Login() (err error) {
-	if MyCreds.Username == "" || MyCreds.Password == "" {
+	return Site.Login(&MyCreds)
+}
+
+//Login logs into the MediaWiki API with the bot credentials
+func (m *MediaWiki) Login(creds *Credentials) error {
+	m.loginLock.Lock()
+	defer m.loginLock.Unlock()
+	return m.login(creds)
+}
+
+// login logs in. The caller holds loginLock.
+func (m *MediaWiki) login(creds *Credentials) (err error) {
+	if creds.Username == "" || creds.Password == "" {
 		return errors.New("user information not setup")
 	}
-	if MyCreds.LoginToken == "" {
-		MyCreds.LoginToken, err = getToken("login")
-		if err != nil {
-			return
-		}
-	}
-	formVals := url.Values{}
-	formVals.Add("lgname", MyCreds.Username)
-	formVals.Add("lgpassword", MyCreds.Password)
-	formVals.Add("lgtoken", MyCreds.LoginToken)
-	resp, err := client.PostForm(URL+"/api.php?action=login&format=json", formVals)
+	m.lock.Lock()
+	m.creds = creds
+	m.lock.Unlock()
+	// login tokens are only good for one try, so always get a new one
+	loginToken, err := m.getToken("login")
+	m.setTokens(loginToken, "")
 	if err != nil {
-		MyCreds.LoginToken = ""
-		MyCreds.CSRFToken = ""
-		client.Jar = getCookieJar()
 		return
 	}
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
+	formVals := url.Values{}
+	formVals.Add("lgname", creds.Username)
+	formVals.Add("lgpassword", creds.Password)
+	formVals.Add("lgtoken", loginToken)
+	status, body, err := m.postForm(m.apiURL("action=login&format=json"), formVals)
 	if err != nil {
-		MyCreds.LoginToken = ""
-		MyCreds.CSRFToken = ""
-		client.Jar = getCookieJar()
+		m.resetLogin()
 		return
 	}
 	log.Println(string(body))
-	if resp.StatusCode != 200 {
-		MyCreds.LoginToken = ""
-		MyCreds.CSRFToken = ""
-		return fmt.Errorf("invalid response. Expected HTTP 200, instead got %d", resp.StatusCode)
+	if status != 200 {
+		m.setTokens("", "")
+		return fmt.Errorf("invalid response. Expected HTTP 200, instead got %d", status)
 	}
 	lr := loginResponse{}
 	err = json.Unmarshal(body, &lr)
 	if err != nil {
-		MyCreds.LoginToken = ""
-		MyCreds.CSRFToken = ""
-		client.Jar = getCookieJar()
+		m.resetLogin()
 		return
 	}
 	if strings.ToLower(lr.Login.Result) != "success" {
-		MyCreds.LoginToken = ""
-		MyCreds.CSRFToken = ""
-		client.Jar = getCookieJar()
+		m.resetLogin()
 		return errors.New(lr.Login.Reason)
 	}
 
-	MyCreds.CSRFToken, err = getToken("csrf")
+	csrfToken, err := m.getToken("csrf")
 	if err != nil {
-		MyCreds.LoginToken = ""
-		MyCreds.CSRFToken = ""
-		client.Jar = getCookieJar()
+		m.resetLogin()
 		return
 	}
+	m.setTokens(loginToken, csrfToken)
 	return
 }
 
+// resetLogin forgets the tokens and session cookies of a failed login
+func (m *MediaWiki) resetLogin() {
+	m.setTokens("", "")
+	m.jar.reset()
+}
+
 //getToken Gets a token so a login can happen. Records the token in the Credentials
-func getToken(tokenType string) (token string, err error) {
-	resp, err := client.Get(URL + "/api.php?action=query&meta=tokens&type=" + tokenType + "&format=json")
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
+func (m *MediaWiki) getToken(tokenType string) (token string, err error) {
+	_, body, err := m.get(m.apiURL("action=query&meta=tokens&type=" + tokenType + "&format=json"))
 	if err != nil {
 		return
 	}
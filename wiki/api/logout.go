@@ -4,8 +4,19 @@ import "net/url"
 
 //Logout Removes authentication tokens
 func Logout() {
-	client.Get(URL + "api.php?action=logout&token=" + url.QueryEscape(MyCreds.LoginToken))
-	MyCreds.CSRFToken = ""
-	MyCreds.LoginToken = ""
-	client.Jar = getCookieJar()
+	Site.Logout()
+}
+
+//Logout Removes authentication tokens
+func (m *MediaWiki) Logout() {
+	m.loginLock.Lock()
+	defer m.loginLock.Unlock()
+	m.lock.RLock()
+	loggedIn := m.creds != nil
+	m.lock.RUnlock()
+	if !loggedIn {
+		return
+	}
+	m.client.Get(m.URL + "/api.php?action=logout&token=" + url.QueryEscape(m.csrfToken()))
+	m.resetLogin()
 }
@@ -0,0 +1,260 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MediaWiki a wiki that is accessed through the MediaWiki API, like the fandom wiki
+type MediaWiki struct {
+	// URL root of the wiki
+	URL string
+	// MaxLag seconds of database replication lag after which the wiki should refuse our requests.
+	// See https://www.mediawiki.org/wiki/Manual:Maxlag_parameter
+	MaxLag int
+	// MaxRetries number of times a request is tried again when the wiki is busy
+	MaxRetries int
+	client     *http.Client
+	// jar the session cookies, emptied when the login is reset
+	jar *sessionJar
+
+	// lock guards creds and the tokens recorded in them
+	lock  sync.RWMutex
+	creds *Credentials
+	// loginLock makes sure only one login runs at a time
+	loginLock sync.Mutex
+}
+
+// NewMediaWiki creates a client for the MediaWiki site at the root URL
+func NewMediaWiki(rootURL string) *MediaWiki {
+	jar := &sessionJar{jar: getCookieJar()}
+	return &MediaWiki{
+		URL:        rootURL,
+		MaxLag:     5,
+		MaxRetries: 5,
+		client: &http.Client{
+			Jar: jar,
+		},
+		jar: jar,
+	}
+}
+
+func getCookieJar() (j *cookiejar.Jar) {
+	j, _ = cookiejar.New(&cookiejar.Options{
+		PublicSuffixList: nil,
+	})
+	return
+}
+
+// sessionJar a cookie jar that can be emptied while other requests are using it
+type sessionJar struct {
+	lock sync.RWMutex
+	jar  http.CookieJar
+}
+
+func (j *sessionJar) current() http.CookieJar {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	return j.jar
+}
+
+// SetCookies records the cookies of a response
+func (j *sessionJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.current().SetCookies(u, cookies)
+}
+
+// Cookies the cookies to send with a request
+func (j *sessionJar) Cookies(u *url.URL) []*http.Cookie {
+	return j.current().Cookies(u)
+}
+
+// reset forgets all the cookies
+func (j *sessionJar) reset() {
+	j.lock.Lock()
+	j.jar = getCookieJar()
+	j.lock.Unlock()
+}
+
+// LoggedIn true if Login was successful
+func (m *MediaWiki) LoggedIn() bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.creds != nil && m.creds.LoginToken != ""
+}
+
+// csrfToken the edit token from the last login
+func (m *MediaWiki) csrfToken() string {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	if m.creds == nil {
+		return ""
+	}
+	return m.creds.CSRFToken
+}
+
+// setTokens records the login and edit tokens in the credentials
+func (m *MediaWiki) setTokens(loginToken, csrfToken string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.creds != nil {
+		m.creds.LoginToken = loginToken
+		m.creds.CSRFToken = csrfToken
+	}
+}
+
+// PageURL link to view a page in a browser
+func (m *MediaWiki) PageURL(title string) string {
+	return m.URL + "/wiki/" + url.PathEscape(title)
+}
+
+// apiURL the URL of an API action with the maxlag parameter added
+func (m *MediaWiki) apiURL(query string) string {
+	ret := m.URL + "/api.php?" + query
+	if m.MaxLag > 0 {
+		ret += "&maxlag=" + strconv.Itoa(m.MaxLag)
+	}
+	return ret
+}
+
+// apiError an error returned by the MediaWiki API
+type apiError struct {
+	Code string `json:"code"`
+	Info string `json:"info"`
+}
+
+func (e *apiError) Error() string {
+	return e.Code + ": " + e.Info
+}
+
+// responseError gets the error from an API response, or nil if there is none
+func responseError(body []byte) *apiError {
+	er := struct {
+		Error *apiError `json:"error"`
+	}{}
+	if json.Unmarshal(body, &er) != nil {
+		return nil
+	}
+	return er.Error
+}
+
+// call sends the request made by newRequest. Requests are sent again when the wiki asks us to
+// slow down, either with a maxlag error or with a 429/503 status, waiting for the Retry-After time.
+// newRequest is called for each try since request bodies can only be read once.
+func (m *MediaWiki) call(newRequest func() (*http.Request, error)) (status int, body []byte, err error) {
+	for try := 0; ; try++ {
+		var req *http.Request
+		if req, err = newRequest(); err != nil {
+			return
+		}
+		var resp *http.Response
+		if resp, err = m.client.Do(req); err != nil {
+			return
+		}
+		body, err = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return
+		}
+		status = resp.StatusCode
+
+		busy := status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable
+		if e := responseError(body); e != nil && (e.Code == "maxlag" || e.Code == "ratelimited") {
+			busy = true
+		}
+		if !busy {
+			return
+		}
+		if try >= m.MaxRetries {
+			err = fmt.Errorf("the wiki is busy, gave up after %d tries", try+1)
+			return
+		}
+		wait := retryAfter(resp)
+		log.Printf("The wiki is busy (HTTP %d), trying again in %s", status, wait)
+		time.Sleep(wait)
+	}
+}
+
+// retryAfter how long the wiki asked us to wait before sending another request
+func retryAfter(resp *http.Response) time.Duration {
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			if d := time.Until(t); d > 0 {
+				return d
+			}
+			return 0
+		}
+	}
+	return 5 * time.Second
+}
+
+// get sends a GET request to the wiki
+func (m *MediaWiki) get(u string) (int, []byte, error) {
+	return m.call(func() (*http.Request, error) {
+		return http.NewRequest("GET", u, nil)
+	})
+}
+
+// postForm sends a form to the wiki
+func (m *MediaWiki) postForm(u string, formVals url.Values) (int, []byte, error) {
+	return m.call(func() (*http.Request, error) {
+		req, err := http.NewRequest("POST", u, strings.NewReader(formVals.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req, nil
+	})
+}
+
+// isBadLogin true if the error means the login or the edit token expired
+func isBadLogin(err error) bool {
+	if e, ok := err.(*apiError); ok {
+		switch e.Code {
+		case "badtoken", "notloggedin", "assertuserfailed", "assertbotfailed":
+			return true
+		}
+	}
+	return false
+}
+
+// withLogin runs an action with the edit token. If the token expired, the bot logs in again and the action
+// is tried once more. When several actions find the token expired at the same time, only the first one logs in again.
+func (m *MediaWiki) withLogin(action func(token string) error) error {
+	token := m.csrfToken()
+	err := action(token)
+	if !isBadLogin(err) {
+		return err
+	}
+	m.loginLock.Lock()
+	m.lock.RLock()
+	creds := m.creds
+	m.lock.RUnlock()
+	if creds == nil {
+		m.loginLock.Unlock()
+		return err
+	}
+	// another action may have logged in again while this one was waiting
+	if m.csrfToken() == token {
+		log.Printf("The wiki login expired (%s), logging in again", err.Error())
+		m.resetLogin()
+		if lerr := m.login(creds); lerr != nil {
+			m.loginLock.Unlock()
+			return lerr
+		}
+	}
+	token = m.csrfToken()
+	m.loginLock.Unlock()
+	return action(token)
+}
@@ -0,0 +1,287 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testRevisionResponse = `{"curtimestamp":"2020-01-02T00:00:00Z","query":{"pages":[{"pageid":1,"title":"Test Card","revisions":[{"timestamp":"2020-01-01T00:00:00Z","slots":{"main":{"content":"page text"}}}]}]}}`
+
+// testWikiServer a MediaWiki API stand in that answers with the responses of a handler function
+type testWikiServer struct {
+	lock     sync.Mutex
+	requests []*http.Request
+	forms    []map[string]string
+}
+
+// newTestMediaWiki starts a test server and a client for it
+func newTestMediaWiki(t *testing.T, handler func(s *testWikiServer, w http.ResponseWriter, r *http.Request)) (*MediaWiki, *testWikiServer) {
+	s := &testWikiServer{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form := make(map[string]string)
+		for k := range r.PostForm {
+			form[k] = r.PostForm.Get(k)
+		}
+		s.lock.Lock()
+		s.requests = append(s.requests, r)
+		s.forms = append(s.forms, form)
+		s.lock.Unlock()
+		handler(s, w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return NewMediaWiki(srv.URL), s
+}
+
+// count the number of requests for an API action
+func (s *testWikiServer) count(action string) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	n := 0
+	for _, r := range s.requests {
+		if r.URL.Query().Get("action") == action {
+			n++
+		}
+	}
+	return n
+}
+
+// lastForm the form of the last request for an API action
+func (s *testWikiServer) lastForm(action string) map[string]string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for i := len(s.requests) - 1; i >= 0; i-- {
+		if s.requests[i].URL.Query().Get("action") == action {
+			return s.forms[i]
+		}
+	}
+	return nil
+}
+
+func TestMediaWikiRetryBusy(t *testing.T) {
+	for _, status := range []int{http.StatusServiceUnavailable, http.StatusTooManyRequests} {
+		m, s := newTestMediaWiki(t, func(s *testWikiServer, w http.ResponseWriter, r *http.Request) {
+			if s.count("query") <= 2 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(status)
+				return
+			}
+			io.WriteString(w, testRevisionResponse)
+		})
+		rev, err := m.GetPage("Test Card")
+		if err != nil {
+			t.Fatalf("HTTP %d: GetPage returned an error: %s", status, err.Error())
+		}
+		if rev.Text != "page text" || rev.Timestamp != "2020-01-01T00:00:00Z" {
+			t.Errorf("HTTP %d: unexpected revision %v", status, rev)
+		}
+		if n := s.count("query"); n != 3 {
+			t.Errorf("HTTP %d: expected 3 requests, got %d", status, n)
+		}
+	}
+}
+
+func TestMediaWikiMaxlag(t *testing.T) {
+	m, s := newTestMediaWiki(t, func(s *testWikiServer, w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("maxlag") != "5" {
+			t.Errorf("The maxlag parameter was not sent: %s", r.URL.RawQuery)
+		}
+		if s.count("query") == 1 {
+			w.Header().Set("Retry-After", "0")
+			io.WriteString(w, `{"error":{"code":"maxlag","info":"Waiting for a database server: 7 seconds lagged."}}`)
+			return
+		}
+		io.WriteString(w, testRevisionResponse)
+	})
+	if _, err := m.GetPage("Test Card"); err != nil {
+		t.Fatalf("GetPage returned an error: %s", err.Error())
+	}
+	if n := s.count("query"); n != 2 {
+		t.Errorf("Expected 2 requests, got %d", n)
+	}
+}
+
+func TestMediaWikiRetryCap(t *testing.T) {
+	m, s := newTestMediaWiki(t, func(s *testWikiServer, w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	m.MaxRetries = 2
+	if _, err := m.GetPage("Test Card"); err == nil {
+		t.Errorf("GetPage should give up when the wiki stays busy")
+	}
+	if n := s.count("query"); n != 3 {
+		t.Errorf("Expected 3 requests, got %d", n)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		header string
+		min    time.Duration
+		max    time.Duration
+	}{
+		{"", 5 * time.Second, 5 * time.Second},
+		{"3", 3 * time.Second, 3 * time.Second},
+		{"soon", 5 * time.Second, 5 * time.Second},
+		{time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), 0, 0},
+		{time.Now().Add(time.Minute).UTC().Format(http.TimeFormat), 50 * time.Second, time.Minute},
+	}
+	for _, test := range tests {
+		resp := &http.Response{Header: http.Header{}}
+		if test.header != "" {
+			resp.Header.Set("Retry-After", test.header)
+		}
+		if d := retryAfter(resp); d < test.min || d > test.max {
+			t.Errorf("Retry-After %q: expected a wait between %s and %s, got %s", test.header, test.min, test.max, d)
+		}
+	}
+}
+
+// loginHandler answers token and login requests, handing out a new csrf token on each login
+func loginHandler(s *testWikiServer, w http.ResponseWriter, r *http.Request) bool {
+	switch r.URL.Query().Get("action") {
+	case "query":
+		if r.URL.Query().Get("type") == "csrf" {
+			io.WriteString(w, `{"query":{"tokens":{"csrftoken":"csrf-`+strconv.Itoa(s.count("login"))+`"}}}`)
+		} else {
+			io.WriteString(w, `{"query":{"tokens":{"logintoken":"login-token"}}}`)
+		}
+		return true
+	case "login":
+		io.WriteString(w, `{"login":{"result":"Success"}}`)
+		return true
+	}
+	return false
+}
+
+func TestMediaWikiLoginAgain(t *testing.T) {
+	for _, code := range []string{"notloggedin", "badtoken"} {
+		m, s := newTestMediaWiki(t, func(s *testWikiServer, w http.ResponseWriter, r *http.Request) {
+			if loginHandler(s, w, r) {
+				return
+			}
+			if s.count("edit") == 1 {
+				io.WriteString(w, `{"error":{"code":"`+code+`","info":"The session expired."}}`)
+				return
+			}
+			io.WriteString(w, `{"edit":{"result":"Success","title":"Test Card"}}`)
+		})
+		if err := m.Login(&Credentials{Username: "bot", Password: "secret"}); err != nil {
+			t.Fatalf("%s: Login returned an error: %s", code, err.Error())
+		}
+		if err := m.EditPage("Test Card", "new text", "update", nil); err != nil {
+			t.Fatalf("%s: EditPage returned an error: %s", code, err.Error())
+		}
+		if n := s.count("login"); n != 2 {
+			t.Errorf("%s: expected 2 logins, got %d", code, n)
+		}
+		if n := s.count("edit"); n != 2 {
+			t.Errorf("%s: expected 2 edit requests, got %d", code, n)
+		}
+		if token := s.lastForm("edit")["token"]; token != "csrf-2" {
+			t.Errorf("%s: the edit was not sent again with the new token: %s", code, token)
+		}
+	}
+}
+
+// TestMediaWikiLoginAgainConcurrent two edits find the session expired at the same time, only one of them logs in
+// again. Run it with -race to check the credentials and cookies are guarded.
+func TestMediaWikiLoginAgainConcurrent(t *testing.T) {
+	arrived := make(chan struct{}, 2)
+	release := make(chan struct{})
+	m, s := newTestMediaWiki(t, func(s *testWikiServer, w http.ResponseWriter, r *http.Request) {
+		if loginHandler(s, w, r) {
+			return
+		}
+		if r.PostForm.Get("token") == "csrf-1" {
+			// hold the expired edits until both of them were sent
+			select {
+			case arrived <- struct{}{}:
+			default:
+			}
+			<-release
+			io.WriteString(w, `{"error":{"code":"badtoken","info":"The session expired."}}`)
+			return
+		}
+		io.WriteString(w, `{"edit":{"result":"Success","title":"Test Card"}}`)
+	})
+	if err := m.Login(&Credentials{Username: "bot", Password: "secret"}); err != nil {
+		t.Fatalf("Login returned an error: %s", err.Error())
+	}
+
+	errs := make(chan error, 2)
+	for _, page := range []string{"Test Card", "Other Card"} {
+		go func(page string) {
+			errs <- m.EditPage(page, "new text", "update", nil)
+		}(page)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-arrived:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Both edits were not sent with the expired token")
+		}
+	}
+	close(release)
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("EditPage returned an error: %s", err.Error())
+		}
+	}
+	if n := s.count("login"); n != 2 {
+		t.Errorf("Expected 2 logins, got %d", n)
+	}
+	if n := s.count("edit"); n != 4 {
+		t.Errorf("Expected 4 edit requests, got %d", n)
+	}
+	if !m.LoggedIn() || m.csrfToken() != "csrf-2" {
+		t.Errorf("Expected to be logged in with the new token, got %q", m.csrfToken())
+	}
+}
+
+func TestMediaWikiEditConflict(t *testing.T) {
+	m, s := newTestMediaWiki(t, func(s *testWikiServer, w http.ResponseWriter, r *http.Request) {
+		if loginHandler(s, w, r) {
+			return
+		}
+		io.WriteString(w, `{"error":{"code":"editconflict","info":"Edit conflict."}}`)
+	})
+	if err := m.Login(&Credentials{Username: "bot", Password: "secret"}); err != nil {
+		t.Fatalf("Login returned an error: %s", err.Error())
+	}
+	base := &Revision{Timestamp: "2020-01-01T00:00:00Z", StartTimestamp: "2020-01-02T00:00:00Z"}
+	if err := m.EditPage("Test Card", "new text", "update", base); err != ErrEditConflict {
+		t.Errorf("Expected ErrEditConflict, got %v", err)
+	}
+	form := s.lastForm("edit")
+	if form["basetimestamp"] != base.Timestamp || form["starttimestamp"] != base.StartTimestamp || form["nocreate"] != "true" {
+		t.Errorf("The revision timestamps were not sent: %v", form)
+	}
+	if n := s.count("login"); n != 1 {
+		t.Errorf("An edit conflict should not log in again, got %d logins", n)
+	}
+}
+
+func TestMediaWikiCreateExists(t *testing.T) {
+	m, s := newTestMediaWiki(t, func(s *testWikiServer, w http.ResponseWriter, r *http.Request) {
+		if loginHandler(s, w, r) {
+			return
+		}
+		io.WriteString(w, `{"error":{"code":"articleexists","info":"The article you tried to create has been created already."}}`)
+	})
+	if err := m.Login(&Credentials{Username: "bot", Password: "secret"}); err != nil {
+		t.Fatalf("Login returned an error: %s", err.Error())
+	}
+	if err := m.CreatePage("Test Card", "new text", "create"); err != ErrPageExists {
+		t.Errorf("Expected ErrPageExists, got %v", err)
+	}
+	if form := s.lastForm("edit"); form["createonly"] != "true" {
+		t.Errorf("The page was not created with createonly: %v", form)
+	}
+}
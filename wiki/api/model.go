@@ -32,5 +32,44 @@ type editResponse struct {
 		NewRevID     int    `json:"newrevid"`
 		NewTimestamp string `json:"newtimestamp"`
 	} `json:"edit"`
+	Error *apiError `json:"error"`
+}
+
+// {"batchcomplete":true,"query":{"normalized":[{"fromencoded":false,"from":"a_b","to":"A b"}],"pages":[{"ns":0,"title":"A b","missing":true}]}}
+type queryPagesResponse struct {
+	Query struct {
+		Normalized []struct {
+			From string `json:"from"`
+			To   string `json:"to"`
+		} `json:"normalized"`
+		Pages []struct {
+			PageID  int    `json:"pageid"`
+			Title   string `json:"title"`
+			Missing bool   `json:"missing"`
+			Invalid bool   `json:"invalid"`
+		} `json:"pages"`
+	} `json:"query"`
 	Error json.RawMessage `json:"error"`
 }
+
+// {"batchcomplete":true,"curtimestamp":"2021-01-02T03:04:05Z","query":{"pages":[{"pageid":1,"ns":0,"title":"A b","revisions":[{"timestamp":"2021-01-01T00:00:00Z","slots":{"main":{"contentmodel":"wikitext","contentformat":"text/x-wiki","content":"..."}}}]}]}}
+type revisionsResponse struct {
+	CurTimestamp string `json:"curtimestamp"`
+	Query        struct {
+		Pages []struct {
+			PageID    int    `json:"pageid"`
+			Title     string `json:"title"`
+			Missing   bool   `json:"missing"`
+			Invalid   bool   `json:"invalid"`
+			Revisions []struct {
+				Timestamp string `json:"timestamp"`
+				Slots     struct {
+					Main struct {
+						Content string `json:"content"`
+					} `json:"main"`
+				} `json:"slots"`
+			} `json:"revisions"`
+		} `json:"pages"`
+	} `json:"query"`
+	Error *apiError `json:"error"`
+}
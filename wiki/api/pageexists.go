@@ -0,0 +1,82 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// maxTitlesPerQuery the most page titles the wiki API accepts in a single query
+const maxTitlesPerQuery = 50
+
+// MissingPages checks which of the pages do not exist on the wiki yet.
+// Page names are expected in the format returned by CardNameToWiki.
+func MissingPages(pageNames []string) (map[string]bool, error) {
+	titles := make([]string, 0, len(pageNames))
+	for _, pageName := range pageNames {
+		titles = append(titles, pageTitle(pageName))
+	}
+	missingTitles, err := Site.MissingPages(titles)
+	if err != nil {
+		return nil, err
+	}
+	missing := make(map[string]bool, len(missingTitles))
+	for _, pageName := range pageNames {
+		if missingTitles[pageTitle(pageName)] {
+			missing[pageName] = true
+		}
+	}
+	return missing, nil
+}
+
+// MissingPages checks which of the pages do not exist
+func (m *MediaWiki) MissingPages(titles []string) (missing map[string]bool, err error) {
+	missing = make(map[string]bool)
+	for start := 0; start < len(titles); start += maxTitlesPerQuery {
+		end := start + maxTitlesPerQuery
+		if end > len(titles) {
+			end = len(titles)
+		}
+		if err = m.missingPages(titles[start:end], missing); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+func (m *MediaWiki) missingPages(titleList []string, missing map[string]bool) error {
+	// the wiki returns the titles as they were given to it, or as a normalized title
+	titles := make(map[string]string, len(titleList))
+	for _, title := range titleList {
+		titles[title] = title
+	}
+
+	formVals := url.Values{}
+	formVals.Add("titles", strings.Join(titleList, "|"))
+	status, body, err := m.postForm(m.apiURL("action=query&format=json&formatversion=2"), formVals)
+	if err != nil {
+		return err
+	}
+	if status < 200 || status >= 300 {
+		return fmt.Errorf("invalid HTTP Status returned - %d", status)
+	}
+	qr := queryPagesResponse{}
+	if err = json.Unmarshal(body, &qr); err != nil {
+		return err
+	}
+	if qr.Error != nil {
+		return fmt.Errorf("%s", string(qr.Error))
+	}
+	for _, n := range qr.Query.Normalized {
+		if title, ok := titles[n.From]; ok {
+			titles[n.To] = title
+		}
+	}
+	for _, p := range qr.Query.Pages {
+		if title, ok := titles[p.Title]; ok && (p.Missing || p.Invalid) {
+			missing[title] = true
+		}
+	}
+	return nil
+}
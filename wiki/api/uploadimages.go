@@ -3,7 +3,6 @@ package api
 import (
 	"bytes"
 	"io"
-	"io/ioutil"
 	"log"
 	"mime/multipart"
 	"net/http"
@@ -17,11 +16,8 @@ func UploadNewCardUniqueImages(card *vc.Card) (err error) {
 		return
 	}
 
-	if MyCreds.LoginToken == "" {
-		err = Login()
-		if err != nil {
-			return
-		}
+	if err = ensureLogin(); err != nil {
+		return
 	}
 
 	// upload full card images
@@ -46,14 +42,25 @@ func uploadImages(card *vc.Card, thumbs bool) (err error) {
 		if err != nil {
 			return
 		}
+		throttleEdit()
+		if err = Site.UploadFile(name, data); err != nil {
+			return
+		}
+	}
+	return
+}
 
+//UploadFile uploads a file, replacing any existing file with the same name
+func (m *MediaWiki) UploadFile(name string, data []byte) error {
+	return m.withLogin(func(token string) (err error) {
+		// the token changes when the bot logs in again, so the form is built for each try
 		var formData bytes.Buffer
 		w := multipart.NewWriter(&formData)
 		err = w.WriteField("filename", name)
 		if err != nil {
 			return
 		}
-		err = w.WriteField("token", MyCreds.CSRFToken)
+		err = w.WriteField("token", token)
 		if err != nil {
 			return
 		}
@@ -70,20 +77,23 @@ func uploadImages(card *vc.Card, thumbs bool) (err error) {
 
 		//log.Println(string(formData))
 
-		var resp *http.Response
-		resp, err = client.Post(URL+"/api.php?action=upload&format=json&ignorewarnings=true", contentType, bytes.NewReader(formData.Bytes()))
-		if err != nil {
-			return
-		}
-		defer resp.Body.Close()
-		var body []byte
-		body, err = ioutil.ReadAll(resp.Body)
+		_, body, err := m.call(func() (*http.Request, error) {
+			req, err := http.NewRequest("POST", m.apiURL("action=upload&format=json&ignorewarnings=true"), bytes.NewReader(formData.Bytes()))
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Content-Type", contentType)
+			return req, nil
+		})
 		if err != nil {
 			return
 		}
 		log.Println(string(body))
-	}
-	return
+		if e := responseError(body); e != nil {
+			return e
+		}
+		return
+	})
 }
 
 func createMultiPartFormFile(w *multipart.Writer, data []byte, key, fileName string) (err error) {
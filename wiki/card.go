@@ -451,9 +451,9 @@ func getAwakeningMaterials(c *vc.Card) []AwakenMaterial {
 	for _, evo := range c.GetEvolutionCards() {
 		if evo.EvoIsAwoken() {
 			var awakenInfo *vc.CardAwaken
-			for idx, val := range vc.Data.Awakenings {
+			for idx, val := range evo.Dataset().Data.Awakenings {
 				if evo.ID == val.ResultCardID && val.IsClosed == 0 {
-					awakenInfo = &vc.Data.Awakenings[idx]
+					awakenInfo = &evo.Dataset().Data.Awakenings[idx]
 					break
 				}
 			}
@@ -516,9 +516,9 @@ func getRebirthMaterials(c *vc.Card) []RebirthMaterial {
 	for _, evo := range c.GetEvolutionCards() {
 		if evo.EvoIsReborn() {
 			var awakenInfo *vc.CardAwaken
-			for idx, val := range vc.Data.Rebirths {
+			for idx, val := range evo.Dataset().Data.Rebirths {
 				if evo.ID == val.ResultCardID && val.IsClosed == 0 {
-					awakenInfo = &vc.Data.Rebirths[idx]
+					awakenInfo = &evo.Dataset().Data.Rebirths[idx]
 					break
 				}
 			}
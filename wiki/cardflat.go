@@ -526,9 +526,9 @@ func (c *CardFlat) UpdateAwakenRebirthInfo(evolutions map[string]*vc.Card) {
 
 	if gevo != nil {
 		var awakenInfo *vc.CardAwaken
-		for idx, val := range vc.Data.Awakenings {
+		for idx, val := range gevo.Dataset().Data.Awakenings {
 			if gevo.ID == val.ResultCardID {
-				awakenInfo = &vc.Data.Awakenings[idx]
+				awakenInfo = &gevo.Dataset().Data.Awakenings[idx]
 				break
 			}
 		}
@@ -562,9 +562,9 @@ func (c *CardFlat) UpdateAwakenRebirthInfo(evolutions map[string]*vc.Card) {
 	}
 	if xevo != nil {
 		var rebirthInfo *vc.CardAwaken
-		for idx, val := range vc.Data.Rebirths {
+		for idx, val := range xevo.Dataset().Data.Rebirths {
 			if xevo.ID == val.ResultCardID {
-				rebirthInfo = &vc.Data.Rebirths[idx]
+				rebirthInfo = &xevo.Dataset().Data.Rebirths[idx]
 				break
 			}
 		}
@@ -665,7 +665,7 @@ func (c *CardFlat) UpdateSkills(evolutions map[string]*vc.Card) {
 			if s.EffectID == 36 {
 				// Random Skill
 				for i, v := range []int{s.EffectParam, s.EffectParam2, s.EffectParam3, s.EffectParam4, s.EffectParam5} {
-					sr := vc.SkillScan(v)
+					sr := s.Dataset().SkillScan(v)
 					if sr != nil {
 						rs.FieldByName(skillPrefix + "Random" + strconv.Itoa(i+1)).SetString(cleanVal(sr.FireMin()))
 					}
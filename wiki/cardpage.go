@@ -12,6 +12,10 @@ type CardPage struct {
 	CardInfo   CardFlat
 	PageHeader string
 	PageFooter string
+	// BaseTimestamp time the fetched revision of the page was saved. Used to detect edit conflicts.
+	BaseTimestamp string
+	// StartTimestamp time the page was fetched. Used to detect edit conflicts.
+	StartTimestamp string
 }
 
 func (c *CardPage) String() (ret string) {
@@ -83,7 +83,7 @@ func getSkills(c *vc.Card, evoKey string, tSkillsSeen *tmpSkillsSeen) []CardSkil
 		if s.EffectID == 36 {
 			// Random Skill
 			for _, v := range []int{s.EffectParam, s.EffectParam2, s.EffectParam3, s.EffectParam4, s.EffectParam5} {
-				rs := vc.SkillScan(v)
+				rs := s.Dataset().SkillScan(v)
 				if rs != nil {
 					tSkill.RandomSkills = append(tSkill.RandomSkills, rs.FireMin())
 				}